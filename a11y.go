@@ -0,0 +1,60 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlImgTagReg = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	htmlAltReg    = regexp.MustCompile(`(?i)\balt\s*=\s*["']\s*\S`)
+
+	vagueLinkText = map[string]bool{
+		"click here": true, "here": true, "this": true, "link": true,
+		"this link": true, "read more": true, "more": true, "click": true,
+		"this page": true, "go": true,
+	}
+)
+
+// AccessibilityLinter checks for images without alt text, links with
+// low-information text like "click here", and skipped heading levels.
+var AccessibilityLinter = LinterFunc(accessibilityLint)
+
+func accessibilityLint(content string) []LintWarning {
+	var warnings []LintWarning
+	lines, _ := markdownLines(content)
+
+	lastLevel := 0
+	for _, l := range lines {
+		if l.code {
+			continue
+		}
+		for _, m := range inlineLink.FindAllStringSubmatch(l.text, -1) {
+			text := strings.TrimSpace(m[1])
+			if strings.HasPrefix(m[0], "!") {
+				if text == "" {
+					warnings = append(warnings, LintWarning{l.num, "image-alt", fmt.Sprintf("Image %s has no alt text.", m[2])})
+				}
+				continue
+			}
+			if vagueLinkText[strings.ToLower(strings.Trim(text, ".!?*_ "))] {
+				warnings = append(warnings, LintWarning{l.num, "link-text", fmt.Sprintf("Link text %q doesn't describe where it goes.", text)})
+			}
+		}
+		for _, tag := range htmlImgTagReg.FindAllString(l.text, -1) {
+			if !htmlAltReg.MatchString(tag) {
+				warnings = append(warnings, LintWarning{l.num, "image-alt", "Image has no alt text."})
+			}
+		}
+		if m := headingReg.FindStringSubmatch(l.text); m != nil {
+			level := len(m[1])
+			if lastLevel > 0 && level > lastLevel+1 {
+				warnings = append(warnings, LintWarning{l.num, "heading-level", fmt.Sprintf("Heading level skips from %d to %d.", lastLevel, level)})
+			}
+			lastLevel = level
+		}
+	}
+	return warnings
+}
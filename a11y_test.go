@@ -0,0 +1,18 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestAccessibilityLinter(t *testing.T) {
+	content := "# Title\n\n![](cat.jpg) ![A cat](cat.jpg) <img src=\"dog.jpg\">\n\nFor details, [click here](https://write.as).\n\n### Too deep"
+
+	rules := map[string]int{}
+	for _, w := range AccessibilityLinter.Lint(content) {
+		rules[w.Rule]++
+	}
+	if rules["image-alt"] != 2 || rules["link-text"] != 1 || rules["heading-level"] != 1 {
+		t.Errorf("Unexpected warnings: %v", rules)
+	}
+}
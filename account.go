@@ -0,0 +1,68 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrDeleteNotConfirmed is returned by DeleteAccount when the confirmation
+// phrase doesn't match the account's username.
+var ErrDeleteNotConfirmed = errors.New("Account deletion not confirmed: pass the account's username to confirm.")
+
+// AccountDeletion lists everything DeleteAccount would destroy.
+type AccountDeletion struct {
+	User        *User
+	Collections []Collection
+	Posts       []Post
+}
+
+// PlanAccountDeletion is a dry run of DeleteAccount, listing the collections
+// and posts that deleting the authenticated user's account would destroy
+// without deleting anything.
+func (c *Client) PlanAccountDeletion() (*AccountDeletion, error) {
+	u, err := c.GetMe()
+	if err != nil {
+		return nil, err
+	}
+	colls, err := c.GetUserCollections()
+	if err != nil {
+		return nil, err
+	}
+	posts, err := c.GetUserPosts()
+	if err != nil {
+		return nil, err
+	}
+	return &AccountDeletion{User: u, Collections: *colls, Posts: *posts}, nil
+}
+
+// DeleteAccount permanently deletes the authenticated user's account, with all
+// of its collections and posts. As a safety interlock, confirmPhrase must be
+// the account's username, or ErrDeleteNotConfirmed is returned and nothing is
+// deleted. Use PlanAccountDeletion to see what would be destroyed first.
+func (c *Client) DeleteAccount(confirmPhrase string) error {
+	u, err := c.GetMe()
+	if err != nil {
+		return err
+	}
+	if confirmPhrase == "" || confirmPhrase != u.Username {
+		return ErrDeleteNotConfirmed
+	}
+
+	env, err := c.delete("/me", nil)
+	if err != nil {
+		return err
+	}
+
+	status := env.Code
+	if status == http.StatusNoContent {
+		c.SetToken("")
+		return nil
+	} else if c.isNotLoggedIn(status) {
+		return fmt.Errorf("Not authenticated.")
+	} else if status == http.StatusBadRequest {
+		return fmt.Errorf("Bad request: %s", env.ErrorMessage)
+	}
+	return fmt.Errorf("Problem deleting account: %d. %v\n", status, err)
+}
@@ -0,0 +1,37 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestDeleteAccount(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	api.addPost("blog", Post{Slug: "hello"})
+
+	c := api.client()
+	c.SetToken("token")
+	d, err := c.PlanAccountDeletion()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d.User.Username != "writer" || len(d.Collections) != 1 || len(d.Posts) != 1 || len(api.posts) != 1 {
+		t.Errorf("Unexpected dry run: %+v", d)
+	}
+
+	if err := c.DeleteAccount("someone"); err != ErrDeleteNotConfirmed {
+		t.Errorf("Expected unconfirmed deletion to fail, got %v", err)
+	}
+	if len(api.posts) != 1 {
+		t.Fatalf("Expected nothing to be deleted")
+	}
+
+	if err := c.DeleteAccount("writer"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(api.posts) != 0 || c.Token() != "" {
+		t.Errorf("Expected account to be deleted")
+	}
+}
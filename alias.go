@@ -0,0 +1,134 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type (
+	// AliasChange is the result of ChangeCollectionAlias.
+	AliasChange struct {
+		Collection *Collection
+		// URLs maps the collection's old URL, and the old URL of each of its
+		// posts, to the new one. URLs that didn't change, like those on a
+		// custom domain, aren't included.
+		URLs map[string]string
+	}
+
+	// RedirectStatus is the result of checking that an old URL redirects to
+	// its new one.
+	RedirectStatus struct {
+		From, To string
+		// Code is the HTTP status the old URL responded with, or 0 if the
+		// request failed.
+		Code int
+		// Location is where the old URL redirects to, if anywhere.
+		Location string
+		Err      error
+	}
+)
+
+// OK returns whether the old URL redirects to the new one.
+func (rs RedirectStatus) OK() bool {
+	return rs.Err == nil && rs.Code >= 300 && rs.Code < 400 && strings.TrimRight(rs.Location, "/") == strings.TrimRight(rs.To, "/")
+}
+
+// ChangeCollectionAlias changes the alias of the collection with the given
+// alias, returning the collection's and its posts' new URLs. It returns
+// ErrNotSupported if the instance doesn't allow changing aliases.
+func (c *Client) ChangeCollectionAlias(oldAlias, newAlias string) (*AliasChange, error) {
+	newAlias, err := ParseCollectionAlias(newAlias)
+	if err != nil {
+		return nil, err
+	}
+	old, err := c.GetCollection(oldAlias)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := c.allCollectionPosts(oldAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	coll := &Collection{}
+	env, err := c.post(fmt.Sprintf("/collections/%s", oldAlias), map[string]string{
+		"alias": newAlias,
+	}, coll)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if coll, ok = env.Data.(*Collection); !ok {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+	status := env.Code
+	if status != http.StatusOK {
+		if c.isNotLoggedIn(status) {
+			return nil, fmt.Errorf("Not authenticated.")
+		} else if status == http.StatusConflict {
+			return nil, fmt.Errorf("Collection name is already taken.")
+		} else if status == http.StatusBadRequest {
+			return nil, fmt.Errorf("Bad request: %s", env.ErrorMessage)
+		}
+		return nil, fmt.Errorf("Problem updating collection: %d. %v\n", status, err)
+	}
+	if coll.Alias != newAlias {
+		return nil, ErrNotSupported
+	}
+
+	ac := &AliasChange{Collection: coll, URLs: map[string]string{}}
+	ac.addURL(c.collectionURL(old), c.collectionURL(coll))
+	for i := range posts {
+		p := posts[i]
+		p.Collection = old
+		from := c.postURL(&p)
+		p.Collection = coll
+		ac.addURL(from, c.postURL(&p))
+	}
+	return ac, nil
+}
+
+func (ac *AliasChange) addURL(from, to string) {
+	if from != to {
+		ac.URLs[from] = to
+	}
+}
+
+// CheckRedirects requests each old URL, without following redirects, and
+// reports whether it redirects to the new URL, ordered by old URL.
+func (ac *AliasChange) CheckRedirects(c *Client) []RedirectStatus {
+	hc := *c.client
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	froms := make([]string, 0, len(ac.URLs))
+	for from := range ac.URLs {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+
+	statuses := make([]RedirectStatus, len(froms))
+	for i, from := range froms {
+		rs := RedirectStatus{From: from, To: ac.URLs[from]}
+		r, err := http.NewRequest("HEAD", from, nil)
+		if err == nil {
+			r.Header.Set("User-Agent", c.userAgent())
+			var resp *http.Response
+			if resp, err = hc.Do(r); err == nil {
+				resp.Body.Close()
+				rs.Code = resp.StatusCode
+				if loc, err := resp.Location(); err == nil {
+					rs.Location = loc.String()
+				}
+			}
+		}
+		rs.Err = err
+		statuses[i] = rs
+	}
+	return statuses
+}
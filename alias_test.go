@@ -0,0 +1,62 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChangeCollectionAlias(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	api.addPost("blog", Post{Slug: "hello"})
+	c := api.client()
+
+	ac, err := c.ChangeCollectionAlias("blog", "Journal")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ac.Collection.Alias != "journal" || api.collections["journal"] == nil {
+		t.Errorf("Unexpected collection: %+v", ac.Collection)
+	}
+	want := map[string]string{
+		api.URL + "/blog/":      api.URL + "/journal/",
+		api.URL + "/blog/hello": api.URL + "/journal/hello",
+	}
+	if len(ac.URLs) != len(want) {
+		t.Errorf("Unexpected URLs: %v", ac.URLs)
+	}
+	for from, to := range want {
+		if ac.URLs[from] != to {
+			t.Errorf("Unexpected URL for %s: %q", from, ac.URLs[from])
+		}
+	}
+}
+
+func TestCheckRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blog/hello" {
+			http.Redirect(w, r, "/journal/hello", http.StatusMovedPermanently)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	ac := &AliasChange{URLs: map[string]string{
+		srv.URL + "/blog/":      srv.URL + "/journal/",
+		srv.URL + "/blog/hello": srv.URL + "/journal/hello",
+	}}
+	statuses := ac.CheckRedirects(NewClient())
+	if len(statuses) != 2 {
+		t.Fatalf("Unexpected statuses: %+v", statuses)
+	}
+	if statuses[0].OK() || statuses[0].Code != http.StatusNotFound {
+		t.Errorf("Expected missing redirect, got: %+v", statuses[0])
+	}
+	if !statuses[1].OK() {
+		t.Errorf("Expected redirect, got: %+v", statuses[1])
+	}
+}
@@ -0,0 +1,103 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	anchorLinkReg    = regexp.MustCompile(`(\]\()#([^)\s]+)(\))`)
+	footnoteLinkReg  = regexp.MustCompile(`^(?:user-content-)?fn(ref)?[-:]?(\w+)$`)
+	inlineFootReg    = regexp.MustCompile(`\^\[([^\]]+)\]`)
+	footnoteLabelReg = regexp.MustCompile(`\[\^(\d+)\]`)
+)
+
+// HeadingAnchor returns the anchor the Write.as renderer generates for a
+// heading with the given text: lowercase letters and numbers, with every other
+// run of characters replaced by a single hyphen.
+func HeadingAnchor(text string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// githubAnchor returns the anchor GitHub and many static site generators
+// generate for a heading.
+func githubAnchor(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '-' || r == '_' {
+			b.WriteRune(r)
+		} else if r == ' ' {
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// NormalizeAnchors rewrites intra-post links, like [intro](#Intro) or links to
+// footnotes generated by other tools, so they point to the anchors the Write.as
+// renderer produces.
+func NormalizeAnchors(content string) string {
+	anchors := map[string]string{}
+	lines, _ := markdownLines(content)
+	for _, l := range lines {
+		if m := headingReg.FindStringSubmatch(l.text); !l.code && m != nil {
+			a := HeadingAnchor(m[2])
+			anchors[a] = a
+			anchors[githubAnchor(m[2])] = a
+			anchors[strings.ToLower(m[2])] = a
+		}
+	}
+
+	return anchorLinkReg.ReplaceAllStringFunc(content, func(m string) string {
+		sub := anchorLinkReg.FindStringSubmatch(m)
+		target := sub[2]
+		if fm := footnoteLinkReg.FindStringSubmatch(target); fm != nil {
+			return sub[1] + "#fn" + fm[1] + ":" + fm[2] + sub[3]
+		}
+		if a, ok := anchors[strings.ToLower(target)]; ok {
+			return sub[1] + "#" + a + sub[3]
+		}
+		return sub[1] + "#" + HeadingAnchor(target) + sub[3]
+	})
+}
+
+// NormalizeFootnotes converts inline footnotes (^[like this]) into the
+// reference footnotes ([^1] with a "[^1]: like this" definition) that the
+// Write.as renderer supports, numbering them after any existing footnotes.
+func NormalizeFootnotes(content string) string {
+	n := 0
+	for _, m := range footnoteLabelReg.FindAllStringSubmatch(content, -1) {
+		var i int
+		fmt.Sscanf(m[1], "%d", &i)
+		if i > n {
+			n = i
+		}
+	}
+
+	var defs []string
+	content = inlineFootReg.ReplaceAllStringFunc(content, func(m string) string {
+		n++
+		defs = append(defs, fmt.Sprintf("[^%d]: %s", n, inlineFootReg.FindStringSubmatch(m)[1]))
+		return fmt.Sprintf("[^%d]", n)
+	})
+	if len(defs) == 0 {
+		return content
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + strings.Join(defs, "\n") + "\n"
+}
@@ -0,0 +1,35 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestHeadingAnchor(t *testing.T) {
+	tests := map[string]string{
+		"Getting Started":        "getting-started",
+		"What's new in v2.0?":    "what-s-new-in-v2-0",
+		"  Café & Crème Brûlée ": "café-crème-brûlée",
+	}
+	for in, expected := range tests {
+		if res := HeadingAnchor(in); res != expected {
+			t.Errorf("HeadingAnchor(%q) = %q, expected %q", in, res, expected)
+		}
+	}
+}
+
+func TestNormalizeAnchors(t *testing.T) {
+	content := "## What's New\n\nSee [news](#whats-new), [note](#user-content-fn-1) and [back](#fnref1)."
+	expected := "## What's New\n\nSee [news](#what-s-new), [note](#fn:1) and [back](#fnref:1)."
+	if res := NormalizeAnchors(content); res != expected {
+		t.Errorf("Unexpected anchors: %s", res)
+	}
+}
+
+func TestNormalizeFootnotes(t *testing.T) {
+	content := "One[^1] and two^[An inline note].\n\n[^1]: First."
+	expected := "One[^1] and two[^2].\n\n[^1]: First.\n\n[^2]: An inline note\n"
+	if res := NormalizeFootnotes(content); res != expected {
+		t.Errorf("Unexpected footnotes: %q", res)
+	}
+}
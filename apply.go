@@ -0,0 +1,150 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+)
+
+type (
+	// Manifest describes the desired state of a user's blogs: their
+	// collections, and the posts and pinned pages in each, read from local
+	// files. Apply reconciles the remote state to match it.
+	Manifest struct {
+		Collections []ManifestCollection `json:"collections" yaml:"collections" toml:"collections"`
+	}
+
+	// ManifestCollection is a collection in a Manifest.
+	ManifestCollection struct {
+		Alias       string `json:"alias" yaml:"alias" toml:"alias"`
+		Title       string `json:"title" yaml:"title" toml:"title"`
+		Description string `json:"description,omitempty" yaml:"description" toml:"description"`
+		StyleSheet  string `json:"style_sheet,omitempty" yaml:"style_sheet" toml:"style_sheet"`
+
+		Posts []ManifestPost `json:"posts,omitempty" yaml:"posts" toml:"posts"`
+		// Pinned lists the slugs of posts to pin, in order.
+		Pinned []string `json:"pinned,omitempty" yaml:"pinned" toml:"pinned"`
+		// Prune deletes posts in the collection that aren't in Posts.
+		Prune bool `json:"prune,omitempty" yaml:"prune" toml:"prune"`
+		// RedirectStubs publishes a RedirectStub at the old slug of each post
+		// whose slug changes, so links to it keep working.
+		RedirectStubs bool `json:"redirect_stubs,omitempty" yaml:"redirect_stubs" toml:"redirect_stubs"`
+	}
+
+	// ManifestPost is a post in a ManifestCollection, with its content read
+	// from File.
+	ManifestPost struct {
+		Slug     string `json:"slug" yaml:"slug" toml:"slug"`
+		Title    string `json:"title,omitempty" yaml:"title" toml:"title"`
+		File     string `json:"file" yaml:"file" toml:"file"`
+		Font     string `json:"font,omitempty" yaml:"font" toml:"font"`
+		Language string `json:"lang,omitempty" yaml:"lang" toml:"lang"`
+		// OldSlugs lists slugs the post was published under before, newest
+		// first. If the post isn't found at Slug, it's moved from the first
+		// of these it's found at.
+		OldSlugs []string `json:"old_slugs,omitempty" yaml:"old_slugs" toml:"old_slugs"`
+	}
+)
+
+// UnmarshalFunc decodes a manifest, like json.Unmarshal, yaml.Unmarshal, or
+// toml.Unmarshal.
+type UnmarshalFunc func(data []byte, v interface{}) error
+
+// ParseManifest decodes a Manifest with the given UnmarshalFunc, or as JSON if
+// it's nil.
+//
+//	m, err := writeas.ParseManifest(data, yaml.Unmarshal)
+func ParseManifest(data []byte, unmarshal UnmarshalFunc) (*Manifest, error) {
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+	m := &Manifest{}
+	if err := unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("Invalid manifest: %v", err)
+	}
+	for _, coll := range m.Collections {
+		if coll.Alias == "" {
+			return nil, fmt.Errorf("Invalid manifest: every collection needs an alias.")
+		}
+		for _, p := range coll.Posts {
+			if p.Slug == "" || p.File == "" {
+				return nil, fmt.Errorf("Invalid manifest: every post in %s needs a slug and file.", coll.Alias)
+			}
+		}
+	}
+	return m, nil
+}
+
+// LoadManifest reads a Manifest file with the given UnmarshalFunc, or as JSON
+// if it's nil. Post files are relative to the manifest's directory.
+func LoadManifest(path string, unmarshal UnmarshalFunc) (*Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := ParseManifest(data, unmarshal)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	for i := range m.Collections {
+		for j := range m.Collections[i].Posts {
+			p := &m.Collections[i].Posts[j]
+			if !filepath.IsAbs(p.File) {
+				p.File = filepath.Join(dir, p.File)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Apply reconciles the user's collections and posts with the given Manifest.
+// It first writes the Plan of changes to w, then applies it with
+// ApplyChangeset.
+func (c *Client) Apply(m *Manifest, w io.Writer) error {
+	cs, err := c.Plan(m)
+	if err != nil {
+		return err
+	}
+	if err = cs.Render(w); err != nil {
+		return err
+	}
+	return c.ApplyChangeset(cs)
+}
+
+// manifestParams reads the post files in the Manifest, returning the
+// parameters for each collection and its posts.
+func (c *Client) manifestParams(m *Manifest) ([]*CollectionParams, [][]*PostParams, error) {
+	colls := make([]*CollectionParams, len(m.Collections))
+	posts := make([][]*PostParams, len(m.Collections))
+	for i, coll := range m.Collections {
+		colls[i] = &CollectionParams{
+			Alias:       coll.Alias,
+			Title:       coll.Title,
+			Description: coll.Description,
+			StyleSheet:  coll.StyleSheet,
+		}
+		for _, mp := range coll.Posts {
+			content, err := ioutil.ReadFile(mp.File)
+			if err != nil {
+				return nil, nil, err
+			}
+			sp := &PostParams{
+				Collection: coll.Alias,
+				Slug:       mp.Slug,
+				Title:      mp.Title,
+				Content:    string(content),
+				Font:       mp.Font,
+			}
+			if mp.Language != "" {
+				lang := mp.Language
+				sp.Language = &lang
+			}
+			posts[i] = append(posts[i], sp)
+		}
+	}
+	return colls, posts, nil
+}
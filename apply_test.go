@@ -0,0 +1,120 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	dir, err := ioutil.TempDir("", "writeas-apply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "hello.md"), []byte("Hello, world."), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "about.md"), []byte("About me."), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "blog.json"), []byte(`{"collections": [{
+		"alias": "blog", "title": "Blog",
+		"posts": [
+			{"slug": "hello", "title": "Hello", "file": "hello.md"},
+			{"slug": "about", "title": "About", "file": "about.md"}
+		],
+		"pinned": ["about"]
+	}]}`), 0644)
+
+	m, err := LoadManifest(filepath.Join(dir, "blog.json"), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog", Title: "Blog"}
+	api.addPost("blog", Post{Slug: "hello", Title: "Hello", Content: "Old content."})
+
+	var plan bytes.Buffer
+	if err := api.client().Apply(m, &plan); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `~ post blog/hello
+    - Old content.
+    + Hello, world.
++ post blog/about
+^ post blog/about at 1
+Plan: 1 to create, 1 to update, 0 to delete, 1 to pin, 0 to unpin.
+`
+	if plan.String() != expected {
+		t.Errorf("Unexpected plan:\n%s", plan.String())
+	}
+	if p := api.collectionPost("blog", "hello"); p.Content != "Hello, world." {
+		t.Errorf("Unexpected post: %+v", p)
+	}
+	about := api.collectionPost("blog", "about")
+	if about == nil || len(api.pinned["blog"]) != 1 || api.pinned["blog"][0] != about.ID {
+		t.Errorf("Unexpected pins: %v", api.pinned)
+	}
+
+	if _, err := ParseManifest([]byte(`{"collections": [{"title": "No alias"}]}`), nil); err == nil {
+		t.Errorf("Expected error for collection without alias")
+	}
+}
+
+func TestPlanPins(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog", Title: "Blog"}
+	api.addPost("blog", Post{Slug: "hello", Title: "Hello", Content: "Hello."})
+	api.addPost("blog", Post{Slug: "about", Title: "About", Content: "About."})
+	c := api.client()
+
+	m := &Manifest{Collections: []ManifestCollection{{Alias: "blog", Title: "Blog", Pinned: []string{"about", "hello"}}}}
+	if err := c.Apply(m, ioutil.Discard); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(api.pinned["blog"]) != 2 {
+		t.Errorf("Unexpected pins: %v", api.pinned)
+	}
+	cs, err := c.Plan(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !cs.Empty() {
+		t.Errorf("Unexpected changes after apply: %+v", cs.Changes)
+	}
+
+	m.Collections[0].Pinned = []string{"hello"}
+	cs, err = c.Plan(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cs.Of(ChangeUnpin)) != 1 || cs.Of(ChangeUnpin)[0].Slug != "about" || len(cs.Of(ChangePin)) != 1 {
+		t.Errorf("Unexpected changes: %+v", cs.Changes)
+	}
+	if err = c.ApplyChangeset(cs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hello := api.collectionPost("blog", "hello")
+	if len(api.pinned["blog"]) != 1 || api.pinned["blog"][0] != hello.ID {
+		t.Errorf("Unexpected pins: %v", api.pinned)
+	}
+	if pins, _ := c.Pinned("blog"); len(pins) != 1 || pins[0] != "hello" {
+		t.Errorf("Unexpected recorded pins: %v", pins)
+	}
+
+	// Pins already made are recorded when a later change fails
+	cs = &Changeset{Changes: []Change{
+		{Kind: ChangePin, Collection: "blog", Slug: "about", Position: 1},
+		{Kind: ChangeDelete, Collection: "blog", Slug: "missing", postID: "missing"},
+	}}
+	if err = c.ApplyChangeset(cs); err == nil {
+		t.Fatalf("Expected an error deleting a missing post")
+	}
+	if pins, _ := c.Pinned("blog"); fmt.Sprint(pins) != "[about hello]" {
+		t.Errorf("Unexpected recorded pins after a failure: %v", pins)
+	}
+}
@@ -0,0 +1,127 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// ArchiveMonth holds a collection's posts published in a single month.
+	ArchiveMonth struct {
+		Year  int
+		Month time.Month
+		Posts []Post
+	}
+
+	// ArchiveParams holds values for publishing an archive post.
+	ArchiveParams struct {
+		// Title of the archive post. Defaults to "Archive".
+		Title string
+		// BaseURL that post slugs are appended to. Defaults to the collection's
+		// public URL.
+		BaseURL string
+
+		// Pin the archive post to the collection after publishing it.
+		Pin      bool
+		Position int
+	}
+)
+
+// GroupByMonth groups the given posts by the month they were created, newest
+// first.
+func GroupByMonth(posts []Post) []ArchiveMonth {
+	sorted := make([]Post, len(posts))
+	copy(sorted, posts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Created.After(sorted[j].Created)
+	})
+
+	var months []ArchiveMonth
+	for _, p := range sorted {
+		y, m, _ := p.Created.Date()
+		if n := len(months); n == 0 || months[n-1].Year != y || months[n-1].Month != m {
+			months = append(months, ArchiveMonth{Year: y, Month: m})
+		}
+		months[len(months)-1].Posts = append(months[len(months)-1].Posts, p)
+	}
+	return months
+}
+
+// RenderArchive renders a Markdown archive of the given posts, grouped by year
+// and month, linking each post to baseURL + its slug.
+func RenderArchive(baseURL string, posts []Post) string {
+	if baseURL != "" && !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+
+	var b strings.Builder
+	year := 0
+	for _, am := range GroupByMonth(posts) {
+		if am.Year != year {
+			year = am.Year
+			fmt.Fprintf(&b, "## %d\n\n", year)
+		}
+		fmt.Fprintf(&b, "### %s\n\n", am.Month)
+		for _, p := range am.Posts {
+			fmt.Fprintf(&b, "- [%s](%s%s)\n", postDisplayTitle(&p), baseURL, p.Slug)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// PublishArchive renders an archive of the given collection's posts and
+// publishes it to the collection, optionally pinning it.
+func (c *Client) PublishArchive(alias string, ap *ArchiveParams) (*Post, error) {
+	baseURL := ap.BaseURL
+	if baseURL == "" {
+		coll, err := c.GetCollection(alias)
+		if err != nil {
+			return nil, err
+		}
+		baseURL = coll.URL
+	}
+
+	posts, err := c.GetCollectionPosts(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	title := ap.Title
+	if title == "" {
+		title = "Archive"
+	}
+	p, err := c.CreatePost(&PostParams{
+		Title:      title,
+		Content:    RenderArchive(baseURL, *posts),
+		Collection: alias,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if ap.Pin {
+		err = c.PinPost(alias, &PinnedPostParams{ID: p.ID, Position: ap.Position})
+		if err == nil {
+			err = c.recordPin(alias, p.Slug, ap.Position)
+		}
+		if err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// postDisplayTitle returns the post's title, falling back to its slug or ID.
+func postDisplayTitle(p *Post) string {
+	if p.Title != "" {
+		return p.Title
+	}
+	if p.Slug != "" {
+		return p.Slug
+	}
+	return p.ID
+}
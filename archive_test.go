@@ -0,0 +1,34 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenderArchive(t *testing.T) {
+	d := func(y int, m time.Month) time.Time {
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	}
+	posts := []Post{
+		{Slug: "first", Title: "First", Created: d(2018, time.December)},
+		{Slug: "third", Title: "Third", Created: d(2019, time.January)},
+		{Slug: "second", Created: d(2018, time.December).Add(time.Hour)},
+	}
+
+	expected := `## 2019
+
+### January
+
+- [Third](https://write.as/blog/third)
+
+## 2018
+
+### December
+
+- [second](https://write.as/blog/second)
+- [First](https://write.as/blog/first)`
+	if res := RenderArchive("https://write.as/blog", posts); res != expected {
+		t.Errorf("Unexpected archive:\n%s", res)
+	}
+}
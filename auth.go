@@ -63,13 +63,13 @@ func (c *Client) LogOut() error {
 	}
 
 	// Logout successful, so update the Client
-	c.token = ""
+	c.SetToken("")
 
 	return nil
 }
 
 func (c *Client) isNotLoggedIn(code int) bool {
-	if c.token == "" {
+	if c.token == "" && c.auth == nil {
 		return false
 	}
 	return code == http.StatusUnauthorized
@@ -0,0 +1,71 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+)
+
+// Authenticator adds authentication to API requests, for WriteFreely
+// deployments and proxies that expect something other than Write.as' usual
+// "Authorization: Token" header.
+type Authenticator interface {
+	ApplyAuth(r *http.Request)
+}
+
+type (
+	// TokenAuth sends an access token in the Authorization header. It's kept
+	// up to date with the Client's token, as set by SetToken or LogIn.
+	TokenAuth struct {
+		Token string
+		// Scheme defaults to "Token". Some proxies expect "Bearer".
+		Scheme string
+	}
+
+	// BasicAuth sends a username and password with HTTP basic
+	// authentication.
+	BasicAuth struct {
+		Username string
+		Password string
+	}
+
+	// HeaderAuth sends a credential, like an API key, in a custom header.
+	HeaderAuth struct {
+		Header string
+		Value  string
+	}
+)
+
+// WithAuthenticator sets the Authenticator used for every request, instead of
+// sending the Client's token in an "Authorization: Token" header.
+//
+//	c := writeas.NewClient(writeas.WithAuthenticator(&writeas.TokenAuth{Scheme: "Bearer"}))
+func WithAuthenticator(a Authenticator) Option {
+	return func(c *Client) {
+		c.auth = a
+		if ta, ok := a.(*TokenAuth); ok && ta.Token == "" {
+			ta.Token = c.token
+		}
+	}
+}
+
+// ApplyAuth implements the Authenticator interface.
+func (a *TokenAuth) ApplyAuth(r *http.Request) {
+	if a.Token == "" {
+		return
+	}
+	scheme := a.Scheme
+	if scheme == "" {
+		scheme = "Token"
+	}
+	r.Header.Set("Authorization", scheme+" "+a.Token)
+}
+
+// ApplyAuth implements the Authenticator interface.
+func (a *BasicAuth) ApplyAuth(r *http.Request) {
+	r.SetBasicAuth(a.Username, a.Password)
+}
+
+// ApplyAuth implements the Authenticator interface.
+func (a *HeaderAuth) ApplyAuth(r *http.Request) {
+	r.Header.Set(a.Header, a.Value)
+}
@@ -0,0 +1,37 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAuthenticators(t *testing.T) {
+	tests := []struct {
+		auth   Authenticator
+		header string
+		value  string
+	}{
+		{&TokenAuth{Token: "abc"}, "Authorization", "Token abc"},
+		{&TokenAuth{Token: "abc", Scheme: "Bearer"}, "Authorization", "Bearer abc"},
+		{&BasicAuth{Username: "writer", Password: "pass"}, "Authorization", "Basic d3JpdGVyOnBhc3M="},
+		{&HeaderAuth{Header: "X-API-Key", Value: "key"}, "X-API-Key", "key"},
+	}
+	for _, test := range tests {
+		c := NewClient(WithAuthenticator(test.auth))
+		r, _ := http.NewRequest("GET", "https://write.as/api/me", nil)
+		c.prepareRequest(r)
+		if v := r.Header.Get(test.header); v != test.value {
+			t.Errorf("Unexpected %s header for %T: %q", test.header, test.auth, v)
+		}
+	}
+
+	ta := &TokenAuth{Scheme: "Bearer"}
+	c := NewClient(WithAuthenticator(ta))
+	c.SetToken("xyz")
+	r, _ := http.NewRequest("GET", "https://write.as/api/me", nil)
+	c.prepareRequest(r)
+	if v := r.Header.Get("Authorization"); v != "Bearer xyz" {
+		t.Errorf("Expected token from SetToken, got %q", v)
+	}
+}
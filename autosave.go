@@ -0,0 +1,366 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/writeas/impart"
+)
+
+type (
+	// Autosave periodically saves a Draft being edited to a DraftStore, and
+	// optionally to an unlisted post, so it can be recovered after a crash.
+	// Call Update as the draft changes and Run to save it every Interval.
+	Autosave struct {
+		Client   *Client
+		Store    DraftStore
+		Interval time.Duration
+
+		// Remote, if true, also saves the draft to an unlisted post, so it
+		// survives losing the local store.
+		Remote bool
+
+		// OnError is called with errors that occur while saving in Run. It
+		// may be nil.
+		OnError func(error)
+
+		// saving is held for the whole of Save, so saves happen one at a
+		// time, while mu guards the fields and is only held briefly, so
+		// Update doesn't wait on the network.
+		saving sync.Mutex
+		mu     sync.Mutex
+		saved  AutosavedDraft
+		draft  *Draft
+		dirty  bool
+		run    RunState
+	}
+
+	// AutosavedDraft is a Draft as last saved by an Autosave session.
+	AutosavedDraft struct {
+		Session string    `json:"session"`
+		Draft   Draft     `json:"draft"`
+		Saved   time.Time `json:"saved"`
+
+		// PostID and Token identify the unlisted post the draft is also
+		// saved to, if any.
+		PostID string `json:"post_id,omitempty"`
+		Token  string `json:"token,omitempty"`
+	}
+
+	// DraftStore keeps the drafts of Autosave sessions until they're
+	// discarded.
+	DraftStore interface {
+		SaveDraft(d *AutosavedDraft) error
+		Drafts() ([]AutosavedDraft, error)
+		DeleteDraft(session string) error
+	}
+
+	// MemoryDraftStore is a DraftStore that keeps drafts in memory.
+	MemoryDraftStore struct {
+		mu     sync.Mutex
+		drafts map[string]AutosavedDraft
+	}
+
+	// DirDraftStore is a DraftStore that keeps each draft in a JSON file
+	// named after its session in Dir.
+	DirDraftStore struct {
+		Dir string
+	}
+)
+
+// DefaultAutosaveInterval is the saving interval used when an Autosave's
+// Interval isn't set.
+const DefaultAutosaveInterval = 30 * time.Second
+
+// RecoverDrafts returns the drafts of the Autosave sessions in the store that
+// were never discarded, like those of an editor that crashed, most recently
+// saved first. Pass one to Resume to continue its session.
+func RecoverDrafts(store DraftStore) ([]AutosavedDraft, error) {
+	drafts, err := store.Drafts()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(drafts, func(i, j int) bool {
+		return drafts[i].Saved.After(drafts[j].Saved)
+	})
+	return drafts, nil
+}
+
+// Resume continues the session of a recovered draft, instead of starting a
+// new one. It must be called before Update.
+func (a *Autosave) Resume(d AutosavedDraft) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.saved = d
+	draft := d.Draft
+	a.draft = &draft
+}
+
+// Session returns the ID of the session, which is empty until the draft is
+// first saved.
+func (a *Autosave) Session() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.saved.Session
+}
+
+// Update records the current state of the draft, to be saved on the next
+// Save.
+func (a *Autosave) Update(d *Draft) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	draft := *d
+	a.draft = &draft
+	a.dirty = true
+}
+
+// Save saves the draft now, if it changed since it was last saved. It's saved
+// to the store first, so a failure to save it remotely doesn't lose it. The
+// draft is saved as it was when Save was called, so Update can be called
+// while it's being saved.
+func (a *Autosave) Save() error {
+	a.saving.Lock()
+	defer a.saving.Unlock()
+
+	a.mu.Lock()
+	if !a.dirty {
+		a.mu.Unlock()
+		return nil
+	}
+	ad := a.saved
+	if ad.Session == "" {
+		ad.Session = a.Client.NewID()
+	}
+	ad.Draft = *a.draft
+	ad.Saved = a.Client.Now()
+	a.dirty = false
+	a.mu.Unlock()
+
+	if err := a.Store.SaveDraft(&ad); err != nil {
+		a.markDirty()
+		return err
+	}
+	a.setSaved(ad)
+
+	if !a.Remote || ad.Draft.IsEmpty() {
+		return nil
+	}
+	created := ad.PostID == ""
+	if err := a.Client.saveRemoteDraft(&ad); err != nil {
+		// Try again on the next save
+		a.markDirty()
+		return fmt.Errorf("Save draft remotely: %v", err)
+	}
+	a.setSaved(ad)
+	if created {
+		return a.Store.SaveDraft(&ad)
+	}
+	return nil
+}
+
+func (a *Autosave) markDirty() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.dirty = true
+}
+
+func (a *Autosave) setSaved(ad AutosavedDraft) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.saved = ad
+}
+
+// Run saves the draft every Interval until ctx is done or Close is called.
+func (a *Autosave) Run(ctx context.Context) error {
+	ctx, err := a.run.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer a.run.End()
+
+	interval := a.Interval
+	if interval <= 0 {
+		interval = DefaultAutosaveInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := a.Save(); err != nil {
+			ReportError(a.OnError, err)
+		}
+	}
+}
+
+// Close stops Run and saves any changes since the last save. The draft stays
+// in the store, to be recovered later, until it's discarded.
+func (a *Autosave) Close() error {
+	a.run.Close()
+	return a.Save()
+}
+
+// Discard stops Run and deletes the saved draft, along with its unlisted
+// post, for when the draft was published or abandoned.
+func (a *Autosave) Discard() error {
+	a.run.Close()
+	a.saving.Lock()
+	defer a.saving.Unlock()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.dirty = false
+	if a.saved.Session == "" {
+		return nil
+	}
+	if a.saved.PostID != "" {
+		err := a.Client.DeletePostIdempotent(&PostParams{ID: a.saved.PostID, Token: a.saved.Token})
+		if err != nil {
+			return err
+		}
+	}
+	return a.Store.DeleteDraft(a.saved.Session)
+}
+
+// saveRemoteDraft saves the draft to its unlisted post, creating one if it
+// doesn't have one yet. The draft is sent as is, without the Client's
+// defaults, filters, or footer, since it isn't being published.
+func (c *Client) saveRemoteDraft(ad *AutosavedDraft) error {
+	sp := &PostParams{
+		Title:    ad.Draft.Title,
+		Content:  ad.Draft.Content,
+		Font:     ad.Draft.Font,
+		IsRTL:    ad.Draft.IsRTL,
+		Language: ad.Draft.Language,
+	}
+
+	p := &Post{}
+	var env *impart.Envelope
+	var err error
+	if ad.PostID == "" {
+		env, err = c.post("/posts", sp, p)
+	} else {
+		sp.Token = ad.Token
+		env, err = c.put(fmt.Sprintf("/posts/%s", ad.PostID), sp, p)
+	}
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	if p, ok = env.Data.(*Post); !ok {
+		return fmt.Errorf("Wrong data returned from API.")
+	}
+	status := env.Code
+	if status != http.StatusOK && status != http.StatusCreated {
+		if c.isNotLoggedIn(status) {
+			return fmt.Errorf("Not authenticated.")
+		} else if status == http.StatusBadRequest {
+			return fmt.Errorf("Bad request: %s", env.ErrorMessage)
+		}
+		return fmt.Errorf("Problem saving post: %d. %v\n", status, err)
+	}
+	if ad.PostID == "" {
+		ad.PostID, ad.Token = p.ID, p.Token
+	}
+	return nil
+}
+
+// SaveDraft implements the DraftStore interface.
+func (s *MemoryDraftStore) SaveDraft(d *AutosavedDraft) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.drafts == nil {
+		s.drafts = map[string]AutosavedDraft{}
+	}
+	s.drafts[d.Session] = *d
+	return nil
+}
+
+// Drafts implements the DraftStore interface.
+func (s *MemoryDraftStore) Drafts() ([]AutosavedDraft, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	drafts := make([]AutosavedDraft, 0, len(s.drafts))
+	for _, d := range s.drafts {
+		drafts = append(drafts, d)
+	}
+	return drafts, nil
+}
+
+// DeleteDraft implements the DraftStore interface.
+func (s *MemoryDraftStore) DeleteDraft(session string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.drafts, session)
+	return nil
+}
+
+// SaveDraft implements the DraftStore interface. The file is replaced
+// atomically, so a crash while saving leaves the previous draft.
+func (s *DirDraftStore) SaveDraft(d *AutosavedDraft) error {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	path := s.path(d.Session)
+	if err = ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// Drafts implements the DraftStore interface.
+func (s *DirDraftStore) Drafts() ([]AutosavedDraft, error) {
+	files, err := ioutil.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	drafts := []AutosavedDraft{}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(s.Dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		d := AutosavedDraft{}
+		if err = json.Unmarshal(data, &d); err != nil {
+			return nil, fmt.Errorf("Invalid draft %s: %v", f.Name(), err)
+		}
+		drafts = append(drafts, d)
+	}
+	return drafts, nil
+}
+
+// DeleteDraft implements the DraftStore interface.
+func (s *DirDraftStore) DeleteDraft(session string) error {
+	err := os.Remove(s.path(session))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *DirDraftStore) path(session string) string {
+	return filepath.Join(s.Dir, session+".json")
+}
@@ -0,0 +1,108 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAutosave(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := api.client(WithClock(ClockFunc(func() time.Time { return now })), WithIDGenerator(IDGeneratorFunc(func() string { return "s1" })))
+	store := &DirDraftStore{Dir: t.TempDir()}
+
+	a := &Autosave{Client: c, Store: store, Remote: true}
+	if err := a.Save(); err != nil || len(api.requests) != 0 {
+		t.Errorf("Unexpected save of an unchanged draft: %v, %v", err, api.requests)
+	}
+	a.Update(&Draft{Title: "Notes", Content: "First"})
+	if err := a.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	a.Update(&Draft{Title: "Notes", Content: "Second"})
+	now = now.Add(time.Minute)
+	if err := a.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	drafts, err := RecoverDrafts(store)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(drafts) != 1 || drafts[0].Session != "s1" || drafts[0].Draft.Content != "Second" || !drafts[0].Saved.Equal(now) {
+		t.Fatalf("Unexpected recovered drafts: %+v", drafts)
+	}
+	id := drafts[0].PostID
+	if p := api.posts[id]; p == nil || p.Content != "Second" || p.Collection != nil {
+		t.Errorf("Unexpected remote draft: %+v", p)
+	}
+
+	resumed := &Autosave{Client: c, Store: store, Remote: true}
+	resumed.Resume(drafts[0])
+	if resumed.Session() != "s1" {
+		t.Errorf("Unexpected session: %s", resumed.Session())
+	}
+	if err = resumed.Discard(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if drafts, _ = RecoverDrafts(store); len(drafts) != 0 {
+		t.Errorf("Unexpected drafts after discarding: %+v", drafts)
+	}
+	if api.posts[id] != nil {
+		t.Errorf("Expected remote draft to be deleted")
+	}
+}
+
+func TestRecoverDraftsOrder(t *testing.T) {
+	store := &MemoryDraftStore{}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, s := range []string{"old", "new", "mid"} {
+		store.SaveDraft(&AutosavedDraft{Session: s, Saved: base.Add(time.Duration([]int{0, 2, 1}[i]) * time.Hour)})
+	}
+	drafts, _ := RecoverDrafts(store)
+	if len(drafts) != 3 || drafts[0].Session != "new" || drafts[1].Session != "mid" || drafts[2].Session != "old" {
+		t.Errorf("Unexpected order: %+v", drafts)
+	}
+}
+
+// updatingDraftStore is a DraftStore that updates the draft while it's being
+// saved.
+type updatingDraftStore struct {
+	MemoryDraftStore
+	a      *Autosave
+	update *Draft
+}
+
+func (s *updatingDraftStore) SaveDraft(d *AutosavedDraft) error {
+	if s.update != nil {
+		s.a.Update(s.update)
+		s.update = nil
+	}
+	return s.MemoryDraftStore.SaveDraft(d)
+}
+
+func TestAutosaveUpdateWhileSaving(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	store := &updatingDraftStore{update: &Draft{Content: "Second"}}
+	a := &Autosave{Client: api.client(), Store: store, Remote: true}
+	store.a = a
+
+	a.Update(&Draft{Content: "First"})
+	if err := a.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	drafts, _ := RecoverDrafts(store)
+	if len(drafts) != 1 || drafts[0].Draft.Content != "First" || api.posts[drafts[0].PostID].Content != "First" {
+		t.Fatalf("Unexpected saved drafts: %+v", drafts)
+	}
+	if err := a.Save(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	drafts, _ = RecoverDrafts(store)
+	if len(drafts) != 1 || drafts[0].Draft.Content != "Second" || api.posts[drafts[0].PostID].Content != "Second" {
+		t.Errorf("Unexpected drafts after the update: %+v", drafts)
+	}
+}
@@ -0,0 +1,268 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Layout of a backup archive.
+const (
+	backupCollectionsFile = "collections.json"
+	backupPinsFile        = "pins.json"
+	backupCollectionsDir  = "collections/"
+	backupPostsDir        = "posts/"
+	backupSumsFile        = "SHA256SUMS"
+	backupSigFile         = "SHA256SUMS.sig"
+)
+
+// Backup writes a gzipped tar archive of the authenticated user's account to w.
+// It contains:
+//
+//   - collections.json, with every collection
+//   - pins.json, with the slugs of the posts pinned in each collection, in
+//     order, as recorded in the Client's PinStore
+//   - collections/<alias>/<slug>.md and .json, with each collection post as
+//     Markdown with front matter, and its full metadata
+//   - posts/<id>.md and .json, with each post not in a collection
+//   - SHA256SUMS, with the SHA-256 checksum of every other file, in the
+//     format of sha256sum
+//
+// The archive can be republished with Restore. It's written to w as each file
+// is made, so a large account isn't held in memory as an archive.
+//
+// Backups are reproducible: files are in a stable order, and their times are
+// those of the posts, to the second, so backing up the same account twice
+// gives the same bytes, and archives can be diffed across time.
+func (c *Client) Backup(w io.Writer) error {
+	return c.backup(w, nil)
+}
+
+// SignedBackup writes a backup like Backup, adding SHA256SUMS.sig, a
+// base64-encoded ed25519 signature of SHA256SUMS made with the given key. The
+// archive can be checked with VerifyBackup.
+func (c *Client) SignedBackup(w io.Writer, key ed25519.PrivateKey) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("Invalid signing key.")
+	}
+	return c.backup(w, key)
+}
+
+// backupWriter streams files into a backup archive, keeping the checksum of
+// each one for SHA256SUMS.
+type backupWriter struct {
+	tw   *tar.Writer
+	sums bytes.Buffer
+}
+
+// add writes a file to the archive, recording its checksum.
+func (bw *backupWriter) add(name string, data []byte, modified time.Time) error {
+	fmt.Fprintf(&bw.sums, "%x  %s\n", sha256.Sum256(data), name)
+	return writeTarFile(bw.tw, name, data, modified)
+}
+
+func (c *Client) backup(w io.Writer, key ed25519.PrivateKey) error {
+	colls, err := c.GetUserCollections()
+	if err != nil {
+		return err
+	}
+	posts, err := c.GetUserPosts()
+	if err != nil {
+		return err
+	}
+
+	// Order posts by their file names, so files are in a stable order, and
+	// find the latest time before writing anything
+	names := make([]string, len(*posts))
+	order := make([]int, len(*posts))
+	var latest time.Time
+	for i := range *posts {
+		p := &(*posts)[i]
+		names[i] = backupPostsDir + p.ID
+		if p.Collection != nil {
+			slug := p.Slug
+			if slug == "" {
+				slug = p.ID
+			}
+			names[i] = backupCollectionsDir + p.Collection.Alias + "/" + slug
+		}
+		order[i] = i
+		if m := postModified(p); m.After(latest) {
+			latest = m
+		}
+	}
+	sort.Slice(order, func(i, j int) bool { return names[order[i]] < names[order[j]] })
+
+	sortedColls := append([]Collection{}, *colls...)
+	sort.Slice(sortedColls, func(i, j int) bool { return sortedColls[i].Alias < sortedColls[j].Alias })
+	data, err := json.MarshalIndent(sortedColls, "", "  ")
+	if err != nil {
+		return err
+	}
+	pins := map[string][]string{}
+	for _, coll := range sortedColls {
+		slugs, err := c.Pinned(coll.Alias)
+		if err != nil {
+			return err
+		}
+		if len(slugs) > 0 {
+			pins[coll.Alias] = slugs
+		}
+	}
+	pinData, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	bw := &backupWriter{tw: tar.NewWriter(gz)}
+	// Collections and pins have no dates, so use the latest post's, rather
+	// than now
+	if err = bw.add(backupCollectionsFile, data, latest); err != nil {
+		return err
+	}
+	if err = bw.add(backupPinsFile, pinData, latest); err != nil {
+		return err
+	}
+	for _, i := range order {
+		p := &(*posts)[i]
+		data, err := json.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = bw.add(names[i]+".json", data, postModified(p)); err != nil {
+			return err
+		}
+		if err = bw.add(names[i]+".md", []byte(FrontMatter(p)+p.Content), postModified(p)); err != nil {
+			return err
+		}
+	}
+
+	sums := bw.sums.Bytes()
+	if err = writeTarFile(bw.tw, backupSumsFile, sums, latest); err != nil {
+		return err
+	}
+	if key != nil {
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, sums))
+		if err = writeTarFile(bw.tw, backupSigFile, []byte(sig+"\n"), latest); err != nil {
+			return err
+		}
+	}
+	if err = bw.tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// postModified returns when a post was last changed.
+func postModified(p *Post) time.Time {
+	if p.Updated.IsZero() {
+		return p.Created
+	}
+	return p.Updated
+}
+
+// VerifyBackup checks that every file in a backup archive matches its
+// checksum in SHA256SUMS, and that no files were added or removed. If pub
+// isn't nil, the archive must also be signed with its private key, by
+// SignedBackup.
+func VerifyBackup(r io.Reader, pub ed25519.PublicKey) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("Invalid backup: %v", err)
+	}
+	defer gz.Close()
+
+	var sums, sig []byte
+	actual := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Invalid backup: %v", err)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("Invalid backup: %v", err)
+		}
+		switch h.Name {
+		case backupSumsFile:
+			sums = data
+		case backupSigFile:
+			sig = data
+		default:
+			actual[h.Name] = fmt.Sprintf("%x", sha256.Sum256(data))
+		}
+	}
+	if sums == nil {
+		return fmt.Errorf("Backup has no %s.", backupSumsFile)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(sums)), "\n") {
+		parts := strings.SplitN(line, "  ", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("Malformed %s line: %q", backupSumsFile, line)
+		}
+		sum, ok := actual[parts[1]]
+		if !ok {
+			return fmt.Errorf("Backup is missing %s.", parts[1])
+		}
+		if sum != parts[0] {
+			return fmt.Errorf("Backup file %s doesn't match its checksum.", parts[1])
+		}
+		delete(actual, parts[1])
+	}
+	for name := range actual {
+		return fmt.Errorf("Backup file %s isn't in %s.", name, backupSumsFile)
+	}
+
+	if pub == nil {
+		return nil
+	}
+	if sig == nil {
+		return fmt.Errorf("Backup isn't signed.")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("Malformed signature: %v", err)
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("Invalid public key.")
+	}
+	if !ed25519.Verify(pub, sums, raw) {
+		return fmt.Errorf("Signature doesn't match backup.")
+	}
+	return nil
+}
+
+// writeTarFile writes a file with normalized metadata, so the same file is
+// always written the same way.
+func writeTarFile(tw *tar.Writer, name string, data []byte, modified time.Time) error {
+	if modified.IsZero() {
+		modified = time.Unix(0, 0)
+	}
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modified.UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(data)
+	return err
+}
@@ -0,0 +1,124 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackup(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog", Title: "Blog"}
+	api.addPost("blog", Post{Slug: "hello", Title: "Hello", Content: "Hello, world."})
+	api.addPost("", Post{ID: "anon", Content: "Anonymous."})
+
+	var buf bytes.Buffer
+	if err := api.client().Backup(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	files := readTarGz(t, &buf)
+	for _, name := range []string{"collections.json", "collections/blog/hello.md", "collections/blog/hello.json", "posts/anon.md", "posts/anon.json"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Missing %s in backup", name)
+		}
+	}
+	if md := files["collections/blog/hello.md"]; !strings.HasPrefix(md, "---\ntitle: \"Hello\"\n") || !strings.HasSuffix(md, "---\nHello, world.") {
+		t.Errorf("Unexpected post Markdown: %q", md)
+	}
+}
+
+func readTarGz(t *testing.T, buf *bytes.Buffer) map[string]string {
+	gz, err := gzip.NewReader(buf)
+	if err != nil {
+		t.Fatalf("Bad gzip: %v", err)
+	}
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			break
+		}
+		data, _ := ioutil.ReadAll(tr)
+		files[h.Name] = string(data)
+	}
+	return files
+}
+
+func TestBackupReproducible(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog", Title: "Blog"}
+	api.collections["notes"] = &Collection{Alias: "notes", Title: "Notes"}
+	api.addPost("blog", Post{Slug: "hello", Content: "Hello.", Created: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)})
+	api.addPost("notes", Post{Slug: "note", Content: "Note."})
+	api.addPost("", Post{ID: "anon", Content: "Anonymous."})
+
+	backup := func(now time.Time) []byte {
+		var buf bytes.Buffer
+		c := api.client(WithClock(ClockFunc(func() time.Time { return now })))
+		if err := c.Backup(&buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return buf.Bytes()
+	}
+	a := backup(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	b := backup(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	if !bytes.Equal(a, b) {
+		t.Errorf("Expected backups of the same account to be identical")
+	}
+	if err := VerifyBackup(bytes.NewReader(a), nil); err != nil {
+		t.Errorf("Unexpected verification error: %v", err)
+	}
+	if sums := readTarGz(t, bytes.NewBuffer(a))["SHA256SUMS"]; !strings.Contains(sums, "  collections/blog/hello.md\n") {
+		t.Errorf("Unexpected SHA256SUMS: %q", sums)
+	}
+}
+
+func TestSignedBackup(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.addPost("", Post{ID: "anon", Content: "Anonymous."})
+	pub, priv, _ := ed25519.GenerateKey(nil)
+
+	var buf bytes.Buffer
+	if err := api.client().SignedBackup(&buf, priv); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := VerifyBackup(bytes.NewReader(buf.Bytes()), pub); err != nil {
+		t.Errorf("Unexpected verification error: %v", err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if err := VerifyBackup(bytes.NewReader(buf.Bytes()), otherPub); err == nil {
+		t.Errorf("Expected a signature by another key to fail")
+	}
+
+	// Tamper with a post, keeping the checksums
+	files := readTarGz(t, bytes.NewBuffer(buf.Bytes()))
+	files["posts/anon.md"] = "Tampered."
+	var tampered bytes.Buffer
+	gz := gzip.NewWriter(&tampered)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		writeTarFile(tw, name, []byte(data), time.Time{})
+	}
+	tw.Close()
+	gz.Close()
+	if err := VerifyBackup(&tampered, pub); err == nil || !strings.Contains(err.Error(), "posts/anon.md") {
+		t.Errorf("Expected tampering to be caught, got %v", err)
+	}
+
+	buf.Reset()
+	api.client().Backup(&buf)
+	if err := VerifyBackup(&buf, pub); err == nil {
+		t.Errorf("Expected an unsigned backup to fail verification with a key")
+	}
+}
@@ -0,0 +1,75 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type (
+	// BatchResult is the outcome of an operation on many items, like
+	// importing documents, in the order the items were given.
+	BatchResult[T any] struct {
+		Items []BatchItem[T]
+	}
+
+	// BatchItem is the outcome of a batch operation for a single item. Err
+	// is nil if it succeeded.
+	BatchItem[T any] struct {
+		Value T
+		Err   error
+	}
+)
+
+// Succeeded returns the values of the items that succeeded.
+func (r *BatchResult[T]) Succeeded() []T {
+	var vals []T
+	for _, it := range r.Items {
+		if it.Err == nil {
+			vals = append(vals, it.Value)
+		}
+	}
+	return vals
+}
+
+// Failed returns the items that failed, with their errors.
+func (r *BatchResult[T]) Failed() []BatchItem[T] {
+	var items []BatchItem[T]
+	for _, it := range r.Items {
+		if it.Err != nil {
+			items = append(items, it)
+		}
+	}
+	return items
+}
+
+// Err returns the errors of every failed item joined together, or nil if
+// all of them succeeded.
+func (r *BatchResult[T]) Err() error {
+	var errs []error
+	for _, it := range r.Items {
+		if it.Err != nil {
+			errs = append(errs, it.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *BatchResult[T]) add(v T, err error) {
+	r.Items = append(r.Items, BatchItem[T]{Value: v, Err: err})
+}
+
+// ClaimBatch converts the results of ClaimPosts into a BatchResult, with an
+// error for each post that couldn't be claimed.
+func ClaimBatch(results []ClaimPostResult) *BatchResult[ClaimPostResult] {
+	br := &BatchResult[ClaimPostResult]{}
+	for _, res := range results {
+		var err error
+		if res.Code != http.StatusOK {
+			err = fmt.Errorf("Problem claiming post %s: %d. %s", res.ID, res.Code, res.ErrorMessage)
+		}
+		br.add(res, err)
+	}
+	return br
+}
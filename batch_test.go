@@ -0,0 +1,43 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBatchResult(t *testing.T) {
+	br := &BatchResult[string]{}
+	if br.Err() != nil {
+		t.Errorf("Expected no error for an empty batch")
+	}
+	br.add("a", nil)
+	br.add("b", errors.New("first failure"))
+	br.add("c", nil)
+	br.add("d", errors.New("second failure"))
+
+	if ok := br.Succeeded(); len(ok) != 2 || ok[0] != "a" || ok[1] != "c" {
+		t.Errorf("Unexpected successes: %v", ok)
+	}
+	if failed := br.Failed(); len(failed) != 2 || failed[1].Value != "d" {
+		t.Errorf("Unexpected failures: %v", failed)
+	}
+	if err := br.Err(); err == nil || err.Error() != "first failure\nsecond failure" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestClaimBatch(t *testing.T) {
+	br := ClaimBatch([]ClaimPostResult{
+		{ID: "a", Code: http.StatusOK},
+		{ID: "b", Code: http.StatusNotFound, ErrorMessage: "Post not found."},
+	})
+	if len(br.Succeeded()) != 1 || len(br.Failed()) != 1 {
+		t.Errorf("Unexpected batch: %+v", br)
+	}
+	if err := br.Err(); err == nil || !strings.Contains(err.Error(), "post b: 404") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
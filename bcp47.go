@@ -0,0 +1,163 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LanguageError is returned when a language tag isn't a valid BCP 47 tag.
+type LanguageError struct {
+	Tag    string
+	Reason string
+	// Suggestions holds valid tags that may have been intended.
+	Suggestions []string
+}
+
+func (e *LanguageError) Error() string {
+	msg := fmt.Sprintf("Invalid language %q: %s", e.Tag, e.Reason)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(" Did you mean %s?", strings.Join(e.Suggestions, ", "))
+	}
+	return msg
+}
+
+// iso639 maps ISO 639-1 language codes to their English names.
+var iso639 = parseLanguageTable(`aa Afar|ab Abkhazian|ae Avestan|af Afrikaans|ak Akan|am Amharic|an Aragonese|ar Arabic|as Assamese|av Avaric|ay Aymara|az Azerbaijani|ba Bashkir|be Belarusian|bg Bulgarian|bi Bislama|bm Bambara|bn Bengali|bo Tibetan|br Breton|bs Bosnian|ca Catalan|ce Chechen|ch Chamorro|co Corsican|cr Cree|cs Czech|cu Church Slavic|cv Chuvash|cy Welsh|da Danish|de German|dv Divehi|dz Dzongkha|ee Ewe|el Greek|en English|eo Esperanto|es Spanish|et Estonian|eu Basque|fa Persian|ff Fulah|fi Finnish|fj Fijian|fo Faroese|fr French|fy Western Frisian|ga Irish|gd Scottish Gaelic|gl Galician|gn Guarani|gu Gujarati|gv Manx|ha Hausa|he Hebrew|hi Hindi|ho Hiri Motu|hr Croatian|ht Haitian|hu Hungarian|hy Armenian|hz Herero|ia Interlingua|id Indonesian|ie Interlingue|ig Igbo|ii Sichuan Yi|ik Inupiaq|io Ido|is Icelandic|it Italian|iu Inuktitut|ja Japanese|jv Javanese|ka Georgian|kg Kongo|ki Kikuyu|kj Kuanyama|kk Kazakh|kl Kalaallisut|km Khmer|kn Kannada|ko Korean|kr Kanuri|ks Kashmiri|ku Kurdish|kv Komi|kw Cornish|ky Kyrgyz|la Latin|lb Luxembourgish|lg Ganda|li Limburgish|ln Lingala|lo Lao|lt Lithuanian|lu Luba-Katanga|lv Latvian|mg Malagasy|mh Marshallese|mi Maori|mk Macedonian|ml Malayalam|mn Mongolian|mr Marathi|ms Malay|mt Maltese|my Burmese|na Nauru|nb Norwegian Bokmål|nd North Ndebele|ne Nepali|ng Ndonga|nl Dutch|nn Norwegian Nynorsk|no Norwegian|nr South Ndebele|nv Navajo|ny Chichewa|oc Occitan|oj Ojibwa|om Oromo|or Oriya|os Ossetian|pa Punjabi|pi Pali|pl Polish|ps Pashto|pt Portuguese|qu Quechua|rm Romansh|rn Rundi|ro Romanian|ru Russian|rw Kinyarwanda|sa Sanskrit|sc Sardinian|sd Sindhi|se Northern Sami|sg Sango|si Sinhala|sk Slovak|sl Slovenian|sm Samoan|sn Shona|so Somali|sq Albanian|sr Serbian|ss Swati|st Southern Sotho|su Sundanese|sv Swedish|sw Swahili|ta Tamil|te Telugu|tg Tajik|th Thai|ti Tigrinya|tk Turkmen|tl Tagalog|tn Tswana|to Tonga|tr Turkish|ts Tsonga|tt Tatar|tw Twi|ty Tahitian|ug Uyghur|uk Ukrainian|ur Urdu|uz Uzbek|ve Venda|vi Vietnamese|vo Volapük|wa Walloon|wo Wolof|xh Xhosa|yi Yiddish|yo Yoruba|za Zhuang|zh Chinese|zu Zulu`)
+
+func parseLanguageTable(table string) map[string]string {
+	m := map[string]string{}
+	for _, entry := range strings.Split(table, "|") {
+		parts := strings.SplitN(entry, " ", 2)
+		m[parts[0]] = parts[1]
+	}
+	return m
+}
+
+// NormalizeLanguage validates a BCP 47 language tag and returns it in its
+// canonical case, e.g. "EN_us" becomes "en-US" and "zh-hant-tw" becomes
+// "zh-Hant-TW". Two-letter primary languages must be ISO 639-1 codes; any
+// error is a *LanguageError listing near matches.
+func NormalizeLanguage(tag string) (string, error) {
+	orig := tag
+	tag = strings.Replace(strings.TrimSpace(tag), "_", "-", -1)
+	if tag == "" {
+		return "", &LanguageError{Tag: orig, Reason: "tag is empty."}
+	}
+	subtags := strings.Split(tag, "-")
+	for _, s := range subtags {
+		if s == "" || len(s) > 8 || !isAlnum(s) {
+			return "", &LanguageError{Tag: orig, Reason: fmt.Sprintf("malformed subtag %q.", s), Suggestions: suggestLanguages(subtags[0])}
+		}
+	}
+
+	lang := strings.ToLower(subtags[0])
+	if !isAlpha(lang) || len(lang) < 2 || len(lang) > 3 {
+		return "", &LanguageError{Tag: orig, Reason: fmt.Sprintf("%q isn't a language code.", subtags[0]), Suggestions: suggestLanguages(subtags[0])}
+	}
+	if _, ok := iso639[lang]; len(lang) == 2 && !ok {
+		return "", &LanguageError{Tag: orig, Reason: fmt.Sprintf("unknown language %q.", lang), Suggestions: suggestLanguages(lang)}
+	}
+
+	out := []string{lang}
+	rest := subtags[1:]
+	for i, s := range rest {
+		l := strings.ToLower(s)
+		switch {
+		case len(l) == 1:
+			// Extensions and private use subtags are lowercase, whatever
+			// their length
+			for _, e := range rest[i:] {
+				out = append(out, strings.ToLower(e))
+			}
+			return strings.Join(out, "-"), nil
+		case len(l) == 4 && isAlpha(l):
+			out = append(out, strings.ToUpper(l[:1])+l[1:])
+		case len(l) == 2 && isAlpha(l), len(l) == 3 && isDigits(l):
+			out = append(out, strings.ToUpper(l))
+		case len(l) == 3 && isAlpha(l) && i == 0:
+			out = append(out, l)
+		case len(l) >= 5, len(l) == 4 && l[0] >= '0' && l[0] <= '9':
+			out = append(out, l)
+		default:
+			return "", &LanguageError{Tag: orig, Reason: fmt.Sprintf("unexpected subtag %q.", s), Suggestions: suggestLanguages(lang)}
+		}
+	}
+	return strings.Join(out, "-"), nil
+}
+
+// suggestLanguages returns ISO 639-1 codes that are close to the given code or
+// language name.
+func suggestLanguages(s string) []string {
+	s = strings.ToLower(s)
+	var sugg []string
+	for code, name := range iso639 {
+		lname := strings.ToLower(name)
+		if lname == s || (len(s) > 3 && strings.HasPrefix(lname, s)) || (len(s) <= 3 && editDistance(code, s) == 1 && code[0] == s[0]) {
+			sugg = append(sugg, code)
+		}
+	}
+	sort.Strings(sugg)
+	if len(sugg) > 5 {
+		sugg = sugg[:5]
+	}
+	return sugg
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func minInt(vals ...int) int {
+	m := vals[0]
+	for _, v := range vals[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
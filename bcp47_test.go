@@ -0,0 +1,36 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	valid := map[string]string{
+		"EN-us":           "en-US",
+		"zh_hant_tw":      "zh-Hant-TW",
+		"es-419":          "es-419",
+		"sl-rozaj-biske":  "sl-rozaj-biske",
+		"de-DE-x-Phonebk": "de-DE-x-phonebk",
+		"en-US-u-CA-GB":   "en-US-u-ca-gb",
+		"yue":             "yue",
+	}
+	for in, expected := range valid {
+		res, err := NormalizeLanguage(in)
+		if err != nil || res != expected {
+			t.Errorf("NormalizeLanguage(%q) = %q, %v; expected %q", in, res, err, expected)
+		}
+	}
+
+	_, err := NormalizeLanguage("english")
+	le, ok := err.(*LanguageError)
+	if !ok || len(le.Suggestions) != 1 || le.Suggestions[0] != "en" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, in := range []string{"", "e", "en-", "xq", "en-US-!"} {
+		if _, err := NormalizeLanguage(in); err == nil {
+			t.Errorf("Expected %q to be invalid", in)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type (
+	// BlockAction determines what happens when a BlocklistRule matches.
+	BlockAction int
+
+	// BlocklistRule matches content that an organization wants to reject or
+	// review before it's published.
+	BlocklistRule struct {
+		Name    string
+		Pattern *regexp.Regexp
+		Action  BlockAction
+	}
+
+	// Blocklist is a Filter that checks post content against a set of rules.
+	// Content matching any Block rule is rejected with an *ErrContentBlocked;
+	// content matching Flag rules is published, after OnFlag is called.
+	Blocklist struct {
+		Rules []BlocklistRule
+
+		// OnFlag is called with the matched rules when content matches only
+		// Flag rules. It may be nil.
+		OnFlag func(content string, rules []BlocklistRule)
+	}
+
+	// ErrContentBlocked is returned when content matches one or more Block
+	// rules in a Blocklist.
+	ErrContentBlocked struct {
+		Rules []BlocklistRule
+	}
+)
+
+const (
+	// Block rejects matching content.
+	Block BlockAction = iota
+	// Flag allows matching content, but reports it to Blocklist.OnFlag.
+	Flag
+)
+
+// NewKeywordRule returns a BlocklistRule matching any of the given words,
+// case-insensitively and on word boundaries. A word that starts or ends with
+// a symbol, like "c++" or "@admin", only needs a boundary on the sides that
+// are letters or digits. Empty words are ignored, so a rule without any words
+// matches nothing.
+func NewKeywordRule(name string, action BlockAction, words ...string) BlocklistRule {
+	rule := BlocklistRule{Name: name, Action: action}
+	var quoted []string
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w == "" {
+			continue
+		}
+		q := regexp.QuoteMeta(w)
+		if first, _ := utf8.DecodeRuneInString(w); isKeywordRune(first) {
+			q = `(?:^|[^\p{L}\p{N}_])` + q
+		}
+		if last, _ := utf8.DecodeLastRuneInString(w); isKeywordRune(last) {
+			q += `(?:[^\p{L}\p{N}_]|$)`
+		}
+		quoted = append(quoted, q)
+	}
+	if len(quoted) > 0 {
+		rule.Pattern = regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	}
+	return rule
+}
+
+// isKeywordRune returns whether r is part of a word, for keyword boundaries.
+func isKeywordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (e *ErrContentBlocked) Error() string {
+	names := make([]string, len(e.Rules))
+	for i, r := range e.Rules {
+		names[i] = r.Name
+	}
+	return fmt.Sprintf("Content blocked by rules: %s", strings.Join(names, ", "))
+}
+
+// Match returns all rules in the Blocklist that match the given content.
+func (bl *Blocklist) Match(content string) []BlocklistRule {
+	var matched []BlocklistRule
+	for _, r := range bl.Rules {
+		if r.Pattern != nil && r.Pattern.MatchString(content) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// Filter implements the Filter interface, leaving content unchanged.
+func (bl *Blocklist) Filter(content string) (string, error) {
+	matched := bl.Match(content)
+	if len(matched) == 0 {
+		return content, nil
+	}
+
+	var blocked []BlocklistRule
+	for _, r := range matched {
+		if r.Action == Block {
+			blocked = append(blocked, r)
+		}
+	}
+	if len(blocked) > 0 {
+		return "", &ErrContentBlocked{Rules: blocked}
+	}
+	if bl.OnFlag != nil {
+		bl.OnFlag(content, matched)
+	}
+	return content, nil
+}
@@ -0,0 +1,63 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestBlocklist(t *testing.T) {
+	var flagged []BlocklistRule
+	bl := &Blocklist{
+		Rules: []BlocklistRule{
+			NewKeywordRule("secrets", Block, "confidential", "internal only"),
+			NewKeywordRule("review", Flag, "lawsuit"),
+		},
+		OnFlag: func(content string, rules []BlocklistRule) {
+			flagged = rules
+		},
+	}
+
+	_, err := bl.Filter("This memo is CONFIDENTIAL.")
+	if eb, ok := err.(*ErrContentBlocked); !ok {
+		t.Errorf("Expected ErrContentBlocked, got: %v", err)
+	} else if len(eb.Rules) != 1 || eb.Rules[0].Name != "secrets" {
+		t.Errorf("Unexpected blocked rules: %+v", eb.Rules)
+	}
+
+	_, err = bl.Filter("No lawsuit was filed.")
+	if err != nil {
+		t.Errorf("Expected flagged content to pass, got: %v", err)
+	}
+	if len(flagged) != 1 || flagged[0].Name != "review" {
+		t.Errorf("Unexpected flagged rules: %+v", flagged)
+	}
+}
+
+func TestKeywordRuleWithoutWords(t *testing.T) {
+	bl := &Blocklist{Rules: []BlocklistRule{
+		NewKeywordRule("empty", Block),
+		NewKeywordRule("blank", Block, "", " "),
+	}}
+	if m := bl.Match("Anything at all."); len(m) != 0 {
+		t.Errorf("Expected rules without words to match nothing, got %v", m)
+	}
+}
+
+func TestKeywordRuleSymbols(t *testing.T) {
+	rule := NewKeywordRule("symbols", Block, "c++", "@admin", "café")
+	tests := map[string]bool{
+		"I write C++ every day.":  true,
+		"Ask @admin for access.":  true,
+		"(@admin)":                true,
+		"Meet at the café.":       true,
+		"I write c every day.":    false,
+		"Ask @administrator.":     false,
+		"Email admin@example.com": false,
+		"A cafés crawl.":          false,
+	}
+	for content, matches := range tests {
+		if got := rule.Pattern.MatchString(content); got != matches {
+			t.Errorf("Unexpected match for %q: %v", content, got)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// CachedResponse is the body of a successful read response, kept in a
+	// ResponseCache.
+	CachedResponse struct {
+		Body []byte `json:"body"`
+		// Expires is when the response stops being fresh. A stale response
+		// is revalidated with the API if it has an ETag or LastModified, and
+		// fetched again otherwise.
+		Expires      time.Time `json:"expires"`
+		ETag         string    `json:"etag,omitempty"`
+		LastModified string    `json:"last_modified,omitempty"`
+	}
+
+	// ResponseCache keeps the Client's read responses. Keys identify the
+	// request, including who made it, so responses to one user aren't
+	// returned to another.
+	ResponseCache interface {
+		// Response returns the cached response for the key, or nil if there
+		// isn't one.
+		Response(key string) (*CachedResponse, error)
+		SaveResponse(key string, r *CachedResponse) error
+		// Clear removes every response, since a write could have changed
+		// any of them.
+		Clear() error
+	}
+
+	// MemoryResponseCache is a ResponseCache that keeps responses in memory.
+	// It's what the Client uses unless it's given another with
+	// WithResponseCache.
+	MemoryResponseCache struct {
+		mu        sync.Mutex
+		responses map[string]CachedResponse
+	}
+
+	// DirResponseCache is a ResponseCache that keeps each response in a file
+	// in Dir, so processes using the same Dir share it, like successive runs
+	// of a command-line client.
+	DirResponseCache struct {
+		Dir string
+	}
+)
+
+// WithResponseCache sets where the Client caches read responses. With one
+// set, responses are cached as long as the API's Cache-Control or Expires
+// headers allow, or for the ReadEndpoints Policy's CacheTTL if they don't say.
+//
+//	dir, _ := os.UserCacheDir()
+//	c := writeas.NewClient(
+//		writeas.WithResponseCache(&writeas.DirResponseCache{Dir: filepath.Join(dir, "writeas", "http")}),
+//		writeas.WithPolicy(writeas.ReadEndpoints, writeas.Policy{CacheTTL: time.Minute}),
+//	)
+func WithResponseCache(rc ResponseCache) Option {
+	return func(c *Client) {
+		c.cache = rc
+	}
+}
+
+// responseCache returns the cache the Client keeps responses in.
+func (c *Client) responseCache() ResponseCache {
+	if c.cache != nil {
+		return c.cache
+	}
+	return &c.memCache
+}
+
+// cachesReads reports whether the Client caches responses to reads.
+func (c *Client) cachesReads() bool {
+	return c.cache != nil || c.policy(ReadEndpoints).CacheTTL > 0
+}
+
+// cacheResponse reads the response to a cacheable request, saving it in the
+// cache if it's successful, and returns its status and body. A 304 Not
+// Modified response to a request revalidating cached returns cached's body,
+// with 200 OK.
+func (c *Client) cacheResponse(key string, cached *CachedResponse, resp *http.Response, ps *policyState) (int, []byte, error) {
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		if cr := cachedResponse(resp.Header, cached.Body, c.Now(), ps.CacheTTL); cr != nil {
+			if cr.ETag == "" && cr.LastModified == "" {
+				cr.ETag, cr.LastModified = cached.ETag, cached.LastModified
+			}
+			c.responseCache().SaveResponse(key, cr)
+		}
+		return http.StatusOK, cached.Body, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		if cr := cachedResponse(resp.Header, body, c.Now(), ps.CacheTTL); cr != nil {
+			c.responseCache().SaveResponse(key, cr)
+		}
+	}
+	return resp.StatusCode, body, nil
+}
+
+// revalidate refreshes a stale cached response in the background, emitting
+// ResponseRefreshed if it changed. Only one refresh of each response runs at
+// a time.
+func (c *Client) revalidate(r *http.Request, key string, cached *CachedResponse, ps *policyState) {
+	if _, running := c.revalidating.LoadOrStore(key, true); running {
+		return
+	}
+	rr := r.Clone(context.Background())
+	cached.setValidators(rr)
+	go func() {
+		defer c.revalidating.Delete(key)
+		resp, err := c.send(rr, ps)
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+		status, body, err := c.cacheResponse(key, cached, resp, ps)
+		if err == nil && status == http.StatusOK && !bytes.Equal(body, cached.Body) {
+			c.emit(ResponseRefreshed{Path: c.apiPath(r.URL.Path)})
+		}
+	}()
+}
+
+// cacheKey identifies a request in the cache. Responses can depend on who's
+// asking, and an Authenticator can send credentials in any header, so it
+// includes a hash of every header along with the URL, which has any query
+// credentials.
+func cacheKey(r *http.Request) string {
+	names := make([]string, 0, len(r.Header))
+	for name := range r.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		for _, v := range r.Header[name] {
+			fmt.Fprintf(h, "%s %d\n%s\n", name, len(v), v)
+		}
+	}
+	return r.Method + " " + r.URL.String() + " " + hex.EncodeToString(h.Sum(nil))
+}
+
+// cachedResponse returns a CachedResponse of the given body, fresh for as
+// long as the response headers say, or ttl if they don't. It returns nil if
+// the response shouldn't be cached.
+func cachedResponse(h http.Header, body []byte, now time.Time, ttl time.Duration) *CachedResponse {
+	cr := &CachedResponse{
+		Body:         body,
+		ETag:         h.Get("ETag"),
+		LastModified: h.Get("Last-Modified"),
+	}
+	freshness, ok := responseFreshness(h)
+	if ok && freshness < 0 {
+		return nil
+	} else if !ok {
+		freshness = ttl
+	}
+	if freshness <= 0 && cr.ETag == "" && cr.LastModified == "" {
+		return nil
+	}
+	cr.Expires = now.Add(freshness)
+	return cr
+}
+
+// responseFreshness returns how long a response with the given headers is
+// fresh, and false if they don't say. It's negative if the response mustn't
+// be stored.
+func responseFreshness(h http.Header) (time.Duration, bool) {
+	for _, d := range strings.Split(h.Get("Cache-Control"), ",") {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "no-store" {
+			return -1, true
+		}
+	}
+	for _, d := range strings.Split(h.Get("Cache-Control"), ",") {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "no-cache" {
+			return 0, true
+		}
+		if strings.HasPrefix(d, "max-age=") {
+			secs, err := strconv.Atoi(strings.TrimPrefix(d, "max-age="))
+			if err == nil {
+				return time.Duration(secs) * time.Second, true
+			}
+		}
+	}
+	if e := h.Get("Expires"); e != "" {
+		expires, err := http.ParseTime(e)
+		if err != nil {
+			return 0, true
+		}
+		date, err := http.ParseTime(h.Get("Date"))
+		if err != nil {
+			date = time.Now()
+		}
+		return expires.Sub(date), true
+	}
+	return 0, false
+}
+
+// setValidators makes the request conditional on the cached response having
+// changed.
+func (cr *CachedResponse) setValidators(r *http.Request) {
+	if cr.ETag != "" {
+		r.Header.Set("If-None-Match", cr.ETag)
+	}
+	if cr.LastModified != "" {
+		r.Header.Set("If-Modified-Since", cr.LastModified)
+	}
+}
+
+// Response implements the ResponseCache interface.
+func (rc *MemoryResponseCache) Response(key string) (*CachedResponse, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	r, ok := rc.responses[key]
+	if !ok {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+// SaveResponse implements the ResponseCache interface.
+func (rc *MemoryResponseCache) SaveResponse(key string, r *CachedResponse) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.responses == nil {
+		rc.responses = map[string]CachedResponse{}
+	}
+	rc.responses[key] = *r
+	return nil
+}
+
+// Clear implements the ResponseCache interface.
+func (rc *MemoryResponseCache) Clear() error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.responses = nil
+	return nil
+}
+
+// Response implements the ResponseCache interface.
+func (rc *DirResponseCache) Response(key string) (*CachedResponse, error) {
+	data, err := ioutil.ReadFile(rc.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	r := &CachedResponse{}
+	if err = json.Unmarshal(data, r); err != nil {
+		// Treat a corrupt entry as missing, so it's replaced
+		return nil, nil
+	}
+	return r, nil
+}
+
+// SaveResponse implements the ResponseCache interface. The file is replaced
+// atomically, so other processes never read part of a response.
+func (rc *DirResponseCache) SaveResponse(key string, r *CachedResponse) error {
+	if err := os.MkdirAll(rc.Dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(rc.Dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), rc.path(key))
+}
+
+// Clear implements the ResponseCache interface.
+func (rc *DirResponseCache) Clear() error {
+	files, err := ioutil.ReadDir(rc.Dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		if err = os.Remove(filepath.Join(rc.Dir, f.Name())); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Clear cache: %v", err)
+		}
+	}
+	return nil
+}
+
+// path returns the file a response is kept in. Keys include credentials, so
+// they're hashed rather than used in the name.
+func (rc *DirResponseCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(rc.Dir, hex.EncodeToString(sum[:])+".json")
+}
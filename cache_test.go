@@ -0,0 +1,168 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDirResponseCacheShared(t *testing.T) {
+	dir, err := ioutil.TempDir("", "writeas-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	srv := newPolicyServer()
+	defer srv.Close()
+
+	// Each client stands in for a separate run of a command
+	newClient := func() *Client {
+		return NewClient(WithBaseURL(srv.URL), WithResponseCache(&DirResponseCache{Dir: dir}),
+			WithPolicy(ReadEndpoints, Policy{CacheTTL: time.Minute}))
+	}
+	for i := 0; i < 2; i++ {
+		p, err := newClient().GetPost("abc")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if p.ID != "p1" {
+			t.Errorf("Expected the cached post, got %s", p.ID)
+		}
+	}
+	if len(srv.bodies) != 1 {
+		t.Errorf("Expected 1 request, got %d", len(srv.bodies))
+	}
+
+	c := newClient()
+	c.CreatePost(&PostParams{Content: "Hi"})
+	if p, _ := newClient().GetPost("abc"); p == nil || p.ID != "p3" {
+		t.Errorf("Expected the cache to be cleared by a write, got %+v", p)
+	}
+}
+
+func TestResponseCacheRevalidation(t *testing.T) {
+	requests, notModified := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/posts/private" {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, `{"code":200,"data":{"id":"abc","body":"Hi"}}`)
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithResponseCache(&MemoryResponseCache{}))
+
+	for i := 0; i < 2; i++ {
+		p, err := c.GetPost("abc")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if p.Content != "Hi" {
+			t.Errorf("Unexpected content %q", p.Content)
+		}
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("Expected the second request to be revalidated, got %d requests, %d not modified", requests, notModified)
+	}
+
+	c.GetPost("private")
+	c.GetPost("private")
+	if requests != 4 || notModified != 1 {
+		t.Errorf("Expected a no-store response not to be cached, got %d requests, %d not modified", requests, notModified)
+	}
+}
+
+func TestResponseFreshness(t *testing.T) {
+	tests := []struct {
+		header http.Header
+		want   time.Duration
+		ok     bool
+	}{
+		{http.Header{}, 0, false},
+		{http.Header{"Cache-Control": {"public, max-age=60"}}, time.Minute, true},
+		{http.Header{"Cache-Control": {"no-cache"}}, 0, true},
+		{http.Header{"Cache-Control": {"max-age=60, no-store"}}, -1, true},
+		{http.Header{"Date": {"Mon, 01 Jan 2024 00:00:00 GMT"}, "Expires": {"Mon, 01 Jan 2024 00:02:00 GMT"}}, 2 * time.Minute, true},
+	}
+	for _, test := range tests {
+		if got, ok := responseFreshness(test.header); got != test.want || ok != test.ok {
+			t.Errorf("Unexpected freshness of %v: %s, %t", test.header, got, ok)
+		}
+	}
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	srv := newPolicyServer()
+	defer srv.Close()
+	var mu sync.Mutex
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClient(WithBaseURL(srv.URL),
+		WithClock(ClockFunc(func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return now
+		})),
+		WithPolicy(ReadEndpoints, Policy{CacheTTL: time.Minute, StaleWhileRevalidate: time.Hour}))
+	refreshed := make(chan string, 1)
+	c.Subscribe(func(e Event) {
+		if e, ok := e.(ResponseRefreshed); ok {
+			refreshed <- e.Path
+		}
+	})
+
+	get := func() string {
+		p, err := c.GetPost("abc")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return p.ID
+	}
+	get()
+	mu.Lock()
+	now = now.Add(2 * time.Minute)
+	mu.Unlock()
+	if id := get(); id != "p1" {
+		t.Errorf("Expected the stale response, got %s", id)
+	}
+	select {
+	case path := <-refreshed:
+		if path != "/posts/abc" {
+			t.Errorf("Unexpected refreshed path %s", path)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a ResponseRefreshed event")
+	}
+	if id := get(); id != "p2" {
+		t.Errorf("Expected the refreshed response, got %s", id)
+	}
+}
+
+func TestCacheKeyCredentials(t *testing.T) {
+	key := func(a Authenticator) string {
+		c := NewClient(WithAuthenticator(a))
+		r, _ := c.buildRequest("GET", "/posts/abc", nil)
+		return cacheKey(r)
+	}
+	if key(&HeaderAuth{Header: "X-Api-Key", Value: "alice"}) == key(&HeaderAuth{Header: "X-Api-Key", Value: "bob"}) {
+		t.Errorf("Expected different header credentials to get different keys")
+	}
+	if key(&BasicAuth{Username: "alice", Password: "a"}) == key(&BasicAuth{Username: "alice", Password: "b"}) {
+		t.Errorf("Expected different basic credentials to get different keys")
+	}
+	if k := key(&HeaderAuth{Header: "X-Api-Key", Value: "alice"}); strings.Contains(k, "alice") {
+		t.Errorf("Expected credentials not to appear in the key %q", k)
+	}
+}
@@ -0,0 +1,61 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ClaimPostByURL claims the anonymous post at the given URL, as copied from a
+// browser, with its token. The URL must be on the instance the Client talks
+// to.
+func (c *Client) ClaimPostByURL(postURL, token string) (*ClaimPostResult, error) {
+	id, err := c.postIDFromURL(postURL)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.ClaimPosts(&[]OwnedPostParams{{ID: id, Token: token}})
+	if err != nil {
+		return nil, err
+	}
+	if len(*res) == 0 {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+	item := ClaimBatch(*res).Items[0]
+	return &item.Value, item.Err
+}
+
+// postIDFromURL returns the ID of the anonymous post at the given URL on the
+// Client's instance, like "https://write.as/rf3t35fkax0aw" or the same with a
+// ".md" or ".txt" extension.
+func (c *Client) postIDFromURL(postURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(postURL))
+	if err != nil {
+		return "", fmt.Errorf("Invalid post URL: %v", err)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("Invalid post URL %q: missing host.", postURL)
+	}
+	prefix := ""
+	if inst, err := url.Parse(c.instanceURL()); err == nil {
+		if !strings.EqualFold(u.Host, inst.Host) {
+			return "", fmt.Errorf("Post URL %q isn't on %s.", postURL, inst.Host)
+		}
+		prefix = strings.TrimRight(inst.Path, "/")
+	}
+
+	p := strings.Trim(strings.TrimPrefix(u.Path, prefix), "/")
+	if p == "" {
+		return "", fmt.Errorf("Post URL %q has no post ID.", postURL)
+	}
+	if strings.Contains(p, "/") {
+		return "", fmt.Errorf("Post URL %q is in a collection; only anonymous posts can be claimed.", postURL)
+	}
+	switch path.Ext(p) {
+	case ".md", ".txt":
+		p = strings.TrimSuffix(p, path.Ext(p))
+	}
+	return ParsePostID(p)
+}
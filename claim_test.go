@@ -0,0 +1,47 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestClaimPostByURL(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.addPost("", Post{ID: "rf3t35fkax0aw", Token: "tok"})
+	c := api.client()
+
+	res, err := c.ClaimPostByURL(api.URL+"/rf3t35fkax0aw.md", "tok")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.Post == nil || res.Post.OwnerName != "writer" {
+		t.Errorf("Unexpected result: %+v", res)
+	}
+
+	if _, err = c.ClaimPostByURL(api.URL+"/rf3t35fkax0aw", "wrong"); err == nil {
+		t.Errorf("Expected an error for the wrong token")
+	}
+}
+
+func TestPostIDFromURL(t *testing.T) {
+	c := NewClient()
+	tests := map[string]string{
+		"https://write.as/rf3t35fkax0aw":      "rf3t35fkax0aw",
+		" https://write.as/rf3t35fkax0aw/ ":   "rf3t35fkax0aw",
+		"https://write.as/rf3t35fkax0aw.txt":  "rf3t35fkax0aw",
+		"https://Write.as/rf3t35fkax0aw?a=b":  "rf3t35fkax0aw",
+		"https://write.as/blog/my-first-post": "",
+		"https://example.com/rf3t35fkax0aw":   "",
+		"https://write.as/":                   "",
+		"write.as/rf3t35fkax0aw":              "",
+	}
+	for u, want := range tests {
+		id, err := c.postIDFromURL(u)
+		if want == "" && err == nil {
+			t.Errorf("Expected an error for %q, got %q", u, id)
+		} else if want != "" && (err != nil || id != want) {
+			t.Errorf("Unexpected ID for %q: %q, %v", u, id, err)
+		}
+	}
+}
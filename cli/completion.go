@@ -0,0 +1,193 @@
+#author: Nguyễn Thái Sơn
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/writeas/go-writeas"
+)
+
+// CompleteCommand is the hidden subcommand the completion scripts run to get
+// completions, with the words typed so far after the program name.
+const CompleteCommand = "__complete"
+
+// MaxRecentPosts is the most post IDs a CompletionCache remembers.
+const MaxRecentPosts = 50
+
+type (
+	// Completer completes the arguments of a program's commands: command
+	// names first, then collection aliases after CollectionFlags and post IDs
+	// elsewhere, from the Cache.
+	Completer struct {
+		Commands []string
+		// CollectionFlags are the flags that take a collection alias, like
+		// "--collection" or "-c".
+		CollectionFlags []string
+		Cache           *CompletionCache
+	}
+
+	// CompletionCache is a JSON file of the collection aliases and post IDs
+	// the program has seen recently, so completing them doesn't need a
+	// request. Commands call Remember with what they fetch or publish.
+	CompletionCache struct {
+		Path string
+	}
+
+	completionData struct {
+		Collections []string `json:"collections"`
+		Posts       []string `json:"posts"`
+	}
+)
+
+// Run writes the completions for args, the words typed so far ending with
+// the one being completed, one per line, if args starts with
+// CompleteCommand. It returns whether it did, so programs can call it first
+// thing:
+//
+//	if comp.Run(os.Stdout, os.Args[1:]) {
+//		return
+//	}
+func (cp *Completer) Run(w io.Writer, args []string) bool {
+	if len(args) == 0 || args[0] != CompleteCommand {
+		return false
+	}
+	for _, s := range cp.Complete(args[1:]) {
+		fmt.Fprintln(w, s)
+	}
+	return true
+}
+
+// Complete returns the completions of the last of args, the words typed so
+// far after the program name.
+func (cp *Completer) Complete(args []string) []string {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	cur := args[len(args)-1]
+	if len(args) == 1 {
+		return withPrefix(cp.Commands, cur)
+	}
+	if strings.HasPrefix(cur, "-") || cp.Cache == nil {
+		return nil
+	}
+
+	data, err := cp.Cache.load()
+	if err != nil {
+		return nil
+	}
+	prev := args[len(args)-2]
+	for _, f := range cp.CollectionFlags {
+		if prev == f {
+			return withPrefix(data.Collections, cur)
+		}
+	}
+	return withPrefix(data.Posts, cur)
+}
+
+func withPrefix(candidates []string, prefix string) []string {
+	res := []string{}
+	for _, s := range candidates {
+		if strings.HasPrefix(s, prefix) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// Remember adds collection aliases and posts to the cache. Posts are
+// remembered most recent first, so pass them oldest first, and only the
+// latest MaxRecentPosts are kept.
+func (cc *CompletionCache) Remember(aliases []string, posts []writeas.Post) error {
+	data, err := cc.load()
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	for _, a := range data.Collections {
+		seen[a] = true
+	}
+	for _, a := range aliases {
+		if !seen[a] {
+			seen[a] = true
+			data.Collections = append(data.Collections, a)
+		}
+	}
+	sort.Strings(data.Collections)
+
+	ids := make([]string, 0, len(posts)+len(data.Posts))
+	for i := len(posts) - 1; i >= 0; i-- {
+		ids = append(ids, posts[i].ID)
+	}
+	ids = append(ids, data.Posts...)
+	data.Posts = data.Posts[:0]
+	seen = map[string]bool{}
+	for _, id := range ids {
+		if id != "" && !seen[id] && len(data.Posts) < MaxRecentPosts {
+			seen[id] = true
+			data.Posts = append(data.Posts, id)
+		}
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(cc.Path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(cc.Path, b, 0600)
+}
+
+func (cc *CompletionCache) load() (*completionData, error) {
+	data := &completionData{}
+	b, err := ioutil.ReadFile(cc.Path)
+	if os.IsNotExist(err) {
+		return data, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, data); err != nil {
+		return nil, fmt.Errorf("Invalid completion cache %s: %v", cc.Path, err)
+	}
+	return data, nil
+}
+
+var nonIdentChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// CompletionScript returns the script that sets up completion of the
+// program's arguments in the given shell, "bash", "zsh", or "fish", by
+// running it with CompleteCommand.
+func CompletionScript(shell, prog string) (string, error) {
+	fn := "_" + nonIdentChars.ReplaceAllString(prog, "_") + "_complete"
+	switch shell {
+	case "bash":
+		return fmt.Sprintf(`%[1]s() {
+	local IFS=$'\n'
+	COMPREPLY=($(%[2]s %[3]s "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F %[1]s %[2]s
+`, fn, prog, CompleteCommand), nil
+	case "zsh":
+		return fmt.Sprintf(`#compdef %[2]s
+%[1]s() {
+	local -a completions
+	completions=("${(@f)$(%[2]s %[3]s "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	compadd -a completions
+}
+compdef %[1]s %[2]s
+`, fn, prog, CompleteCommand), nil
+	case "fish":
+		return fmt.Sprintf(`complete -c %[1]s -f -a '(%[1]s %[2]s (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`, prog, CompleteCommand), nil
+	}
+	return "", fmt.Errorf("Unsupported shell %q: only bash, zsh, and fish are.", shell)
+}
@@ -0,0 +1,83 @@
+#author: Nguyễn Thái Sơn
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestCompleter(t *testing.T) {
+	cache := &CompletionCache{Path: filepath.Join(t.TempDir(), "cache", "completion.json")}
+	if err := cache.Remember([]string{"notes", "blog"}, []writeas.Post{{ID: "abc"}, {ID: "abd"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := cache.Remember([]string{"blog"}, []writeas.Post{{ID: "xyz"}, {ID: "abc"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cp := &Completer{
+		Commands:        []string{"post", "publish", "delete"},
+		CollectionFlags: []string{"-c", "--collection"},
+		Cache:           cache,
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"p"}, []string{"post", "publish"}},
+		{[]string{"publish", "-c", ""}, []string{"blog", "notes"}},
+		{[]string{"publish", "--collection", "n"}, []string{"notes"}},
+		{[]string{"delete", ""}, []string{"abc", "xyz", "abd"}},
+		{[]string{"delete", "ab"}, []string{"abc", "abd"}},
+		{[]string{"delete", "--"}, nil},
+	}
+	for _, test := range tests {
+		if got := cp.Complete(test.args); fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("Unexpected completions of %q: %q", test.args, got)
+		}
+	}
+
+	var b bytes.Buffer
+	if !cp.Run(&b, []string{CompleteCommand, "del"}) || b.String() != "delete\n" {
+		t.Errorf("Unexpected Run output: %q", b.String())
+	}
+	if cp.Run(&b, []string{"delete"}) {
+		t.Errorf("Expected Run to ignore other commands")
+	}
+}
+
+func TestRememberLimit(t *testing.T) {
+	cache := &CompletionCache{Path: filepath.Join(t.TempDir(), "completion.json")}
+	posts := []writeas.Post{}
+	for i := 0; i < MaxRecentPosts+10; i++ {
+		posts = append(posts, writeas.Post{ID: fmt.Sprintf("p%d", i)})
+	}
+	cache.Remember(nil, posts)
+	data, err := cache.load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(data.Posts) != MaxRecentPosts || data.Posts[0] != fmt.Sprintf("p%d", MaxRecentPosts+9) {
+		t.Errorf("Unexpected remembered posts: %v", data.Posts)
+	}
+}
+
+func TestCompletionScript(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		s, err := CompletionScript(shell, "writeas")
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", shell, err)
+		}
+		if !strings.Contains(s, "writeas "+CompleteCommand) {
+			t.Errorf("Unexpected %s script:\n%s", shell, s)
+		}
+	}
+	if _, err := CompletionScript("tcsh", "writeas"); err == nil {
+		t.Errorf("Expected an error for an unsupported shell")
+	}
+}
@@ -0,0 +1,96 @@
+#author: Nguyễn Thái Sơn
+// Package cli provides building blocks for command-line programs built on the
+// Write.as client, like the writeas CLI, so their commands behave the same
+// way for users and scripts.
+//
+//	out := &cli.Output{}
+//	out.Flags(fs)
+//	fs.Parse(args)
+//	posts, err := c.GetUserPosts()
+//	...
+//	return out.Print(os.Stdout, *posts, func(w io.Writer) error {
+//		for _, p := range *posts {
+//			fmt.Fprintf(w, "%s  %s\n", p.ID, p.Title)
+//		}
+//		return nil
+//	})
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// Output is how a command prints its result: as text for people by default,
+// or as JSON or through a template for scripts.
+type Output struct {
+	// JSON prints the result as indented JSON.
+	JSON bool
+	// Template prints the result with a text/template. A slice result is
+	// printed one element at a time, each followed by a newline.
+	Template string
+}
+
+// Flags registers the --json and --template flags on fs.
+func (o *Output) Flags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.JSON, "json", false, "Print the result as JSON")
+	fs.StringVar(&o.Template, "template", "", "Print the result with the given Go template, once per item for lists")
+}
+
+// Print writes v to w in the chosen format, calling human to write it for
+// people when neither JSON nor a template was chosen.
+func (o *Output) Print(w io.Writer, v interface{}, human func(io.Writer) error) error {
+	if o.JSON && o.Template != "" {
+		return fmt.Errorf("Only one of --json and --template can be used.")
+	}
+	if o.JSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	if o.Template != "" {
+		return o.printTemplate(w, v)
+	}
+	return human(w)
+}
+
+func (o *Output) printTemplate(w io.Writer, v interface{}) error {
+	t, err := template.New("output").Funcs(templateFuncs).Parse(o.Template)
+	if err != nil {
+		return fmt.Errorf("Invalid template: %v", err)
+	}
+
+	items := []interface{}{v}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		items = make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+	}
+	for _, item := range items {
+		if err = t.Execute(w, item); err != nil {
+			return err
+		}
+		if _, err = io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateFuncs are the functions available to --template, beyond the
+// text/template builtins.
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
@@ -0,0 +1,54 @@
+#author: Nguyễn Thái Sơn
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestOutput(t *testing.T) {
+	posts := []writeas.Post{
+		{ID: "a", Title: "First", Tags: []string{"go", "cli"}},
+		{ID: "b", Title: "Second"},
+	}
+	human := func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "2 posts")
+		return err
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "2 posts\n"},
+		{[]string{"--template", `{{.ID}}: {{.Title | upper}} [{{join .Tags ","}}]`}, "a: FIRST [go,cli]\nb: SECOND []\n"},
+		{[]string{"--json"}, "[\n  {\n    \"id\": \"a\""},
+	}
+	for _, test := range tests {
+		out := &Output{}
+		fs := flag.NewFlagSet("posts", flag.ContinueOnError)
+		out.Flags(fs)
+		if err := fs.Parse(test.args); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var b bytes.Buffer
+		if err := out.Print(&b, posts, human); err != nil {
+			t.Errorf("Unexpected error with %v: %v", test.args, err)
+		}
+		if got := b.String(); len(got) < len(test.want) || got[:len(test.want)] != test.want {
+			t.Errorf("Unexpected output with %v:\n%s", test.args, got)
+		}
+	}
+
+	if err := (&Output{JSON: true, Template: "{{.}}"}).Print(io.Discard, posts, human); err == nil {
+		t.Errorf("Expected an error with both --json and --template")
+	}
+	if err := (&Output{Template: "{{"}).Print(io.Discard, posts, human); err == nil {
+		t.Errorf("Expected an error with an invalid template")
+	}
+}
@@ -0,0 +1,101 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+type (
+	// Clock tells the time. Tests can use a fixed Clock to make
+	// time-dependent behavior, like view sampling, deterministic.
+	Clock interface {
+		Now() time.Time
+	}
+
+	// ClockFunc is a Clock that calls the given function.
+	ClockFunc func() time.Time
+
+	// Scheduler is a Clock that can also call functions later, by its own
+	// time. When the Client's Clock is one, it runs timers like
+	// PublishExpiring's, so a fake Clock can fire them too.
+	Scheduler interface {
+		Clock
+		AfterFunc(d time.Duration, f func()) Timer
+	}
+
+	// Timer is a call scheduled by a Scheduler. A *time.Timer is one.
+	Timer interface {
+		// Stop keeps the call from happening, returning false if it already
+		// has or was stopped.
+		Stop() bool
+	}
+
+	// IDGenerator generates unique IDs, used for things like transaction IDs
+	// and idempotency keys.
+	IDGenerator interface {
+		NewID() string
+	}
+
+	// IDGeneratorFunc is an IDGenerator that calls the given function.
+	IDGeneratorFunc func() string
+)
+
+// Now implements the Clock interface.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// NewID implements the IDGenerator interface.
+func (f IDGeneratorFunc) NewID() string {
+	return f()
+}
+
+// WithClock sets the Clock the Client and the components built on it use,
+// instead of the system clock. If it's a Scheduler, it also times their
+// scheduled work.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// WithIDGenerator sets the IDGenerator the Client and the components built on
+// it use, instead of random IDs.
+func WithIDGenerator(gen IDGenerator) Option {
+	return func(c *Client) {
+		c.idGen = gen
+	}
+}
+
+// Now returns the current time according to the Client's Clock.
+func (c *Client) Now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// afterFunc calls f after d, as told by the Client's Clock if it's a
+// Scheduler, and the system clock otherwise.
+func (c *Client) afterFunc(d time.Duration, f func()) Timer {
+	if s, ok := c.clock.(Scheduler); ok {
+		return s.AfterFunc(d, f)
+	}
+	return time.AfterFunc(d, f)
+}
+
+// NewID returns a new unique ID from the Client's IDGenerator, or 16 random
+// bytes in hex by default.
+func (c *Client) NewID() string {
+	if c.idGen != nil {
+		return c.idGen.NewID()
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back on the time, which is unique enough for a single Client
+		return hex.EncodeToString([]byte(time.Now().Format(time.RFC3339Nano)))
+	}
+	return hex.EncodeToString(b)
+}
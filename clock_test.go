@@ -0,0 +1,39 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWithClock(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.addPost("", Post{ID: "a", Views: 10})
+
+	now := time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+	c := api.client(WithClock(ClockFunc(func() time.Time { return now })))
+	store := NewMemoryViewStore()
+	if err := NewViewTracker(c, store, 0).Sample(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	samples, _ := store.Samples("a", time.Time{})
+	if len(samples) != 1 || !samples[0].Time.Equal(now) {
+		t.Errorf("Unexpected samples: %+v", samples)
+	}
+}
+
+func TestWithIDGenerator(t *testing.T) {
+	n := 0
+	c := NewClient(WithIDGenerator(IDGeneratorFunc(func() string {
+		n++
+		return fmt.Sprintf("id-%d", n)
+	})))
+	if id := c.NewID(); id != "id-1" {
+		t.Errorf("Unexpected ID: %s", id)
+	}
+	if a, b := NewClient().NewID(), NewClient().NewID(); len(a) != 32 || a == b {
+		t.Errorf("Unexpected default IDs: %s, %s", a, b)
+	}
+}
@@ -0,0 +1,18 @@
+#author: Nguyễn Thái Sơn
+//go:build js && wasm
+// +build js,wasm
+
+// Command writeas-wasm is the WebAssembly module for browser extensions,
+// which sets the global writeas object described in package wasm. Load it
+// with the wasm_exec.js that comes with Go.
+package main
+
+import (
+	"github.com/writeas/go-writeas"
+	"github.com/writeas/go-writeas/wasm"
+)
+
+func main() {
+	wasm.Register(func() *writeas.Client { return writeas.NewClient() })
+	select {}
+}
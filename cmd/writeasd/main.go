@@ -0,0 +1,91 @@
+#author: Nguyễn Thái Sơn
+// Command writeasd serves the gateway API, letting applications publish to
+// Write.as over HTTP with per-user keys instead of holding access tokens
+// themselves. See package gateway for the API.
+//
+//	writeasd -keys keys.json -tokens /var/lib/writeasd/tokens.json \
+//		-tls-cert cert.pem -tls-key key.pem
+//
+// The keys file is a JSON object mapping each gateway key to its user.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/writeas/go-writeas"
+	"github.com/writeas/go-writeas/gateway"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "Address to listen on")
+	keysPath := flag.String("keys", "", "JSON file mapping gateway keys to users")
+	tokensPath := flag.String("tokens", "tokens.json", "File to store users' Write.as tokens in")
+	instance := flag.String("instance", "", "URL of the WriteFreely instance to publish to, instead of Write.as")
+	certFile := flag.String("tls-cert", "", "TLS certificate file")
+	keyFile := flag.String("tls-key", "", "TLS key file")
+	flag.Parse()
+
+	keys, err := loadKeys(*keysPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *instance != "" {
+		if _, err = writeas.NewClientForInstance(*instance); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	h := &gateway.Handler{
+		Keys:   keys,
+		Tokens: &gateway.FileTokenStore{Path: *tokensPath},
+		NewClient: func() *writeas.Client {
+			if *instance == "" {
+				return writeas.NewClient()
+			}
+			c, _ := writeas.NewClientForInstance(*instance)
+			return c
+		},
+		OnError: func(err error) {
+			log.Printf("Error: %v", err)
+		},
+	}
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+	}
+
+	log.Printf("Serving on %s", *addr)
+	if *certFile != "" || *keyFile != "" {
+		err = srv.ListenAndServeTLS(*certFile, *keyFile)
+	} else {
+		err = srv.ListenAndServe()
+	}
+	log.Fatal(err)
+}
+
+func loadKeys(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("A keys file is required; see -help.")
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	keys := map[string]string{}
+	if err = json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("Invalid keys file %s: %v", path, err)
+	}
+	if len(keys) == 0 {
+		log.Printf("Warning: %s has no keys, so every request will be refused.", path)
+	}
+	return keys, nil
+}
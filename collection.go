@@ -2,6 +2,7 @@
 package writeas
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -15,27 +16,103 @@ type (
 		Description string `json:"description"`
 		StyleSheet  string `json:"style_sheet"`
 		Private     bool   `json:"private"`
-		Views       int64  `json:"views"`
-		Domain      string `json:"domain,omitempty"`
-		Email       string `json:"email,omitempty"`
-		URL         string `json:"url,omitempty"`
+		// Public is whether anyone can read the collection, as the API
+		// reports it.
+		Public bool   `json:"public"`
+		Views  int64  `json:"views"`
+		Domain string `json:"domain,omitempty"`
+		Email  string `json:"email,omitempty"`
+		URL    string `json:"url,omitempty"`
+
+		// Format is how the collection displays its posts: "blog",
+		// "novel", or "notebook".
+		Format string `json:"format,omitempty"`
+		// Signature is appended to every post in the collection.
+		Signature string `json:"signature,omitempty"`
+		// Script is custom JavaScript, returned to the owner on instances
+		// that allow it.
+		Script string `json:"script,omitempty"`
+		// MonetizationPointer is the Web Monetization payment pointer.
+		MonetizationPointer string `json:"monetization_pointer,omitempty"`
+		// VerificationLink is a profile URL linked with rel="me", for
+		// verifying the blog on sites like Mastodon.
+		VerificationLink string `json:"verification_link,omitempty"`
 
 		TotalPosts int `json:"total_posts"`
 
+		// HideViews is whether view counts are hidden from readers. It's nil
+		// on instances that don't have the setting.
+		HideViews *bool `json:"hide_views,omitempty"`
+
 		Posts *[]Post `json:"posts,omitempty"`
 	}
 
-	// CollectionParams holds values for creating a collection.
+	// PostsPage is a single page of a collection's posts, with the totals
+	// needed to render a pager.
+	PostsPage struct {
+		Posts []Post
+		// Page is the number of this page, starting at 1.
+		Page       int
+		TotalPosts int
+		TotalPages int
+	}
+
+	// CollectionParams holds values for creating a collection. Only Title,
+	// Description, StyleSheet, and HideViews can be changed with
+	// UpdateCollection.
 	CollectionParams struct {
-		Alias string `json:"alias"`
-		Title string `json:"title"`
+		Alias       string `json:"alias"`
+		Title       string `json:"title"`
+		Description string `json:"description,omitempty"`
+		StyleSheet  string `json:"style_sheet,omitempty"`
+		HideViews   *bool  `json:"hide_views,omitempty"`
+	}
+
+	// collectionUpdate holds the mutable fields of a collection.
+	collectionUpdate struct {
+		Title       string `json:"title,omitempty"`
+		Description string `json:"description,omitempty"`
+		StyleSheet  string `json:"style_sheet,omitempty"`
+		HideViews   *bool  `json:"hide_views,omitempty"`
 	}
 )
 
+// Clone returns a deep copy of the Collection, including its Posts.
+func (c *Collection) Clone() *Collection {
+	if c == nil {
+		return nil
+	}
+	cp := *c
+	if c.HideViews != nil {
+		hide := *c.HideViews
+		cp.HideViews = &hide
+	}
+	if c.Posts != nil {
+		posts := clonePosts(*c.Posts)
+		cp.Posts = &posts
+	}
+	return &cp
+}
+
+// Pages returns the number of pages of posts in the collection, with
+// PostsPerPage posts to a page, based on TotalPosts.
+func (c *Collection) Pages() int {
+	return (c.TotalPosts + PostsPerPage - 1) / PostsPerPage
+}
+
 // CreateCollection creates a new collection, returning a user-friendly error
 // if one comes up. Requires a Write.as subscription. See
 // https://developer.write.as/docs/api/#create-a-collection
 func (c *Client) CreateCollection(sp *CollectionParams) (*Collection, error) {
+	if sp.Alias != "" {
+		alias, err := ParseCollectionAlias(sp.Alias)
+		if err != nil {
+			return nil, err
+		}
+		np := *sp
+		np.Alias = alias
+		sp = &np
+	}
 	p := &Collection{}
 	env, err := c.post("/collections", sp, p)
 	if err != nil {
@@ -63,10 +140,228 @@ func (c *Client) CreateCollection(sp *CollectionParams) (*Collection, error) {
 	return p, nil
 }
 
+// UpdateCollection updates the title, description, style sheet, and view count
+// visibility of the collection with the given alias. Empty fields are left
+// unchanged. See
+// https://developer.write.as/docs/api/#update-a-collection
+func (c *Client) UpdateCollection(alias string, sp *CollectionParams) (*Collection, error) {
+	alias, err := ParseCollectionAlias(alias)
+	if err != nil {
+		return nil, err
+	}
+	p := &Collection{}
+	env, err := c.post(fmt.Sprintf("/collections/%s", alias), &collectionUpdate{
+		Title:       sp.Title,
+		Description: sp.Description,
+		StyleSheet:  sp.StyleSheet,
+		HideViews:   sp.HideViews,
+	}, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if p, ok = env.Data.(*Collection); !ok {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+
+	status := env.Code
+	if status != http.StatusOK {
+		if c.isNotLoggedIn(status) {
+			return nil, fmt.Errorf("Not authenticated.")
+		} else if status == http.StatusBadRequest {
+			return nil, fmt.Errorf("Bad request: %s", env.ErrorMessage)
+		} else if status == http.StatusNotFound {
+			return nil, fmt.Errorf("Collection not found.")
+		}
+		return nil, fmt.Errorf("Problem updating collection: %d. %v\n", status, err)
+	}
+	return p, nil
+}
+
+// SetCollectionViewsVisible shows or hides public view counts on the
+// collection with the given alias, returning ErrNotSupported if the instance
+// doesn't have the setting.
+func (c *Client) SetCollectionViewsVisible(alias string, visible bool) error {
+	hide := !visible
+	coll, err := c.UpdateCollection(alias, &CollectionParams{HideViews: &hide})
+	if err != nil {
+		return err
+	}
+	if coll.HideViews == nil {
+		return ErrNotSupported
+	}
+	return nil
+}
+
+// ErrCascadeNotConfirmed is returned by DeleteCollection when the confirm
+// callback declines to delete or detach a collection's posts.
+var ErrCascadeNotConfirmed = errors.New("Collection deletion not confirmed.")
+
+// CascadeMode decides what DeleteCollection does with a collection's posts
+// before deleting it.
+type CascadeMode string
+
+// Cascade modes.
+const (
+	// CascadeNone leaves the posts to the instance.
+	CascadeNone CascadeMode = ""
+	// CascadeDelete deletes the posts.
+	CascadeDelete CascadeMode = "delete"
+	// CascadeDetach moves the posts out of the collection, keeping them as
+	// the user's posts without a collection.
+	CascadeDetach CascadeMode = "detach"
+)
+
+// DeleteCollection permanently deletes the collection with the given alias.
+// Instances differ in what happens to the posts of a deleted collection, so
+// with a cascade other than CascadeNone, its posts are deleted or detached
+// first, after confirm (if not nil) approves the list. If any post can't be
+// deleted or detached, the collection is kept.
+//
+// Posts and collections that are already gone count as deleted, so a
+// DeleteCollection that failed partway can safely be retried.
+func (c *Client) DeleteCollection(alias string, cascade CascadeMode, confirm func(posts []Post) bool) error {
+	alias, err := ParseCollectionAlias(alias)
+	if err != nil {
+		return err
+	}
+	if cascade != CascadeNone && cascade != CascadeDelete && cascade != CascadeDetach {
+		return fmt.Errorf("Invalid cascade mode %q.", cascade)
+	}
+	if cascade != CascadeNone {
+		coll, err := c.findCollection(alias)
+		if err != nil {
+			return err
+		}
+		if coll == nil {
+			return nil
+		}
+		posts, err := c.allCollectionPosts(alias)
+		if err != nil {
+			return err
+		}
+		if confirm != nil && !confirm(posts) {
+			return ErrCascadeNotConfirmed
+		}
+		var res *BatchResult[Post]
+		if cascade == CascadeDetach {
+			res, err = c.detachPosts(posts)
+			if err != nil {
+				return err
+			}
+		} else {
+			res = &BatchResult[Post]{}
+			for _, p := range posts {
+				res.add(p, c.DeletePostIdempotent(&PostParams{ID: p.ID}))
+			}
+		}
+		if err = res.Err(); err != nil {
+			return err
+		}
+	}
+
+	env, err := c.delete(fmt.Sprintf("/collections/%s", alias), nil)
+	if err != nil {
+		return err
+	}
+	status := env.Code
+	if status == http.StatusNoContent || status == http.StatusOK || status == http.StatusNotFound {
+		return nil
+	} else if c.isNotLoggedIn(status) {
+		return fmt.Errorf("Not authenticated.")
+	} else if status == http.StatusBadRequest {
+		return fmt.Errorf("Bad request: %s", env.ErrorMessage)
+	}
+	return fmt.Errorf("Problem deleting collection: %d. %v\n", status, err)
+}
+
+// detachPosts moves the given posts out of their collection, returning the
+// result for each. Posts that are already gone count as detached.
+func (c *Client) detachPosts(posts []Post) (*BatchResult[Post], error) {
+	res := &BatchResult[Post]{}
+	if len(posts) == 0 {
+		return res, nil
+	}
+	ids := make([]string, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	results := &[]BatchPostResult{}
+	env, err := c.post("/posts/disperse", ids, results)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if results, ok = env.Data.(*[]BatchPostResult); !ok {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+	status := env.Code
+	if status != http.StatusOK {
+		if c.isNotLoggedIn(status) {
+			return nil, fmt.Errorf("Not authenticated.")
+		} else if status == http.StatusBadRequest {
+			return nil, fmt.Errorf("Bad request: %s", env.ErrorMessage)
+		}
+		return nil, fmt.Errorf("Problem detaching posts: %d. %v\n", status, err)
+	}
+
+	codes := map[string]BatchPostResult{}
+	for _, r := range *results {
+		codes[r.ID] = r
+	}
+	for _, p := range posts {
+		r, ok := codes[p.ID]
+		switch {
+		case !ok:
+			res.add(p, fmt.Errorf("No result for post %s.", p.ID))
+		case r.Code == http.StatusOK, r.Code == http.StatusNotFound:
+			res.add(p, nil)
+		default:
+			res.add(p, fmt.Errorf("Problem detaching post %s: %d. %s", p.ID, r.Code, r.ErrorMessage))
+		}
+	}
+	return res, nil
+}
+
+// allCollectionPosts retrieves every page of a collection's posts. Instances
+// that don't report total_posts are paged until an empty page.
+func (c *Client) allCollectionPosts(alias string) ([]Post, error) {
+	var posts []Post
+	for page := 1; ; page++ {
+		pp, err := c.GetCollectionPostsPage(alias, page)
+		if err != nil {
+			return nil, err
+		}
+		posts = append(posts, pp.Posts...)
+		if len(pp.Posts) == 0 || (pp.TotalPages > 0 && page >= pp.TotalPages) {
+			return posts, nil
+		}
+	}
+}
+
 // GetCollection retrieves a collection, returning the Collection and any error
 // (in user-friendly form) that occurs. See
 // https://developer.write.as/docs/api/#retrieve-a-collection
 func (c *Client) GetCollection(alias string) (*Collection, error) {
+	coll, err := c.findCollection(alias)
+	if err != nil {
+		return nil, err
+	}
+	if coll == nil {
+		return nil, fmt.Errorf("Collection not found.")
+	}
+	return coll, nil
+}
+
+// findCollection retrieves a collection, returning a nil Collection without
+// an error if it doesn't exist.
+func (c *Client) findCollection(alias string) (*Collection, error) {
+	alias, err := ParseCollectionAlias(alias)
+	if err != nil {
+		return nil, err
+	}
 	coll := &Collection{}
 	env, err := c.get(fmt.Sprintf("/collections/%s", alias), coll)
 	if err != nil {
@@ -82,7 +377,7 @@ func (c *Client) GetCollection(alias string) (*Collection, error) {
 	if status == http.StatusOK {
 		return coll, nil
 	} else if status == http.StatusNotFound {
-		return nil, fmt.Errorf("Collection not found.")
+		return nil, nil
 	} else {
 		return nil, fmt.Errorf("Problem getting collection: %d. %v\n", status, err)
 	}
@@ -92,8 +387,43 @@ func (c *Client) GetCollection(alias string) (*Collection, error) {
 // and any error (in user-friendly form) that occurs. See
 // https://developer.write.as/docs/api/#retrieve-collection-posts
 func (c *Client) GetCollectionPosts(alias string) (*[]Post, error) {
+	coll, err := c.getCollectionPosts(alias, "")
+	if err != nil {
+		return nil, err
+	}
+	return coll.Posts, nil
+}
+
+// GetCollectionPostsPage retrieves the given page of a collection's posts,
+// starting at 1, along with the collection's total post and page counts.
+func (c *Client) GetCollectionPostsPage(alias string, page int) (*PostsPage, error) {
+	if page < 1 {
+		return nil, fmt.Errorf("Invalid page %d: pages start at 1.", page)
+	}
+	coll, err := c.getCollectionPosts(alias, fmt.Sprintf("?page=%d", page))
+	if err != nil {
+		return nil, err
+	}
+	pp := &PostsPage{
+		Page:       page,
+		TotalPosts: coll.TotalPosts,
+		TotalPages: coll.Pages(),
+	}
+	if coll.Posts != nil {
+		pp.Posts = *coll.Posts
+	}
+	return pp, nil
+}
+
+// getCollectionPosts retrieves a collection along with its posts, with the
+// given query string.
+func (c *Client) getCollectionPosts(alias, query string) (*Collection, error) {
+	alias, err := ParseCollectionAlias(alias)
+	if err != nil {
+		return nil, err
+	}
 	coll := &Collection{}
-	env, err := c.get(fmt.Sprintf("/collections/%s/posts", alias), coll)
+	env, err := c.get(fmt.Sprintf("/collections/%s/posts%s", alias, query), coll)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +435,10 @@ func (c *Client) GetCollectionPosts(alias string) (*[]Post, error) {
 	status := env.Code
 
 	if status == http.StatusOK {
-		return coll.Posts, nil
+		if coll.Posts != nil {
+			c.fillPostURLs(*coll.Posts, coll)
+		}
+		return coll, nil
 	} else if status == http.StatusNotFound {
 		return nil, fmt.Errorf("Collection not found.")
 	} else {
@@ -113,6 +446,48 @@ func (c *Client) GetCollectionPosts(alias string) (*[]Post, error) {
 	}
 }
 
+// GetCollectionPost retrieves a post in a collection by its slug, returning
+// the Post and any error (in user-friendly form) that occurs. See
+// https://developer.write.as/docs/api/#retrieve-a-collection-post
+func (c *Client) GetCollectionPost(alias, slug string) (*Post, error) {
+	p, err := c.findCollectionPost(alias, slug)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("Post not found.")
+	}
+	return p, nil
+}
+
+// findCollectionPost retrieves a post in a collection by its slug, returning
+// a nil Post without an error if it doesn't exist.
+func (c *Client) findCollectionPost(alias, slug string) (*Post, error) {
+	alias, err := ParseCollectionAlias(alias)
+	if err != nil {
+		return nil, err
+	}
+	p := &Post{}
+	env, err := c.get(fmt.Sprintf("/collections/%s/posts/%s", alias, slug), p)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if p, ok = env.Data.(*Post); !ok {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+	status := env.Code
+
+	if status == http.StatusOK {
+		c.fillPostURL(p, &Collection{Alias: alias})
+		return p, nil
+	} else if status == http.StatusNotFound || status == http.StatusGone {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("Problem getting post: %d. %v\n", status, err)
+}
+
 // GetUserCollections retrieves the authenticated user's collections.
 // See https://developers.write.as/docs/api/#retrieve-user-39-s-collections
 func (c *Client) GetUserCollections() (*[]Collection, error) {
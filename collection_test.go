@@ -2,7 +2,9 @@
 package writeas
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -62,3 +64,140 @@ func ExampleClient_GetCollection() {
 	fmt.Printf("%s", coll.Title)
 	// Output: write.as
 }
+
+func TestSetCollectionViewsVisible(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog", Title: "Blog"}
+	c := api.client()
+
+	if err := c.SetCollectionViewsVisible("blog", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	coll := api.collections["blog"]
+	if coll.HideViews == nil || !*coll.HideViews || coll.Title != "Blog" {
+		t.Errorf("Unexpected collection: %+v", coll)
+	}
+
+	if err := c.SetCollectionViewsVisible("missing", true); err == nil {
+		t.Errorf("Expected an error for a missing collection")
+	}
+}
+
+func TestGetCollectionPostsPage(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	for i := 0; i < 23; i++ {
+		api.addPost("blog", Post{ID: fmt.Sprintf("p%02d", i)})
+	}
+	c := api.client()
+
+	pp, err := c.GetCollectionPostsPage("blog", 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pp.Page != 3 || pp.TotalPosts != 23 || pp.TotalPages != 3 || len(pp.Posts) != 3 || pp.Posts[0].ID != "p20" {
+		t.Errorf("Unexpected page: %+v", pp)
+	}
+	if _, err = c.GetCollectionPostsPage("blog", 0); err == nil {
+		t.Errorf("Expected an error for page 0")
+	}
+}
+
+func TestCollectionPages(t *testing.T) {
+	for total, pages := range map[int]int{0: 0, 1: 1, 10: 1, 11: 2, 100: 10} {
+		if n := (&Collection{TotalPosts: total}).Pages(); n != pages {
+			t.Errorf("Unexpected pages for %d posts: %d", total, n)
+		}
+	}
+}
+
+func TestDeleteCollection(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	for i := 0; i < 12; i++ {
+		api.addPost("blog", Post{ID: fmt.Sprintf("p%02d", i)})
+	}
+	api.addPost("", Post{ID: "anon"})
+	c := api.client()
+
+	err := c.DeleteCollection("blog", CascadeDelete, func(posts []Post) bool { return false })
+	if err != ErrCascadeNotConfirmed || len(api.posts) != 13 || api.collections["blog"] == nil {
+		t.Fatalf("Expected nothing deleted without confirmation, got: %v", err)
+	}
+
+	var confirmed []Post
+	err = c.DeleteCollection("blog", CascadeDelete, func(posts []Post) bool {
+		confirmed = posts
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(confirmed) != 12 || len(api.posts) != 1 || api.collections["blog"] != nil {
+		t.Errorf("Unexpected state: %d confirmed, %d posts left", len(confirmed), len(api.posts))
+	}
+
+	// Retrying after the collection is gone succeeds.
+	if err = c.DeleteCollection("blog", CascadeDelete, nil); err != nil {
+		t.Errorf("Unexpected error on retry: %v", err)
+	}
+}
+
+func TestDeleteCollectionDetach(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.noTotals = true
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	for i := 0; i < 12; i++ {
+		api.addPost("blog", Post{ID: fmt.Sprintf("p%02d", i)})
+	}
+	c := api.client()
+
+	var confirmed []Post
+	err := c.DeleteCollection("blog", CascadeDetach, func(posts []Post) bool {
+		confirmed = posts
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(confirmed) != 12 || len(api.posts) != 12 || api.collections["blog"] != nil {
+		t.Errorf("Unexpected state: %d confirmed, %d posts left", len(confirmed), len(api.posts))
+	}
+	for _, p := range api.posts {
+		if p.Collection != nil {
+			t.Errorf("Expected post %s to be detached", p.ID)
+		}
+	}
+	if err = c.DeleteCollection("blog", "archive", nil); err == nil {
+		t.Errorf("Expected an error for an unknown cascade mode")
+	}
+}
+
+func TestCollectionDecode(t *testing.T) {
+	tests := map[string]Collection{
+		// Write.as
+		`{"alias":"new-blog","title":"The Best Blog Ever","description":"","style_sheet":"","public":true,"views":10,"email":"new-blog-wjn6epspzjqankz41mlfvz@writeas.com","url":"https://write.as/new-blog/","total_posts":3}`: {
+			Alias: "new-blog", Title: "The Best Blog Ever", Public: true, Views: 10,
+			Email: "new-blog-wjn6epspzjqankz41mlfvz@writeas.com", URL: "https://write.as/new-blog/", TotalPosts: 3,
+		},
+		// WriteFreely, with the owner's settings
+		`{"alias":"notes","title":"Notes","format":"notebook","signature":"—M","script":"console.log(1)","monetization_pointer":"$wallet.example.com/m","verification_link":"https://mastodon.social/@m","public":false,"views":0,"total_posts":0}`: {
+			Alias: "notes", Title: "Notes", Format: "notebook", Signature: "—M", Script: "console.log(1)",
+			MonetizationPointer: "$wallet.example.com/m", VerificationLink: "https://mastodon.social/@m",
+		},
+	}
+	for data, want := range tests {
+		var coll Collection
+		if err := json.Unmarshal([]byte(data), &coll); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(coll, want) {
+			t.Errorf("Unexpected collection:\n%+v\nwant:\n%+v", coll, want)
+		}
+	}
+}
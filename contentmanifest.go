@@ -0,0 +1,177 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import "sort"
+
+type (
+	// ContentManifest summarizes an account's content as hashes: each post's
+	// ContentHash, rolled up into a digest of each collection, rolled up into
+	// a digest of the whole account. Comparing the Digest of two manifests
+	// tells whether anything changed between them, and comparing their posts
+	// tells what.
+	//
+	// Unlike a Manifest, which describes the content an account should
+	// have, it records the content an account has. It's JSON-friendly, to be
+	// stored alongside backups.
+	ContentManifest struct {
+		// Version is the ContentHashVersion the manifest was made with.
+		// Manifests of different versions can't be compared.
+		Version     string             `json:"version"`
+		Digest      string             `json:"digest"`
+		Collections []CollectionDigest `json:"collections"`
+		// Posts are the posts not in a collection.
+		Posts []PostHash `json:"posts"`
+	}
+
+	// CollectionDigest is the part of a ContentManifest for one collection. Its
+	// Digest covers the collection's own settings as well as its posts.
+	CollectionDigest struct {
+		Alias  string     `json:"alias"`
+		Digest string     `json:"digest"`
+		Posts  []PostHash `json:"posts"`
+	}
+
+	// PostHash is a post's ContentHash in a ContentManifest.
+	PostHash struct {
+		ID   string `json:"id"`
+		Hash string `json:"hash"`
+	}
+)
+
+// ContentManifest returns a ContentManifest of the authenticated user's collections and
+// posts.
+func (c *Client) ContentManifest() (*ContentManifest, error) {
+	colls, err := c.GetUserCollections()
+	if err != nil {
+		return nil, err
+	}
+	posts, err := c.GetUserPosts()
+	if err != nil {
+		return nil, err
+	}
+	return NewContentManifest(*colls, *posts), nil
+}
+
+// NewContentManifest returns a ContentManifest of the given collections and
+// posts. Posts are sorted by ID and collections by alias, so the same content
+// always gets the same digests, whatever order it's given in.
+func NewContentManifest(colls []Collection, posts []Post) *ContentManifest {
+	byAlias := map[string][]PostHash{}
+	m := &ContentManifest{Version: ContentHashVersion, Posts: []PostHash{}}
+	for i := range posts {
+		p := &posts[i]
+		ph := PostHash{ID: p.ID, Hash: ContentHash(p)}
+		if p.Collection != nil {
+			byAlias[p.Collection.Alias] = append(byAlias[p.Collection.Alias], ph)
+		} else {
+			m.Posts = append(m.Posts, ph)
+		}
+	}
+
+	sorted := make([]Collection, len(colls))
+	copy(sorted, colls)
+	known := map[string]bool{}
+	for _, coll := range colls {
+		known[coll.Alias] = true
+	}
+	for alias := range byAlias {
+		// Keep posts in collections that weren't given, by alias alone
+		if !known[alias] {
+			sorted = append(sorted, Collection{Alias: alias})
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Alias < sorted[j].Alias })
+	for _, coll := range sorted {
+		cm := CollectionDigest{Alias: coll.Alias, Posts: byAlias[coll.Alias]}
+		if cm.Posts == nil {
+			cm.Posts = []PostHash{}
+		}
+		sortPostHashes(cm.Posts)
+		fields := [][2]string{
+			{"alias", coll.Alias},
+			{"title", coll.Title},
+			{"description", coll.Description},
+			{"style_sheet", coll.StyleSheet},
+			{"format", coll.Format},
+			{"signature", coll.Signature},
+			{"script", coll.Script},
+		}
+		cm.Digest = hashFields(append(fields, postHashFields(cm.Posts)...))
+		m.Collections = append(m.Collections, cm)
+	}
+	if m.Collections == nil {
+		m.Collections = []CollectionDigest{}
+	}
+
+	sortPostHashes(m.Posts)
+	fields := [][2]string{}
+	for _, cm := range m.Collections {
+		fields = append(fields, [2]string{"collection " + cm.Alias, cm.Digest})
+	}
+	m.Digest = hashFields(append(fields, postHashFields(m.Posts)...))
+	return m
+}
+
+// Changed reports whether anything changed since the old manifest was made.
+// Manifests made with another version of ContentHash are always changed.
+func (m *ContentManifest) Changed(old *ContentManifest) bool {
+	return old == nil || old.Version != m.Version || old.Digest != m.Digest
+}
+
+// ChangedPosts returns the IDs of the posts that were added, changed, or
+// removed since the old manifest was made, sorted. Collections whose digests
+// match are skipped without looking at their posts.
+func (m *ContentManifest) ChangedPosts(old *ContentManifest) []string {
+	oldHashes := map[string]string{}
+	newHashes := map[string]string{}
+	oldDigests := map[string]string{}
+	if old != nil && old.Version == m.Version {
+		for _, cm := range old.Collections {
+			oldDigests[cm.Alias] = cm.Digest
+			addPostHashes(oldHashes, cm.Posts)
+		}
+		addPostHashes(oldHashes, old.Posts)
+	}
+	for _, cm := range m.Collections {
+		if d, ok := oldDigests[cm.Alias]; ok && d == cm.Digest {
+			// Unchanged, so its posts are the same in both
+			for _, ph := range cm.Posts {
+				delete(oldHashes, ph.ID)
+			}
+			continue
+		}
+		addPostHashes(newHashes, cm.Posts)
+	}
+	addPostHashes(newHashes, m.Posts)
+
+	var ids []string
+	for id, h := range newHashes {
+		if oldHashes[id] != h {
+			ids = append(ids, id)
+		}
+		delete(oldHashes, id)
+	}
+	for id := range oldHashes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func sortPostHashes(phs []PostHash) {
+	sort.Slice(phs, func(i, j int) bool { return phs[i].ID < phs[j].ID })
+}
+
+func postHashFields(phs []PostHash) [][2]string {
+	fields := make([][2]string, len(phs))
+	for i, ph := range phs {
+		fields[i] = [2]string{"post " + ph.ID, ph.Hash}
+	}
+	return fields
+}
+
+func addPostHashes(hashes map[string]string, phs []PostHash) {
+	for _, ph := range phs {
+		hashes[ph.ID] = ph.Hash
+	}
+}
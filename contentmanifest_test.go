@@ -0,0 +1,65 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestContentManifest(t *testing.T) {
+	colls := []Collection{{Alias: "notes", Title: "Notes"}, {Alias: "blog", Title: "Blog"}}
+	posts := []Post{
+		{ID: "b1", Title: "One", Content: "Hi", Collection: &Collection{Alias: "blog"}},
+		{ID: "n1", Content: "Note", Collection: &Collection{Alias: "notes"}},
+		{ID: "a1", Content: "Anonymous"},
+		{ID: "b2", Content: "Two", Collection: &Collection{Alias: "blog"}},
+	}
+	m := NewContentManifest(colls, posts)
+	if m.Collections[0].Alias != "blog" || fmt.Sprint(m.Collections[0].Posts[1].ID) != "b2" || len(m.Posts) != 1 {
+		t.Errorf("Unexpected manifest layout: %+v", m)
+	}
+
+	// Order doesn't matter
+	reordered := NewContentManifest([]Collection{colls[1], colls[0]}, []Post{posts[3], posts[2], posts[1], posts[0]})
+	if reordered.Changed(m) {
+		t.Errorf("Expected the same content in another order to have the same digest")
+	}
+	if ids := reordered.ChangedPosts(m); len(ids) != 0 {
+		t.Errorf("Unexpected changed posts %v", ids)
+	}
+
+	edited := append([]Post{}, posts...)
+	edited[1].Content = "Note, edited"
+	edited[2] = Post{ID: "a2", Content: "New"}
+	em := NewContentManifest(colls, edited)
+	if !em.Changed(m) {
+		t.Errorf("Expected an edit to change the digest")
+	}
+	if em.Collections[0].Digest != m.Collections[0].Digest {
+		t.Errorf("Expected the untouched collection's digest to stay the same")
+	}
+	if ids := fmt.Sprint(em.ChangedPosts(m)); ids != "[a1 a2 n1]" {
+		t.Errorf("Unexpected changed posts %s", ids)
+	}
+
+	retitled := NewContentManifest([]Collection{{Alias: "notes", Title: "Notebook"}, colls[1]}, posts)
+	if !retitled.Changed(m) {
+		t.Errorf("Expected a collection title change to change the digest")
+	}
+	if (&ContentManifest{Version: "v0", Digest: m.Digest}).Changed(m) == false {
+		t.Errorf("Expected manifests of other versions to be changed")
+	}
+}
+
+func TestClientContentManifest(t *testing.T) {
+	api := newFakeAPI(t)
+	api.addPost("", Post{ID: "abc", Content: "Hi"})
+	c := api.client()
+	m, err := c.ContentManifest()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(m.Posts) != 1 || m.Posts[0].ID != "abc" || !ContentHashCurrent(m.Digest) {
+		t.Errorf("Unexpected manifest %+v", m)
+	}
+}
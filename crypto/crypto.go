@@ -0,0 +1,163 @@
+#author: Nguyễn Thái Sơn
+// Package crypto provides optional client-side encryption of post content, so
+// posts can be shared privately on a public Write.as instance. By convention,
+// the key travels in the fragment of the shared URL, which browsers never
+// send to the server.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/writeas/go-writeas"
+)
+
+const (
+	// KeySize is the size, in bytes, of keys used to encrypt posts.
+	KeySize = 32
+
+	armorHeader = "-----BEGIN WRITEAS ENCRYPTED POST-----"
+	armorFooter = "-----END WRITEAS ENCRYPTED POST-----"
+	armorWidth  = 64
+)
+
+// GenerateKey returns a new random key for encrypting a post.
+func GenerateKey() ([]byte, error) {
+	k := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, k); err != nil {
+		return nil, fmt.Errorf("Generate key: %v", err)
+	}
+	return k, nil
+}
+
+// EncodeKey encodes the given key for use in a URL fragment.
+func EncodeKey(key []byte) string {
+	return base64.RawURLEncoding.EncodeToString(key)
+}
+
+// DecodeKey decodes a key previously encoded with EncodeKey.
+func DecodeKey(s string) ([]byte, error) {
+	k, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid key: %v", err)
+	}
+	if len(k) != KeySize {
+		return nil, fmt.Errorf("Invalid key: must be %d bytes, got %d.", KeySize, len(k))
+	}
+	return k, nil
+}
+
+// ShareURL returns the given post URL with the key set as its fragment.
+func ShareURL(postURL string, key []byte) string {
+	if i := strings.Index(postURL, "#"); i >= 0 {
+		postURL = postURL[:i]
+	}
+	return postURL + "#" + EncodeKey(key)
+}
+
+// KeyFromURL extracts the key from the fragment of a URL created with
+// ShareURL.
+func KeyFromURL(u string) ([]byte, error) {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid URL: %v", err)
+	}
+	if pu.Fragment == "" {
+		return nil, fmt.Errorf("URL has no key fragment.")
+	}
+	return DecodeKey(pu.Fragment)
+}
+
+// Encrypt seals the given content with AES-256-GCM, returning an armored
+// block of text suitable for use as a post body.
+func Encrypt(key []byte, content string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("Generate nonce: %v", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(content), nil)
+
+	enc := base64.StdEncoding.EncodeToString(sealed)
+	var b strings.Builder
+	b.WriteString(armorHeader + "\n")
+	for len(enc) > armorWidth {
+		b.WriteString(enc[:armorWidth] + "\n")
+		enc = enc[armorWidth:]
+	}
+	b.WriteString(enc + "\n")
+	b.WriteString(armorFooter)
+	return b.String(), nil
+}
+
+// Decrypt opens an armored post body created with Encrypt.
+func Decrypt(key []byte, body string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	body = strings.TrimSpace(body)
+	if !strings.HasPrefix(body, armorHeader) || !strings.HasSuffix(body, armorFooter) {
+		return "", fmt.Errorf("Content is not encrypted.")
+	}
+	enc := strings.Join(strings.Fields(body[len(armorHeader):len(body)-len(armorFooter)]), "")
+	sealed, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return "", fmt.Errorf("Malformed encrypted content: %v", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("Malformed encrypted content.")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("Unable to decrypt: wrong key or corrupted content.")
+	}
+	return string(plain), nil
+}
+
+// IsEncrypted reports whether the given post body was created with Encrypt.
+func IsEncrypted(body string) bool {
+	return strings.HasPrefix(strings.TrimSpace(body), armorHeader)
+}
+
+// EncryptParams encrypts the Content of the given PostParams in place. The
+// Title is left untouched, as it's rendered publicly; callers wanting a fully
+// private post should leave it empty.
+func EncryptParams(p *writeas.PostParams, key []byte) error {
+	enc, err := Encrypt(key, p.Content)
+	if err != nil {
+		return err
+	}
+	p.Content = enc
+	return nil
+}
+
+// DecryptPost decrypts the Content of a fetched Post in place.
+func DecryptPost(p *writeas.Post, key []byte) error {
+	plain, err := Decrypt(key, p.Content)
+	if err != nil {
+		return err
+	}
+	p.Content = plain
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("Invalid key: must be %d bytes, got %d.", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,70 @@
+#author: Nguyễn Thái Sơn
+package crypto
+
+import (
+	"testing"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("Unable to generate key: %v", err)
+	}
+
+	content := "This is a private post."
+	enc, err := Encrypt(key, content)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if !IsEncrypted(enc) {
+		t.Errorf("Expected armored content, got: %s", enc)
+	}
+
+	dec, err := Decrypt(key, enc)
+	if err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	if dec != content {
+		t.Errorf("Unexpected decrypted content: %s", dec)
+	}
+
+	other, _ := GenerateKey()
+	if _, err = Decrypt(other, enc); err == nil {
+		t.Errorf("Expected decrypt with wrong key to fail")
+	}
+}
+
+func TestShareURL(t *testing.T) {
+	key, _ := GenerateKey()
+	u := ShareURL("https://write.as/3psnxyhqxy3hq", key)
+
+	k, err := KeyFromURL(u)
+	if err != nil {
+		t.Fatalf("Unable to get key from URL %s: %v", u, err)
+	}
+	if EncodeKey(k) != EncodeKey(key) {
+		t.Errorf("Unexpected key from URL: %s", u)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	pub, priv, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("Unable to generate signing key: %v", err)
+	}
+
+	p := &writeas.PostParams{Content: "This is a post."}
+	SignParams(p, priv)
+
+	err = VerifyPost(&writeas.Post{Content: p.Content}, pub)
+	if err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	err = VerifyPost(&writeas.Post{Content: "Tampered." + p.Content}, pub)
+	if err == nil {
+		t.Errorf("Expected verify of tampered post to fail")
+	}
+}
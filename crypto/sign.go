@@ -0,0 +1,104 @@
+#author: Nguyễn Thái Sơn
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/writeas/go-writeas"
+)
+
+const (
+	sigHeader = "-----BEGIN WRITEAS SIGNATURE-----"
+	sigFooter = "-----END WRITEAS SIGNATURE-----"
+)
+
+// GenerateSigningKey returns a new ed25519 key pair for signing posts.
+func GenerateSigningKey() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Generate signing key: %v", err)
+	}
+	return pub, priv, nil
+}
+
+// Sign returns an armored, detached signature of the given content.
+func Sign(priv ed25519.PrivateKey, content string) string {
+	sig := ed25519.Sign(priv, signedBytes(content))
+	return sigHeader + "\n" + base64.StdEncoding.EncodeToString(sig) + "\n" + sigFooter
+}
+
+// Verify checks an armored signature created with Sign against the given
+// content and public key.
+func Verify(pub ed25519.PublicKey, content, sig string) error {
+	sig = strings.TrimSpace(sig)
+	if !strings.HasPrefix(sig, sigHeader) || !strings.HasSuffix(sig, sigFooter) {
+		return fmt.Errorf("Malformed signature.")
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(sig[len(sigHeader) : len(sig)-len(sigFooter)]))
+	if err != nil {
+		return fmt.Errorf("Malformed signature: %v", err)
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("Invalid public key.")
+	}
+	if !ed25519.Verify(pub, signedBytes(content), raw) {
+		return fmt.Errorf("Signature doesn't match content.")
+	}
+	return nil
+}
+
+// SignParams appends a detached signature of the PostParams' Content to the
+// Content itself.
+func SignParams(p *writeas.PostParams, priv ed25519.PrivateKey) {
+	p.Content = strings.TrimRight(p.Content, " \t\r\n") + "\n\n" + Sign(priv, p.Content)
+}
+
+// SplitSignature separates a signature appended with SignParams from the rest
+// of a post body. ok is false if the body isn't signed.
+func SplitSignature(body string) (content, sig string, ok bool) {
+	i := strings.LastIndex(body, sigHeader)
+	if i < 0 {
+		return body, "", false
+	}
+	return body[:i], body[i:], true
+}
+
+// VerifyPost checks the signature appended to a post's Content with
+// SignParams.
+func VerifyPost(p *writeas.Post, pub ed25519.PublicKey) error {
+	content, sig, ok := SplitSignature(p.Content)
+	if !ok {
+		return fmt.Errorf("Post isn't signed.")
+	}
+	return Verify(pub, content, sig)
+}
+
+// CompanionParams returns the PostParams for a companion post that carries the
+// signature of the given post, for authors who don't want the signature
+// appended to the post itself.
+func CompanionParams(p *writeas.Post, priv ed25519.PrivateKey) *writeas.PostParams {
+	return &writeas.PostParams{
+		Content: fmt.Sprintf("Signature for post %s\n\n%s", p.ID, Sign(priv, p.Content)),
+	}
+}
+
+// VerifyCompanion checks a post against the signature stored in its companion
+// post.
+func VerifyCompanion(p, companion *writeas.Post, pub ed25519.PublicKey) error {
+	_, sig, ok := SplitSignature(companion.Content)
+	if !ok {
+		return fmt.Errorf("Companion post has no signature.")
+	}
+	return Verify(pub, p.Content, sig)
+}
+
+// signedBytes normalizes content before signing, since line endings and
+// trailing whitespace don't always survive a round trip through the API.
+func signedBytes(content string) []byte {
+	content = strings.Replace(content, "\r\n", "\n", -1)
+	return []byte(strings.TrimRight(content, " \t\n"))
+}
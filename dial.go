@@ -0,0 +1,206 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// DialOptions tune how the Client connects to the API, for networks where
+// connecting, rather than the API, is slow.
+type DialOptions struct {
+	// Timeout limits how long connecting may take. It defaults to 30
+	// seconds.
+	Timeout time.Duration
+	// FallbackDelay is how long to wait for a connection over the preferred
+	// IP version before also trying the other one, when the host has both
+	// IPv6 and IPv4 addresses ("happy eyeballs"). It defaults to 300ms, and
+	// a negative value tries addresses one at a time.
+	FallbackDelay time.Duration
+	// DNSCacheTTL, if positive, caches looked up addresses this long. When
+	// connecting to every cached address fails, they're looked up again.
+	DNSCacheTTL time.Duration
+}
+
+// WithDialOptions sets how the Client connects to the API. It has no effect
+// on Tor clients, which connect through the proxy, or under js/wasm, where
+// the browser connects.
+func WithDialOptions(o DialOptions) Option {
+	return func(c *Client) {
+		if runtime.GOOS == "js" {
+			return
+		}
+		if t, ok := c.client.Transport.(*http.Transport); ok && t.Dial != nil {
+			return
+		}
+		t := c.ownTransport()
+
+		timeout := o.Timeout
+		if timeout <= 0 {
+			timeout = 30 * time.Second
+		}
+		d := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second, FallbackDelay: o.FallbackDelay}
+		if o.DNSCacheTTL <= 0 {
+			t.DialContext = d.DialContext
+			return
+		}
+		c.dns = &dnsCache{
+			ttl:    o.DNSCacheTTL,
+			now:    c.Now,
+			lookup: net.DefaultResolver.LookupIPAddr,
+		}
+		fallback := o.FallbackDelay
+		if fallback == 0 {
+			fallback = 300 * time.Millisecond
+		}
+		t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return c.dns.dial(ctx, d, fallback, network, addr)
+		}
+	}
+}
+
+// dnsCache caches the addresses of the hosts the Client connects to.
+type dnsCache struct {
+	ttl    time.Duration
+	now    func() time.Time
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+type dnsEntry struct {
+	addrs   []net.IPAddr
+	expires time.Time
+}
+
+// resolve returns the host's addresses, from the cache if they haven't
+// expired.
+func (dc *dnsCache) resolve(ctx context.Context, host string) ([]net.IPAddr, error) {
+	dc.mu.Lock()
+	e, ok := dc.entries[host]
+	dc.mu.Unlock()
+	if ok && dc.now().Before(e.expires) {
+		return e.addrs, nil
+	}
+
+	addrs, err := dc.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.entries == nil {
+		dc.entries = map[string]dnsEntry{}
+	}
+	dc.entries[host] = dnsEntry{addrs: addrs, expires: dc.now().Add(dc.ttl)}
+	return addrs, nil
+}
+
+func (dc *dnsCache) forget(host string) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	delete(dc.entries, host)
+}
+
+// dial connects to addr using the cached addresses of its host, racing IPv6
+// and IPv4 addresses like net.Dialer does.
+func (dc *dnsCache) dial(ctx context.Context, d *net.Dialer, fallback time.Duration, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return d.DialContext(ctx, network, addr)
+	}
+	addrs, err := dc.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	primaries, fallbacks := partitionAddrs(addrs, network)
+	if fallback < 0 {
+		primaries, fallbacks = append(primaries, fallbacks...), nil
+	}
+	conn, err := dialRace(ctx, d, fallback, network, port, primaries, fallbacks)
+	if err != nil {
+		dc.forget(host)
+	}
+	return conn, err
+}
+
+// partitionAddrs splits addrs into those of the first address's IP version
+// and the rest, leaving out those the network doesn't allow.
+func partitionAddrs(addrs []net.IPAddr, network string) (primaries, fallbacks []net.IPAddr) {
+	for _, a := range addrs {
+		v4 := a.IP.To4() != nil
+		if network == "tcp4" && !v4 || network == "tcp6" && v4 {
+			continue
+		}
+		if len(primaries) == 0 || (primaries[0].IP.To4() != nil) == v4 {
+			primaries = append(primaries, a)
+		} else {
+			fallbacks = append(fallbacks, a)
+		}
+	}
+	return primaries, fallbacks
+}
+
+// dialRace dials the primaries one at a time, and after the fallback delay
+// the fallbacks alongside them, returning the first connection.
+func dialRace(ctx context.Context, d *net.Dialer, fallback time.Duration, network, port string, primaries, fallbacks []net.IPAddr) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan result, 2)
+	dialSerial := func(addrs []net.IPAddr, delay time.Duration) {
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				results <- result{err: ctx.Err()}
+				return
+			}
+		}
+		err := &net.AddrError{Err: "no suitable address found"}
+		for _, a := range addrs {
+			conn, derr := d.DialContext(ctx, network, net.JoinHostPort(a.String(), port))
+			if derr == nil {
+				results <- result{conn: conn}
+				return
+			}
+			err = &net.AddrError{Err: derr.Error(), Addr: a.String()}
+		}
+		results <- result{err: err}
+	}
+
+	racers := 1
+	go dialSerial(primaries, 0)
+	if len(fallbacks) > 0 {
+		racers++
+		go dialSerial(fallbacks, fallback)
+	}
+	var firstErr error
+	for i := 0; i < racers; i++ {
+		res := <-results
+		if res.err == nil {
+			// Close the other connection, if it comes
+			go func(n int) {
+				for ; n > 0; n-- {
+					if l := <-results; l.conn != nil {
+						l.conn.Close()
+					}
+				}
+			}(racers - i - 1)
+			return res.conn, nil
+		}
+		if firstErr == nil {
+			firstErr = res.err
+		}
+	}
+	return nil, firstErr
+}
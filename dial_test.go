@@ -0,0 +1,91 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDialOptionsDNSCache(t *testing.T) {
+	srv := newPolicyServer()
+	defer srv.Close()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClient(WithBaseURL(strings.Replace(srv.URL, "127.0.0.1", "writeas.test", 1)),
+		WithClock(ClockFunc(func() time.Time { return now })),
+		WithDialOptions(DialOptions{DNSCacheTTL: time.Minute}))
+	lookups := 0
+	c.dns.lookup = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		lookups++
+		if host != "writeas.test" {
+			t.Errorf("Unexpected lookup of %s", host)
+		}
+		return []net.IPAddr{{IP: net.IPv4(127, 0, 0, 1)}}, nil
+	}
+
+	get := func() {
+		if _, err := c.GetPost("abc"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		c.client.Transport.(*http.Transport).CloseIdleConnections()
+	}
+	get()
+	get()
+	if lookups != 1 {
+		t.Errorf("Expected 1 lookup, got %d", lookups)
+	}
+	now = now.Add(time.Minute)
+	get()
+	if lookups != 2 {
+		t.Errorf("Expected an expired address to be looked up again, got %d lookups", lookups)
+	}
+}
+
+func TestDialRaceFallback(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	// The primary address doesn't answer, so the fallback should connect.
+	d := &net.Dialer{Timeout: 5 * time.Second}
+	primaries := []net.IPAddr{{IP: net.ParseIP("192.0.2.1")}}
+	fallbacks := []net.IPAddr{{IP: net.IPv4(127, 0, 0, 1)}}
+	start := time.Now()
+	conn, err := dialRace(context.Background(), d, 50*time.Millisecond, "tcp", port, primaries, fallbacks)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("Expected the fallback to connect without waiting for the primary")
+	}
+}
+
+func TestPartitionAddrs(t *testing.T) {
+	addrs := []net.IPAddr{{IP: net.ParseIP("2001:db8::1")}, {IP: net.ParseIP("192.0.2.1")}, {IP: net.ParseIP("2001:db8::2")}}
+	p, f := partitionAddrs(addrs, "tcp")
+	if len(p) != 2 || len(f) != 1 || f[0].IP.String() != "192.0.2.1" {
+		t.Errorf("Unexpected partition: %v, %v", p, f)
+	}
+	p, f = partitionAddrs(addrs, "tcp4")
+	if len(p) != 1 || len(f) != 0 {
+		t.Errorf("Unexpected tcp4 partition: %v, %v", p, f)
+	}
+}
+
+func TestDialOptionsSharedTransport(t *testing.T) {
+	shared := &http.Transport{}
+	c := NewClient(WithTransport(shared), WithDialOptions(DialOptions{DNSCacheTTL: time.Minute}))
+	if shared.DialContext != nil {
+		t.Errorf("Expected the shared transport to be left alone")
+	}
+	if tr := c.client.Transport.(*http.Transport); tr == shared || tr.DialContext == nil {
+		t.Errorf("Expected a clone of the transport with the dialer")
+	}
+}
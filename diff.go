@@ -0,0 +1,92 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"strings"
+)
+
+// diffLine is a line of a line-by-line diff: kept (' '), removed ('-'), or
+// added ('+').
+type diffLine struct {
+	Op   byte
+	Text string
+}
+
+// diffLines returns the shortest edit turning a into b, by way of their
+// longest common subsequence.
+func diffLines(a, b []string) []diffLine {
+	lcs := lcsTable(a, b)
+
+	var d []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] == b[j] {
+			d = append(d, diffLine{' ', a[i]})
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			d = append(d, diffLine{'-', a[i]})
+			i++
+		} else {
+			d = append(d, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		d = append(d, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		d = append(d, diffLine{'+', b[j]})
+	}
+	return d
+}
+
+// lcsTable returns the lengths of the longest common subsequences of every
+// pair of suffixes of a and b.
+func lcsTable(a, b []string) [][]int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	return lcs
+}
+
+// lcsMatches returns, for each line of a, the index of the line of b it's
+// matched with in their longest common subsequence, or -1.
+func lcsMatches(a, b []string) []int {
+	lcs := lcsTable(a, b)
+	m := make([]int, len(a))
+	i, j := 0, 0
+	for i < len(a) {
+		if j < len(b) && a[i] == b[j] {
+			m[i] = j
+			i++
+			j++
+		} else if j < len(b) && lcs[i+1][j] < lcs[i][j+1] {
+			j++
+		} else {
+			m[i] = -1
+			i++
+		}
+	}
+	return m
+}
+
+// splitLines splits content into lines, without a trailing empty line.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
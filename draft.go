@@ -0,0 +1,87 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Draft is writing that hasn't been published yet, as kept by an editor. It
+// has a post's content and metadata, but no ID or token: those only exist
+// once it's published with PublishDraft, which returns the Post. Changes to a
+// draft of an existing post are saved to it with SaveDraft.
+type Draft struct {
+	Title    string  `json:"title,omitempty"`
+	Content  string  `json:"body"`
+	Slug     string  `json:"slug,omitempty"`
+	Font     string  `json:"font,omitempty"`
+	IsRTL    *bool   `json:"rtl,omitempty"`
+	Language *string `json:"lang,omitempty"`
+
+	// Collection is the alias of the collection to publish to, or empty for
+	// an anonymous post.
+	Collection string `json:"collection,omitempty"`
+	// Created, if set, backdates the post when it's published.
+	Created *time.Time `json:"created,omitempty"`
+}
+
+// DraftFromPost returns a Draft of a published post, for editing it.
+func DraftFromPost(p *Post) *Draft {
+	d := &Draft{
+		Title:    p.Title,
+		Content:  p.Content,
+		Slug:     p.Slug,
+		Font:     p.Font,
+		IsRTL:    p.RTL,
+		Language: p.Language,
+	}
+	if p.Collection != nil {
+		d.Collection = p.Collection.Alias
+	}
+	return d
+}
+
+// IsEmpty returns whether the draft has no title or content worth
+// publishing.
+func (d *Draft) IsEmpty() bool {
+	return strings.TrimSpace(d.Title) == "" && strings.TrimSpace(d.Content) == ""
+}
+
+// Params returns the PostParams for publishing the draft.
+func (d *Draft) Params() *PostParams {
+	return &PostParams{
+		Slug:       d.Slug,
+		Title:      d.Title,
+		Content:    d.Content,
+		Font:       d.Font,
+		IsRTL:      d.IsRTL,
+		Language:   d.Language,
+		Created:    d.Created,
+		Collection: d.Collection,
+	}
+}
+
+// PublishDraft publishes the draft as a new post. Empty drafts aren't
+// published.
+func (c *Client) PublishDraft(d *Draft) (*Post, error) {
+	if d.IsEmpty() {
+		return nil, fmt.Errorf("Draft is empty.")
+	}
+	return c.CreatePost(d.Params())
+}
+
+// SaveDraft saves the draft to the published post p, which it was usually
+// made from with DraftFromPost, returning the updated post. The draft's
+// Collection and Created are only used when publishing, so they're ignored.
+func (c *Client) SaveDraft(d *Draft, p *Post) (*Post, error) {
+	if d.IsEmpty() {
+		return nil, fmt.Errorf("Draft is empty.")
+	}
+	sp := d.Params()
+	sp.ID = p.ID
+	sp.Token = p.Token
+	sp.Collection = ""
+	sp.Created = nil
+	return c.UpdatePost(sp)
+}
@@ -0,0 +1,39 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import "testing"
+
+func TestDraftLifecycle(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	c := api.client()
+
+	if _, err := c.PublishDraft(&Draft{Content: " \n"}); err == nil {
+		t.Errorf("Expected an error publishing an empty draft")
+	}
+
+	d := &Draft{Title: "Hello", Content: "First draft.", Slug: "hello", Collection: "blog"}
+	p, err := c.PublishDraft(d)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.ID == "" || p.Collection == nil || p.Collection.Alias != "blog" || p.Slug != "hello" {
+		t.Errorf("Unexpected published post: %+v", p)
+	}
+
+	edit := DraftFromPost(p)
+	if edit.Collection != "blog" || edit.Content != "First draft." {
+		t.Errorf("Unexpected draft of post: %+v", edit)
+	}
+	edit.Content = "Second draft."
+	if p, err = c.SaveDraft(edit, p); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Content != "Second draft." || api.posts[p.ID].Content != "Second draft." {
+		t.Errorf("Unexpected saved post: %+v", p)
+	}
+	if got := api.requests[len(api.requests)-1]; got != "PUT /posts/"+p.ID {
+		t.Errorf("Unexpected request: %s", got)
+	}
+}
@@ -0,0 +1,247 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+type (
+	// ImageUploader hosts an image, returning its public URL.
+	ImageUploader interface {
+		UploadImage(filename, contentType string, r io.Reader) (string, error)
+	}
+
+	// EmailMessage is an email parsed into the parts needed for a post.
+	EmailMessage struct {
+		From        *mail.Address
+		Subject     string
+		Body        string
+		Attachments []EmailAttachment
+
+		// AuthenticationResults are the message's Authentication-Results
+		// headers, as added by the mail servers it went through.
+		AuthenticationResults []string
+	}
+
+	// EmailAttachment is a file attached to an email.
+	EmailAttachment struct {
+		Filename    string
+		ContentType string
+		Data        []byte
+	}
+
+	// EmailGateway publishes RFC 5322 email messages as posts, so it can be
+	// paired with any mail receiver to enable post-by-email.
+	EmailGateway struct {
+		Client     *Client
+		Collection string
+
+		// Uploader hosts image attachments, which are then embedded at the
+		// end of the post. If nil, attachments are ignored.
+		Uploader ImageUploader
+
+		// AllowedSenders, if set, restricts publishing to messages from these
+		// addresses. On its own, this only checks the From header, which
+		// anyone can forge, so set AuthServID too unless the mail receiver
+		// already rejects unauthenticated mail.
+		AllowedSenders []string
+
+		// AuthServID is the authserv-id of the receiving mail server, the
+		// first field of the Authentication-Results headers it adds. If set,
+		// messages from AllowedSenders must also have passed DMARC, or DKIM
+		// or SPF for the From address's domain, according to those headers.
+		// Headers from other servers are ignored, since senders can add
+		// their own.
+		AuthServID string
+	}
+)
+
+// ParseEmail parses an RFC 5322 email message. The body is taken from the
+// first text/plain or text/markdown part.
+func ParseEmail(r io.Reader) (*EmailMessage, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid email: %v", err)
+	}
+
+	em := &EmailMessage{}
+	if from := msg.Header.Get("From"); from != "" {
+		em.From, err = mail.ParseAddress(from)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid From address: %v", err)
+		}
+	}
+	em.AuthenticationResults = msg.Header["Authentication-Results"]
+	dec := new(mime.WordDecoder)
+	em.Subject, err = dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		em.Subject = msg.Header.Get("Subject")
+	}
+
+	err = em.parsePart(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), "", msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	em.Body = strings.TrimSpace(strings.Replace(em.Body, "\r\n", "\n", -1))
+	return em, nil
+}
+
+func (em *EmailMessage) parsePart(contentType, encoding, disposition string, body io.Reader) error {
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("Invalid Content-Type: %v", err)
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			p, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("Read email part: %v", err)
+			}
+			err = em.parsePart(p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"), p.Header.Get("Content-Disposition"), p)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := ioutil.ReadAll(decodeTransfer(encoding, body))
+	if err != nil {
+		return fmt.Errorf("Decode email part: %v", err)
+	}
+
+	_, dparams, _ := mime.ParseMediaType(disposition)
+	filename := dparams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+
+	if filename == "" && em.Body == "" && (mediaType == "text/plain" || mediaType == "text/markdown") {
+		em.Body = string(data)
+	} else if filename != "" || !strings.HasPrefix(mediaType, "text/") {
+		em.Attachments = append(em.Attachments, EmailAttachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			Data:        data,
+		})
+	}
+	return nil
+}
+
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+// Publish parses the given email message and publishes it as a post.
+func (g *EmailGateway) Publish(r io.Reader) (*Post, error) {
+	em, err := ParseEmail(r)
+	if err != nil {
+		return nil, err
+	}
+	if !g.allowed(em) {
+		return nil, fmt.Errorf("Sender not allowed.")
+	}
+	if em.Body == "" && len(em.Attachments) == 0 {
+		return nil, fmt.Errorf("Email has no content.")
+	}
+
+	content := em.Body
+	if g.Uploader != nil {
+		for _, a := range em.Attachments {
+			if !strings.HasPrefix(a.ContentType, "image/") {
+				continue
+			}
+			u, err := g.Uploader.UploadImage(a.Filename, a.ContentType, bytes.NewReader(a.Data))
+			if err != nil {
+				return nil, fmt.Errorf("Upload %s: %v", a.Filename, err)
+			}
+			content += fmt.Sprintf("\n\n![%s](%s)", a.Filename, u)
+		}
+	}
+
+	return g.Client.CreatePost(&PostParams{
+		Title:      em.Subject,
+		Content:    strings.TrimSpace(content),
+		Collection: g.Collection,
+	})
+}
+
+func (g *EmailGateway) allowed(em *EmailMessage) bool {
+	if len(g.AllowedSenders) == 0 {
+		return true
+	}
+	if em.From == nil {
+		return false
+	}
+	for _, s := range g.AllowedSenders {
+		if strings.EqualFold(s, em.From.Address) {
+			return g.AuthServID == "" || g.authenticated(em)
+		}
+	}
+	return false
+}
+
+// authenticated returns whether the receiving mail server found the message
+// to come from the domain of its From address, by DMARC, DKIM, or SPF.
+func (g *EmailGateway) authenticated(em *EmailMessage) bool {
+	domain := em.From.Address[strings.LastIndex(em.From.Address, "@")+1:]
+	for _, h := range em.AuthenticationResults {
+		parts := strings.Split(authCommentReg.ReplaceAllString(h, " "), ";")
+		if id := strings.Fields(parts[0]); len(id) == 0 || !strings.EqualFold(id[0], g.AuthServID) {
+			continue
+		}
+		for _, res := range parts[1:] {
+			fields := strings.Fields(res)
+			if len(fields) == 0 || !strings.EqualFold(fields[0][strings.Index(fields[0], "=")+1:], "pass") {
+				continue
+			}
+			var prop string
+			switch strings.ToLower(strings.SplitN(fields[0], "=", 2)[0]) {
+			case "dmarc":
+				prop = "header.from"
+			case "dkim":
+				prop = "header.d"
+			case "spf":
+				prop = "smtp.mailfrom"
+			default:
+				continue
+			}
+			for _, f := range fields[1:] {
+				kv := strings.SplitN(f, "=", 2)
+				if len(kv) != 2 || !strings.EqualFold(kv[0], prop) {
+					continue
+				}
+				// smtp.mailfrom may be a whole address
+				if v := kv[1][strings.LastIndex(kv[1], "@")+1:]; strings.EqualFold(v, domain) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// authCommentReg matches the comments in an Authentication-Results header.
+var authCommentReg = regexp.MustCompile(`\([^()]*\)`)
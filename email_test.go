@@ -0,0 +1,80 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestParseEmail(t *testing.T) {
+	msg := "From: Matt <matt@write.as>\r\n" +
+		"Subject: =?UTF-8?Q?Caf=C3=A9_notes?=\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/mixed; boundary=XYZ\r\n" +
+		"\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: quoted-printable\r\n" +
+		"\r\n" +
+		"This is *Markdown*=3D\r\n" +
+		"--XYZ\r\n" +
+		"Content-Type: image/png\r\n" +
+		"Content-Disposition: attachment; filename=\"pic.png\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"iVBORw0K\r\n" +
+		"--XYZ--\r\n"
+
+	em, err := ParseEmail(strings.NewReader(msg))
+	if err != nil {
+		t.Fatalf("Unable to parse email: %v", err)
+	}
+	if em.From.Address != "matt@write.as" || em.Subject != "Café notes" || em.Body != "This is *Markdown*=" {
+		t.Errorf("Unexpected email: %+v", em)
+	}
+	if len(em.Attachments) != 1 || em.Attachments[0].Filename != "pic.png" || len(em.Attachments[0].Data) != 6 {
+		t.Errorf("Unexpected attachments: %+v", em.Attachments)
+	}
+
+	g := &EmailGateway{AllowedSenders: []string{"someone@example.com"}}
+	if g.allowed(em) {
+		t.Errorf("Expected sender to be rejected")
+	}
+}
+
+func TestEmailGatewayAuthentication(t *testing.T) {
+	g := &EmailGateway{AllowedSenders: []string{"matt@write.as"}, AuthServID: "mx.example.com"}
+	tests := []struct {
+		results []string
+		allowed bool
+	}{
+		{nil, false},
+		{[]string{"mx.example.com; dkim=pass header.d=write.as"}, true},
+		{[]string{"mx.example.com 1; spf=pass (sender allowed) smtp.mailfrom=bounce@Write.as"}, true},
+		{[]string{"mx.example.com;\r\n dkim=fail header.d=write.as;\r\n dmarc=pass header.from=write.as"}, true},
+		{[]string{"mx.example.com; dkim=pass header.d=evil.example"}, false},
+		{[]string{"mx.example.com; dkim=fail header.d=write.as"}, false},
+		{[]string{"mx.evil.example; dkim=pass header.d=write.as"}, false},
+		{[]string{"mx.example.com; none"}, false},
+	}
+	for _, test := range tests {
+		em := &EmailMessage{From: &mail.Address{Address: "matt@write.as"}, AuthenticationResults: test.results}
+		if got := g.allowed(em); got != test.allowed {
+			t.Errorf("Unexpected result for %q: %v", test.results, got)
+		}
+	}
+
+	msg := "From: matt@write.as\r\n" +
+		"Authentication-Results: mx.example.com;\r\n" +
+		"  dkim=pass header.d=write.as\r\n" +
+		"\r\n" +
+		"Hi\r\n"
+	em, err := ParseEmail(strings.NewReader(msg))
+	if err != nil {
+		t.Fatalf("Unable to parse email: %v", err)
+	}
+	if !g.allowed(em) {
+		t.Errorf("Expected authenticated sender to be allowed: %q", em.AuthenticationResults)
+	}
+}
@@ -0,0 +1,126 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+)
+
+// EnsureAction describes what an Ensure call did to reach the desired state.
+type EnsureAction string
+
+// Actions returned by the Ensure calls.
+const (
+	EnsureCreated   EnsureAction = "created"
+	EnsureUpdated   EnsureAction = "updated"
+	EnsureUnchanged EnsureAction = "unchanged"
+)
+
+// EnsurePost creates the given post in sp.Collection if no post with sp.Slug
+// exists there yet, or updates the existing post if its title or content
+// differ. It returns the resulting Post and the action taken.
+func (c *Client) EnsurePost(sp *PostParams) (*Post, EnsureAction, error) {
+	alias := sp.Collection
+	if alias == "" && c.defaults != nil {
+		alias = c.defaults.Collection
+	}
+	if alias == "" || sp.Slug == "" {
+		return nil, "", fmt.Errorf("EnsurePost needs a collection and slug.")
+	}
+
+	existing, err := c.findCollectionPost(alias, sp.Slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing == nil {
+		p, err := c.CreatePost(sp)
+		if err != nil {
+			return nil, "", err
+		}
+		return p, EnsureCreated, nil
+	}
+
+	want, err := c.prepareParams(sp, false)
+	if err != nil {
+		return nil, "", err
+	}
+	if postMatches(existing, want) {
+		return existing, EnsureUnchanged, nil
+	}
+
+	up := *sp
+	up.ID = existing.ID
+	p, err := c.UpdatePost(&up)
+	if err != nil {
+		return nil, "", err
+	}
+	return p, EnsureUpdated, nil
+}
+
+// postMatches reports whether the post already has the values that would be
+// set by the given, prepared PostParams.
+func postMatches(p *Post, sp *PostParams) bool {
+	if p.Title != sp.Title || !ContentEqual(p.Content, sp.Content) {
+		return false
+	}
+	if sp.Font != "" && p.Font != sp.Font {
+		return false
+	}
+	if sp.Language != nil && (p.Language == nil || *p.Language != *sp.Language) {
+		return false
+	}
+	return true
+}
+
+// EnsureCollection creates the collection with sp.Alias if it doesn't exist
+// yet, or updates its title, description, and style sheet if they differ. It
+// returns the resulting Collection and the action taken.
+func (c *Client) EnsureCollection(sp *CollectionParams) (*Collection, EnsureAction, error) {
+	if sp.Alias == "" {
+		return nil, "", fmt.Errorf("EnsureCollection needs an alias.")
+	}
+
+	existing, err := c.findCollection(sp.Alias)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing == nil {
+		coll, err := c.CreateCollection(sp)
+		if err != nil {
+			return nil, "", err
+		}
+		if coll.Description == sp.Description && coll.StyleSheet == sp.StyleSheet {
+			return coll, EnsureCreated, nil
+		}
+		// Not every instance takes these on creation
+		coll, err = c.UpdateCollection(sp.Alias, sp)
+		if err != nil {
+			return nil, "", err
+		}
+		return coll, EnsureCreated, nil
+	}
+
+	if collectionMatches(existing, sp) {
+		return existing, EnsureUnchanged, nil
+	}
+	coll, err := c.UpdateCollection(sp.Alias, sp)
+	if err != nil {
+		return nil, "", err
+	}
+	return coll, EnsureUpdated, nil
+}
+
+// collectionMatches reports whether the collection already has the values
+// that would be set by the given CollectionParams. UpdateCollection leaves
+// empty fields unchanged, so only those that are set are compared.
+func collectionMatches(coll *Collection, sp *CollectionParams) bool {
+	if sp.HideViews != nil && (coll.HideViews == nil || *coll.HideViews != *sp.HideViews) {
+		return false
+	}
+	if sp.Title != "" && coll.Title != sp.Title {
+		return false
+	}
+	if sp.Description != "" && coll.Description != sp.Description {
+		return false
+	}
+	return sp.StyleSheet == "" || coll.StyleSheet == sp.StyleSheet
+}
@@ -0,0 +1,119 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnsurePost(t *testing.T) {
+	posts := map[string]*Post{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		code := http.StatusOK
+		var p *Post
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/collections/blog/posts/hello":
+			if p = posts["hello"]; p == nil {
+				code = http.StatusNotFound
+			}
+		case r.Method == "POST" && r.URL.Path == "/collections/blog/posts":
+			sp := &PostParams{}
+			json.NewDecoder(r.Body).Decode(sp)
+			p = &Post{ID: "p1", Slug: sp.Slug, Title: sp.Title, Content: sp.Content}
+			posts[sp.Slug] = p
+			code = http.StatusCreated
+		case r.Method == "PUT" && r.URL.Path == "/posts/p1":
+			sp := &PostParams{}
+			json.NewDecoder(r.Body).Decode(sp)
+			p = posts["hello"]
+			p.Title, p.Content = sp.Title, sp.Content
+		default:
+			t.Fatalf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		res := map[string]interface{}{"code": code}
+		if p != nil {
+			res["data"] = p
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.baseURL = srv.URL
+	sp := &PostParams{Collection: "blog", Slug: "hello", Title: "Hello", Content: "Hi there."}
+
+	expected := []EnsureAction{EnsureCreated, EnsureUnchanged, EnsureUpdated}
+	for i, exp := range expected {
+		if i == 2 {
+			sp.Content = "Hi again."
+		}
+		p, action, err := c.EnsurePost(sp)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if action != exp || p.Content != sp.Content {
+			t.Errorf("Unexpected result %d: %s, %+v", i, action, p)
+		}
+	}
+
+	if _, _, err := c.EnsurePost(&PostParams{Collection: "blog"}); err == nil {
+		t.Errorf("Expected error without a slug")
+	}
+}
+
+func TestEnsureCollection(t *testing.T) {
+	var coll *Collection
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := map[string]interface{}{"code": http.StatusOK}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/collections/blog":
+			if coll == nil {
+				res["code"] = http.StatusNotFound
+			}
+		case r.Method == "POST" && r.URL.Path == "/collections":
+			sp := &CollectionParams{}
+			json.NewDecoder(r.Body).Decode(sp)
+			coll = &Collection{Alias: sp.Alias, Title: sp.Title}
+			res["code"] = http.StatusCreated
+		case r.Method == "POST" && r.URL.Path == "/collections/blog":
+			json.NewDecoder(r.Body).Decode(coll)
+		default:
+			t.Fatalf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if coll != nil {
+			res["data"] = coll
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.baseURL = srv.URL
+	sp := &CollectionParams{Alias: "blog", Title: "Blog", Description: "Thoughts."}
+
+	expected := []EnsureAction{EnsureCreated, EnsureUnchanged, EnsureUpdated}
+	for i, exp := range expected {
+		if i == 2 {
+			sp.Title = "My Blog"
+		}
+		coll, action, err := c.EnsureCollection(sp)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if action != exp || coll.Title != sp.Title || coll.Description != sp.Description {
+			t.Errorf("Unexpected result %d: %s, %+v", i, action, coll)
+		}
+	}
+}
+
+func TestCollectionMatchesEmptyParams(t *testing.T) {
+	coll := &Collection{Alias: "blog", Title: "Blog", Description: "Thoughts.", StyleSheet: "body {}"}
+	if !collectionMatches(coll, &CollectionParams{Alias: "blog", Title: "Blog"}) {
+		t.Errorf("Expected empty fields, which UpdateCollection leaves alone, to match")
+	}
+	if collectionMatches(coll, &CollectionParams{Alias: "blog", Description: "Other."}) {
+		t.Errorf("Expected a different description not to match")
+	}
+}
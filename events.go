@@ -0,0 +1,145 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Event is something a Client did, delivered to the functions passed to
+// Subscribe. It's one of the event types below.
+type Event interface {
+	event()
+}
+
+type (
+	// PostPublished is emitted after CreatePost publishes a post.
+	PostPublished struct {
+		Post *Post
+	}
+
+	// PostUpdated is emitted after UpdatePost updates a post.
+	PostUpdated struct {
+		Post *Post
+	}
+
+	// PostDeleted is emitted after DeletePost or DeletePostIdempotent
+	// deletes a post.
+	PostDeleted struct {
+		ID string
+	}
+
+	// SyncCompleted is emitted after SyncFile syncs a file without a
+	// conflict.
+	SyncCompleted struct {
+		PostID string
+		Result SyncResult
+	}
+
+	// RateLimited is emitted when the API responds to a request with 429
+	// Too Many Requests.
+	RateLimited struct {
+		// Endpoint names the endpoint, like "GET /posts/{id}".
+		Endpoint string
+		// RetryAfter is how long the API asked to wait, or 0 if it didn't
+		// say.
+		RetryAfter time.Duration
+	}
+
+	// ResponseRefreshed is emitted when a stale cached response, returned
+	// under Policy.StaleWhileRevalidate, is refreshed with different data, so
+	// the caller can request it again.
+	ResponseRefreshed struct {
+		// Path is the request's path relative to the API, like
+		// "/collections/blog/posts".
+		Path string
+	}
+
+	// CacheWarmed is emitted by WarmCache as it finishes each collection.
+	CacheWarmed struct {
+		Alias string
+		// Err is why the collection couldn't be fetched, if it couldn't.
+		Err error
+		// Done is how many collections are finished, of Total.
+		Done  int
+		Total int
+	}
+)
+
+func (PostPublished) event()     {}
+func (PostUpdated) event()       {}
+func (PostDeleted) event()       {}
+func (SyncCompleted) event()     {}
+func (RateLimited) event()       {}
+func (ResponseRefreshed) event() {}
+func (CacheWarmed) event()       {}
+
+// eventBus delivers a Client's events to its subscribers.
+type eventBus struct {
+	mu   sync.Mutex
+	next int
+	subs map[int]func(Event)
+}
+
+// Subscribe calls fn with every event the Client emits from now on, until the
+// returned function is called. Events are delivered synchronously, in the
+// order subscribers subscribed, so fn should hand slow work off to another
+// goroutine. A panic in fn is logged.
+//
+//	unsubscribe := c.Subscribe(func(e writeas.Event) {
+//		if e, ok := e.(writeas.PostPublished); ok {
+//			log.Printf("Published %s", e.Post.URL)
+//		}
+//	})
+//	defer unsubscribe()
+func (c *Client) Subscribe(fn func(Event)) (unsubscribe func()) {
+	b := &c.events
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs == nil {
+		b.subs = map[int]func(Event){}
+	}
+	id := b.next
+	b.next++
+	b.subs[id] = fn
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subs, id)
+	}
+}
+
+// emit delivers e to every subscriber.
+func (c *Client) emit(e Event) {
+	b := &c.events
+	b.mu.Lock()
+	ids := make([]int, 0, len(b.subs))
+	for id := range b.subs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	subs := make([]func(Event), len(ids))
+	for i, id := range ids {
+		subs[i] = b.subs[id]
+	}
+	b.mu.Unlock()
+
+	for _, fn := range subs {
+		if perr := SafeCall("event subscriber", func() { fn(e) }); perr != nil {
+			log.Printf("writeas: %v", perr)
+		}
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds, returning 0 if
+// it's missing or a date.
+func retryAfter(header string) time.Duration {
+	secs, err := strconv.Atoi(header)
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
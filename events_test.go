@@ -0,0 +1,65 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSubscribe(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	c := api.client()
+
+	var got []Event
+	unsubscribe := c.Subscribe(func(e Event) {
+		got = append(got, e)
+	})
+	c.Subscribe(func(Event) {
+		panic("oops")
+	})
+
+	p, err := c.CreatePost(&PostParams{Content: "Hello"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = c.UpdatePost(&PostParams{ID: p.ID, Content: "Hi"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	unsubscribe()
+	c.CreatePost(&PostParams{Content: "Unseen"})
+
+	if len(got) != 2 {
+		t.Fatalf("Unexpected events: %+v", got)
+	}
+	if e, ok := got[0].(PostPublished); !ok || e.Post.ID != p.ID {
+		t.Errorf("Unexpected first event: %+v", got[0])
+	}
+	if e, ok := got[1].(PostUpdated); !ok || e.Post.Content != "Hi" {
+		t.Errorf("Unexpected second event: %+v", got[1])
+	}
+}
+
+func TestRateLimitedEvent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"code":429,"error_msg":"Too many requests."}`)
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	var got []Event
+	c.Subscribe(func(e Event) {
+		got = append(got, e)
+	})
+	if _, err := c.GetPost("abc"); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if len(got) != 1 || got[0] != (RateLimited{Endpoint: "GET /posts/{id}", RetryAfter: 30 * time.Second}) {
+		t.Errorf("Unexpected events: %+v", got)
+	}
+}
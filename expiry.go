@@ -0,0 +1,64 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExpiringPost is a post published with PublishExpiring, which is deleted
+// when its time to live runs out unless it's canceled first.
+type ExpiringPost struct {
+	Post    *Post
+	Expires time.Time
+
+	timer Timer
+	done  chan struct{}
+	err   error
+}
+
+// PublishExpiring publishes an anonymous post and deletes it after ttl, for
+// sharing temporary notes. The deletion is scheduled in this process, so it
+// won't happen if the program exits first; save the Post's ID and Token to
+// delete it later instead. The deletion is timed by the Client's Clock if it's
+// a Scheduler, so it happens at Expires by that Clock.
+func (c *Client) PublishExpiring(sp *PostParams, ttl time.Duration) (*ExpiringPost, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("Invalid TTL %s: it must be positive.", ttl)
+	}
+	if c.applyDefaults(sp).Collection != "" {
+		return nil, fmt.Errorf("Expiring posts can't be published to a collection.")
+	}
+	p, err := c.CreatePost(sp)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &ExpiringPost{
+		Post:    p,
+		Expires: c.Now().Add(ttl),
+		done:    make(chan struct{}),
+	}
+	e.timer = c.afterFunc(ttl, func() {
+		e.err = c.DeletePostIdempotent(&PostParams{ID: p.ID, Token: p.Token})
+		close(e.done)
+	})
+	return e, nil
+}
+
+// Cancel keeps the post from being deleted, returning false if it already was
+// or the deletion is in progress.
+func (e *ExpiringPost) Cancel() bool {
+	if !e.timer.Stop() {
+		return false
+	}
+	close(e.done)
+	return true
+}
+
+// Wait blocks until the post is deleted or canceled, returning any error from
+// deleting it.
+func (e *ExpiringPost) Wait() error {
+	<-e.done
+	return e.err
+}
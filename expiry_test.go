@@ -0,0 +1,125 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeScheduler is a Scheduler whose time only moves with Advance.
+type fakeScheduler struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	s    *fakeScheduler
+	at   time.Time
+	f    func()
+	done bool
+}
+
+func (s *fakeScheduler) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+func (s *fakeScheduler) AfterFunc(d time.Duration, f func()) Timer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := &fakeTimer{s: s, at: s.now.Add(d), f: f}
+	s.timers = append(s.timers, t)
+	return t
+}
+
+// Advance moves the time forward by d, calling the functions that come due.
+func (s *fakeScheduler) Advance(d time.Duration) {
+	s.mu.Lock()
+	s.now = s.now.Add(d)
+	var due []func()
+	for _, t := range s.timers {
+		if !t.done && !t.at.After(s.now) {
+			t.done = true
+			due = append(due, t.f)
+		}
+	}
+	s.mu.Unlock()
+	for _, f := range due {
+		f()
+	}
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+	stopped := !t.done
+	t.done = true
+	return stopped
+}
+
+func TestPublishExpiring(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	c := api.client()
+
+	e, err := c.PublishExpiring(&PostParams{Content: "Temporary"}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = e.Wait(); err != nil {
+		t.Errorf("Unexpected delete error: %v", err)
+	}
+	if _, ok := api.posts[e.Post.ID]; ok {
+		t.Errorf("Expected post to be deleted")
+	}
+	if e.Cancel() {
+		t.Errorf("Expected Cancel to fail after deletion")
+	}
+
+	e, err = c.PublishExpiring(&PostParams{Content: "Kept"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !e.Cancel() {
+		t.Errorf("Expected Cancel to succeed")
+	}
+	if err = e.Wait(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, ok := api.posts[e.Post.ID]; !ok {
+		t.Errorf("Expected post to be kept")
+	}
+
+	if _, err = c.PublishExpiring(&PostParams{Content: "Blog", Collection: "blog"}, time.Hour); err == nil {
+		t.Errorf("Expected an error for a collection post")
+	}
+}
+
+func TestPublishExpiringScheduler(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	s := &fakeScheduler{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := api.client(WithClock(s))
+
+	e, err := c.PublishExpiring(&PostParams{Content: "Temporary"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !e.Expires.Equal(s.now.Add(time.Hour)) {
+		t.Errorf("Unexpected expiry: %s", e.Expires)
+	}
+	s.Advance(59 * time.Minute)
+	if _, ok := api.posts[e.Post.ID]; !ok {
+		t.Errorf("Expected post to be kept before it expires")
+	}
+	s.Advance(time.Minute)
+	if err = e.Wait(); err != nil {
+		t.Errorf("Unexpected delete error: %v", err)
+	}
+	if _, ok := api.posts[e.Post.ID]; ok {
+		t.Errorf("Expected post to be deleted when it expires")
+	}
+}
@@ -0,0 +1,96 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	// AccountData is all the personal data stored in a user's account, for
+	// data portability requests.
+	AccountData struct {
+		Exported    time.Time               `json:"exported"`
+		User        *User                   `json:"user"`
+		Collections []CollectionAccountData `json:"collections"`
+		Posts       []Post                  `json:"posts"`
+	}
+
+	// CollectionAccountData is a collection and its subscribers.
+	CollectionAccountData struct {
+		Collection
+		Subscribers []Subscriber `json:"subscribers"`
+	}
+
+	// Subscriber is someone subscribed to a collection by email.
+	Subscriber struct {
+		Email   string    `json:"email"`
+		Created time.Time `json:"created"`
+	}
+)
+
+// ExportAccountData gathers the authenticated user's profile, collections,
+// posts, and collection subscribers, to be encoded as JSON. Subscribers are
+// empty on instances that don't support email subscriptions.
+func (c *Client) ExportAccountData() (*AccountData, error) {
+	u, err := c.GetMe()
+	if err != nil {
+		return nil, err
+	}
+	colls, err := c.GetUserCollections()
+	if err != nil {
+		return nil, err
+	}
+	posts, err := c.GetUserPosts()
+	if err != nil {
+		return nil, err
+	}
+
+	d := &AccountData{
+		Exported:    c.Now().UTC(),
+		User:        u,
+		Collections: []CollectionAccountData{},
+		Posts:       *posts,
+	}
+	for _, coll := range *colls {
+		subs, err := c.GetCollectionSubscribers(coll.Alias)
+		if err != nil {
+			return nil, err
+		}
+		d.Collections = append(d.Collections, CollectionAccountData{Collection: coll, Subscribers: subs})
+	}
+	return d, nil
+}
+
+// GetCollectionSubscribers retrieves the email subscribers of a collection the
+// authenticated user owns. It returns no subscribers on instances that don't
+// support email subscriptions.
+func (c *Client) GetCollectionSubscribers(alias string) ([]Subscriber, error) {
+	alias, err := ParseCollectionAlias(alias)
+	if err != nil {
+		return nil, err
+	}
+	subs := &[]Subscriber{}
+	env, err := c.get(fmt.Sprintf("/collections/%s/subscribers", alias), subs)
+	if err != nil {
+		return nil, err
+	}
+
+	status := env.Code
+	if status == http.StatusNotFound {
+		return []Subscriber{}, nil
+	}
+
+	var ok bool
+	if subs, ok = env.Data.(*[]Subscriber); !ok {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+	if status != http.StatusOK {
+		if c.isNotLoggedIn(status) {
+			return nil, fmt.Errorf("Not authenticated.")
+		}
+		return nil, fmt.Errorf("Problem getting subscribers: %d. %v\n", status, err)
+	}
+	return *subs, nil
+}
@@ -0,0 +1,29 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestExportAccountData(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	api.collections["notes"] = &Collection{Alias: "notes"}
+	api.subscribers["blog"] = []Subscriber{{Email: "reader@example.com"}}
+	api.addPost("blog", Post{Slug: "hello"})
+
+	d, err := api.client().ExportAccountData()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d.User.Username != "writer" || len(d.Posts) != 1 || len(d.Collections) != 2 {
+		t.Errorf("Unexpected data: %+v", d)
+	}
+	if subs := d.Collections[0].Subscribers; len(subs) != 1 || subs[0].Email != "reader@example.com" {
+		t.Errorf("Unexpected subscribers: %+v", subs)
+	}
+	if subs := d.Collections[1].Subscribers; subs == nil || len(subs) != 0 {
+		t.Errorf("Expected empty subscribers, got %+v", subs)
+	}
+}
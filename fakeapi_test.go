@@ -0,0 +1,275 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeAPI is an in-memory stand-in for the Write.as API's collection and post
+// endpoints, for testing calls built on top of them.
+type fakeAPI struct {
+	*httptest.Server
+	t           *testing.T
+	collections map[string]*Collection
+	posts       map[string]*Post
+	pinned      map[string][]string
+	subscribers map[string][]Subscriber
+	requests    []string
+
+	// noTotals leaves total_posts out of collections, like some instances.
+	noTotals bool
+}
+
+func newFakeAPI(t *testing.T) *fakeAPI {
+	api := &fakeAPI{
+		t:           t,
+		collections: map[string]*Collection{},
+		posts:       map[string]*Post{},
+		pinned:      map[string][]string{},
+		subscribers: map[string][]Subscriber{},
+	}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.handle))
+	return api
+}
+
+// client returns a Client that talks to the fake API.
+func (api *fakeAPI) client(opts ...Option) *Client {
+	c := NewClient(opts...)
+	c.baseURL = api.URL
+	return c
+}
+
+// addPost adds a post to the given collection.
+func (api *fakeAPI) addPost(alias string, p Post) *Post {
+	if p.ID == "" {
+		p.ID = fmt.Sprintf("p%d", len(api.posts)+1)
+	}
+	if alias != "" {
+		p.Collection = api.collections[alias]
+	}
+	api.posts[p.ID] = &p
+	return &p
+}
+
+// sortedPosts returns the posts in the given collection, or all posts if alias
+// is empty, ordered by ID.
+func (api *fakeAPI) sortedPosts(alias string) []Post {
+	posts := []Post{}
+	for _, p := range api.posts {
+		if alias == "" || (p.Collection != nil && p.Collection.Alias == alias) {
+			posts = append(posts, *p)
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool { return posts[i].ID < posts[j].ID })
+	return posts
+}
+
+func (api *fakeAPI) collectionPost(alias, slug string) *Post {
+	for _, p := range api.posts {
+		if p.Collection != nil && p.Collection.Alias == alias && p.Slug == slug {
+			return p
+		}
+	}
+	return nil
+}
+
+func (api *fakeAPI) handle(w http.ResponseWriter, r *http.Request) {
+	api.requests = append(api.requests, r.Method+" "+r.URL.Path)
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	code := http.StatusOK
+	var data interface{}
+
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/me":
+		data = &User{Username: "writer"}
+	case r.Method == "DELETE" && r.URL.Path == "/me":
+		api.collections = map[string]*Collection{}
+		api.posts = map[string]*Post{}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case r.Method == "GET" && r.URL.Path == "/me/collections":
+		colls := []Collection{}
+		for _, coll := range api.collections {
+			colls = append(colls, *coll)
+		}
+		sort.Slice(colls, func(i, j int) bool { return colls[i].Alias < colls[j].Alias })
+		data = colls
+	case r.Method == "GET" && r.URL.Path == "/me/posts":
+		data = api.sortedPosts("")
+	case r.Method == "POST" && r.URL.Path == "/posts":
+		sp := &PostParams{}
+		json.NewDecoder(r.Body).Decode(sp)
+		code, data = http.StatusCreated, api.addPost("", Post{Title: sp.Title, Content: sp.Content, Font: sp.Font, Language: sp.Language})
+	case r.Method == "POST" && r.URL.Path == "/collections":
+		sp := &CollectionParams{}
+		json.NewDecoder(r.Body).Decode(sp)
+		coll := &Collection{Alias: sp.Alias, Title: sp.Title}
+		api.collections[sp.Alias] = coll
+		code, data = http.StatusCreated, coll
+	case parts[0] == "collections" && len(parts) == 2:
+		coll := api.collections[parts[1]]
+		if coll == nil {
+			code = http.StatusNotFound
+			break
+		}
+		if r.Method == "POST" {
+			json.NewDecoder(r.Body).Decode(coll)
+			if coll.Alias != parts[1] {
+				delete(api.collections, parts[1])
+				api.collections[coll.Alias] = coll
+			}
+		} else if r.Method == "DELETE" {
+			delete(api.collections, parts[1])
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		data = coll
+	case parts[0] == "collections" && len(parts) == 3 && parts[2] == "subscribers":
+		if subs, ok := api.subscribers[parts[1]]; ok {
+			data = subs
+		} else {
+			code = http.StatusNotFound
+		}
+	case parts[0] == "collections" && len(parts) == 3 && parts[2] == "pin":
+		var pins []PinnedPostParams
+		json.NewDecoder(r.Body).Decode(&pins)
+		res := []BatchPostResult{}
+		for _, pin := range pins {
+			kept := []string{}
+			for _, id := range api.pinned[parts[1]] {
+				if id != pin.ID {
+					kept = append(kept, id)
+				}
+			}
+			at := len(kept)
+			if pin.Position > 0 && pin.Position-1 < at {
+				at = pin.Position - 1
+			}
+			api.pinned[parts[1]] = append(kept[:at], append([]string{pin.ID}, kept[at:]...)...)
+			res = append(res, BatchPostResult{ID: pin.ID, Code: http.StatusOK})
+		}
+		data = res
+	case parts[0] == "collections" && len(parts) == 3 && parts[2] == "unpin":
+		var pins []PinnedPostParams
+		json.NewDecoder(r.Body).Decode(&pins)
+		res := []BatchPostResult{}
+		for _, pin := range pins {
+			kept := []string{}
+			for _, id := range api.pinned[parts[1]] {
+				if id != pin.ID {
+					kept = append(kept, id)
+				}
+			}
+			api.pinned[parts[1]] = kept
+			res = append(res, BatchPostResult{ID: pin.ID, Code: http.StatusOK})
+		}
+		data = res
+	case parts[0] == "collections" && len(parts) == 3 && parts[2] == "posts" && r.Method == "GET":
+		coll := *api.collections[parts[1]]
+		posts := api.sortedPosts(parts[1])
+		if !api.noTotals {
+			coll.TotalPosts = len(posts)
+		}
+		// Like the API, return the first page unless another is asked for
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 {
+			page = 1
+		}
+		start := (page - 1) * PostsPerPage
+		if start > len(posts) {
+			start = len(posts)
+		}
+		posts = posts[start:]
+		if len(posts) > PostsPerPage {
+			posts = posts[:PostsPerPage]
+		}
+		coll.Posts = &posts
+		data = &coll
+	case parts[0] == "collections" && len(parts) == 3 && parts[2] == "posts" && r.Method == "POST":
+		sp := &PostParams{}
+		json.NewDecoder(r.Body).Decode(sp)
+		code, data = http.StatusCreated, api.addPost(parts[1], Post{Slug: sp.Slug, Title: sp.Title, Content: sp.Content, Font: sp.Font, Language: sp.Language})
+	case parts[0] == "collections" && len(parts) == 4 && parts[2] == "posts":
+		if p := api.collectionPost(parts[1], parts[3]); p != nil {
+			data = p
+		} else {
+			code = http.StatusNotFound
+		}
+	case parts[0] == "users" && len(parts) == 3 && parts[2] == "posts":
+		posts := []Post{}
+		for _, p := range api.sortedPosts("") {
+			if p.OwnerName == parts[1] {
+				posts = append(posts, p)
+			}
+		}
+		data = posts
+	case r.Method == "PUT" && r.URL.Path == "/posts/claim":
+		var owned []OwnedPostParams
+		json.NewDecoder(r.Body).Decode(&owned)
+		res := []ClaimPostResult{}
+		for _, o := range owned {
+			if p := api.posts[o.ID]; p != nil && p.Token == o.Token {
+				p.OwnerName = "writer"
+				res = append(res, ClaimPostResult{ID: o.ID, Code: http.StatusOK, Post: p})
+			} else {
+				res = append(res, ClaimPostResult{ID: o.ID, Code: http.StatusNotFound, ErrorMessage: "Post not found."})
+			}
+		}
+		data = res
+	case r.Method == "POST" && r.URL.Path == "/posts/disperse":
+		var ids []string
+		json.NewDecoder(r.Body).Decode(&ids)
+		res := []BatchPostResult{}
+		for _, id := range ids {
+			if p := api.posts[id]; p != nil {
+				p.Collection = nil
+				res = append(res, BatchPostResult{ID: id, Code: http.StatusOK})
+			} else {
+				res = append(res, BatchPostResult{ID: id, Code: http.StatusNotFound})
+			}
+		}
+		data = res
+	case parts[0] == "posts" && len(parts) == 2:
+		p := api.posts[parts[1]]
+		if p == nil {
+			code = http.StatusNotFound
+			break
+		}
+		switch r.Method {
+		case "PUT":
+			sp := &PostParams{}
+			json.NewDecoder(r.Body).Decode(sp)
+			p.Title, p.Content = sp.Title, sp.Content
+			if sp.Slug != "" {
+				p.Slug = sp.Slug
+			}
+			if sp.Font != "" {
+				p.Font = sp.Font
+			}
+			if sp.Language != nil {
+				p.Language = sp.Language
+			}
+		case "DELETE":
+			delete(api.posts, p.ID)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		data = p
+	default:
+		api.t.Fatalf("Unexpected request: %s %s", r.Method, r.URL.Path)
+	}
+
+	res := map[string]interface{}{"code": code}
+	if data != nil {
+		res["data"] = data
+	}
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
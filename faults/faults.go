@@ -0,0 +1,152 @@
+#author: Nguyễn Thái Sơn
+// Package faults provides an http.RoundTripper that injects failures into
+// requests made with the Write.as client, so applications can test their
+// retry and backoff handling:
+//
+//	t := &faults.Transport{Schedule: faults.Sequence(faults.TooManyRequests, faults.ConnectionReset)}
+//	c := writeas.NewClient(writeas.WithTransport(t))
+package faults
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+// Fault is a failure injected into a request.
+type Fault int
+
+// Faults that can be injected.
+const (
+	// None lets the request through.
+	None Fault = iota
+	// Timeout fails the request with a timeout error.
+	Timeout
+	// TooManyRequests responds with a 429 status and a Retry-After header.
+	TooManyRequests
+	// MalformedJSON responds with a truncated JSON body.
+	MalformedJSON
+	// ConnectionReset fails the request as if the connection was reset.
+	ConnectionReset
+)
+
+// Schedule decides which Fault to inject into the nth request made through a
+// Transport, counting from 0.
+type Schedule func(n int, r *http.Request) Fault
+
+// Sequence injects the given faults into the first requests, in order, and
+// lets the rest through.
+func Sequence(faults ...Fault) Schedule {
+	return func(n int, r *http.Request) Fault {
+		if n < len(faults) {
+			return faults[n]
+		}
+		return None
+	}
+}
+
+// Every injects the fault into every kth request, starting with the kth.
+func Every(k int, f Fault) Schedule {
+	return func(n int, r *http.Request) Fault {
+		if k > 0 && (n+1)%k == 0 {
+			return f
+		}
+		return None
+	}
+}
+
+// Random injects one of the given faults into requests at the given rate,
+// between 0 and 1, using a source seeded with seed so runs are repeatable.
+func Random(rate float64, seed int64, faults ...Fault) Schedule {
+	var mu sync.Mutex
+	rnd := rand.New(rand.NewSource(seed))
+	return func(n int, r *http.Request) Fault {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(faults) == 0 || rnd.Float64() >= rate {
+			return None
+		}
+		return faults[rnd.Intn(len(faults))]
+	}
+}
+
+// Transport is an http.RoundTripper that injects faults into requests
+// according to its Schedule, passing the others on to Base.
+type Transport struct {
+	// Base makes requests that aren't failed. Defaults to
+	// http.DefaultTransport.
+	Base     http.RoundTripper
+	Schedule Schedule
+	// RetryAfter is the Retry-After value, in seconds, sent with
+	// TooManyRequests responses.
+	RetryAfter int
+
+	mu       sync.Mutex
+	requests int
+	injected []Fault
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	n := t.requests
+	t.requests++
+	f := None
+	if t.Schedule != nil {
+		f = t.Schedule(n, r)
+	}
+	t.injected = append(t.injected, f)
+	t.mu.Unlock()
+
+	switch f {
+	case Timeout:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}
+	case ConnectionReset:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	case TooManyRequests:
+		resp := response(r, http.StatusTooManyRequests, `{"code":429,"error_msg":"Too many requests."}`)
+		resp.Header.Set("Retry-After", strconv.Itoa(t.RetryAfter))
+		return resp, nil
+	case MalformedJSON:
+		return response(r, http.StatusOK, `{"code":200,"data":{"id":`), nil
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(r)
+}
+
+// Injected returns the fault injected into each request so far, in order.
+func (t *Transport) Injected() []Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Fault(nil), t.injected...)
+}
+
+func response(r *http.Request, code int, body string) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(code) + " " + http.StatusText(code),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout (injected)" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,54 @@
+#author: Nguyễn Thái Sơn
+package faults
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":200,"data":{"id":"abc"}}`))
+	}))
+	defer srv.Close()
+
+	tr := &Transport{Schedule: Sequence(Timeout, TooManyRequests, MalformedJSON, ConnectionReset)}
+	c := writeas.NewClient(writeas.WithBaseURL(srv.URL), writeas.WithTransport(tr))
+	for i := 0; i < 4; i++ {
+		if _, err := c.GetPost("abc"); err == nil {
+			t.Errorf("Expected request %d to fail", i)
+		}
+	}
+	if p, err := c.GetPost("abc"); err != nil || p.ID != "abc" {
+		t.Errorf("Expected request to succeed: %v", err)
+	}
+	if f := tr.Injected(); len(f) != 5 || f[1] != TooManyRequests || f[4] != None {
+		t.Errorf("Unexpected faults: %v", f)
+	}
+
+	_, err := (&Transport{Schedule: Every(1, Timeout)}).RoundTrip(httptest.NewRequest("GET", srv.URL, nil))
+	if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	a, b := Random(0.5, 1, Timeout), Random(0.5, 1, Timeout)
+	injected := 0
+	for n := 0; n < 100; n++ {
+		fa, fb := a(n, nil), b(n, nil)
+		if fa != fb {
+			t.Fatalf("Expected the same seed to give the same faults")
+		}
+		if fa != None {
+			injected++
+		}
+	}
+	if injected < 30 || injected > 70 {
+		t.Errorf("Unexpected number of faults: %d", injected)
+	}
+}
@@ -0,0 +1,133 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"crypto/sha256"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// FeedEntry is a single entry of an RSS or Atom feed.
+type FeedEntry struct {
+	ID        string
+	Title     string
+	Link      string
+	Content   string
+	Published time.Time
+}
+
+type (
+	rssFeed struct {
+		Items []struct {
+			GUID        string `xml:"guid"`
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			Description string `xml:"description"`
+			Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			PubDate     string `xml:"pubDate"`
+		} `xml:"channel>item"`
+	}
+
+	atomFeed struct {
+		Entries []struct {
+			ID    string `xml:"id"`
+			Title string `xml:"title"`
+			Links []struct {
+				Href string `xml:"href,attr"`
+				Rel  string `xml:"rel,attr"`
+			} `xml:"link"`
+			Summary   string `xml:"summary"`
+			Content   string `xml:"content"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+		} `xml:"entry"`
+	}
+)
+
+var rssTimeLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC822Z, time.RFC822, "Mon, 2 Jan 2006 15:04:05 -0700"}
+
+// ParseFeed parses an RSS 2.0 or Atom feed, returning its entries in the order
+// they appear.
+func ParseFeed(r io.Reader) ([]FeedEntry, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root struct {
+		XMLName xml.Name
+	}
+	if err = xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("Invalid feed: %v", err)
+	}
+
+	var entries []FeedEntry
+	switch root.XMLName.Local {
+	case "rss":
+		var f rssFeed
+		if err = xml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("Invalid RSS feed: %v", err)
+		}
+		for _, it := range f.Items {
+			e := FeedEntry{ID: it.GUID, Title: it.Title, Link: it.Link, Content: it.Content}
+			if e.Content == "" {
+				e.Content = it.Description
+			}
+			e.Published = parseFeedTime(it.PubDate, rssTimeLayouts...)
+			entries = append(entries, e)
+		}
+	case "feed":
+		var f atomFeed
+		if err = xml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("Invalid Atom feed: %v", err)
+		}
+		for _, en := range f.Entries {
+			e := FeedEntry{ID: en.ID, Title: en.Title, Content: en.Content}
+			if e.Content == "" {
+				e.Content = en.Summary
+			}
+			for _, l := range en.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					e.Link = l.Href
+					break
+				}
+			}
+			pub := en.Published
+			if pub == "" {
+				pub = en.Updated
+			}
+			e.Published = parseFeedTime(pub, time.RFC3339)
+			entries = append(entries, e)
+		}
+	default:
+		return nil, fmt.Errorf("Unknown feed format: <%s>.", root.XMLName.Local)
+	}
+
+	for i := range entries {
+		entries[i].Content = strings.TrimSpace(entries[i].Content)
+		if entries[i].ID == "" {
+			entries[i].ID = entries[i].Link
+		}
+		if entries[i].ID == "" {
+			// Without a guid or link, tell entries apart by their title and
+			// date
+			sum := sha256.Sum256([]byte(entries[i].Title + "\n" + entries[i].Published.UTC().Format(time.RFC3339)))
+			entries[i].ID = fmt.Sprintf("sha256:%x", sum)
+		}
+	}
+	return entries, nil
+}
+
+func parseFeedTime(s string, layouts ...string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, l := range layouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,47 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFeed(t *testing.T) {
+	rss := `<?xml version="1.0"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+<item>
+	<title>Hello</title>
+	<link>https://example.com/hello</link>
+	<description>Summary</description>
+	<content:encoded><![CDATA[<p>Full content</p>]]></content:encoded>
+	<pubDate>Sun, 13 Jan 2019 21:09:00 +0000</pubDate>
+</item>
+</channel>
+</rss>`
+	entries, err := ParseFeed(strings.NewReader(rss))
+	if err != nil {
+		t.Fatalf("Unable to parse RSS: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "https://example.com/hello" || entries[0].Content != "<p>Full content</p>" || entries[0].Published.Year() != 2019 {
+		t.Errorf("Unexpected RSS entries: %+v", entries)
+	}
+
+	atom := `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+<entry>
+	<id>urn:1</id>
+	<title>Hi</title>
+	<link rel="alternate" href="https://example.com/hi"/>
+	<summary>Short</summary>
+	<updated>2019-01-13T21:09:00Z</updated>
+</entry>
+</feed>`
+	entries, err = ParseFeed(strings.NewReader(atom))
+	if err != nil {
+		t.Fatalf("Unable to parse Atom: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "urn:1" || entries[0].Link != "https://example.com/hi" || entries[0].Content != "Short" {
+		t.Errorf("Unexpected Atom entries: %+v", entries)
+	}
+}
@@ -0,0 +1,163 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Filter transforms post content before it's published. Returning an error
+// stops the post from being published.
+type Filter interface {
+	Filter(content string) (string, error)
+}
+
+// FilterFunc is an adapter to allow the use of ordinary functions as Filters.
+type FilterFunc func(content string) (string, error)
+
+// Filter calls f(content).
+func (f FilterFunc) Filter(content string) (string, error) {
+	return f(content)
+}
+
+var (
+	emailReg = regexp.MustCompile(`[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}`)
+	// phoneReg matches phone numbers written with an international +
+	// prefix, a parenthesized area code, or as 3-3-4 digit groups.
+	phoneReg = regexp.MustCompile(`\+\d{1,3}[ .\-]?(?:\(\d{1,4}\)[ .\-]?)?\d{1,4}(?:[ .\-]\d{2,4}){1,4}|\(\d{2,4}\)[ .\-]?\d{3,4}[ .\-]\d{3,4}|\d{3}[ .\-]\d{3,4}[ .\-]\d{4}`)
+	imageReg = regexp.MustCompile(`(!\[[^\]]*\]\()([^)\s]+)`)
+	spaceReg = regexp.MustCompile(`[ \t]+\n`)
+	blankReg = regexp.MustCompile(`\n{3,}`)
+)
+
+// Built-in filters for use with SetFilters.
+var (
+	// RedactEmails replaces email addresses with "[email redacted]".
+	RedactEmails = FilterFunc(func(content string) (string, error) {
+		return emailReg.ReplaceAllString(content, "[email redacted]"), nil
+	})
+
+	// RedactPhoneNumbers replaces phone numbers with "[phone redacted]".
+	RedactPhoneNumbers = FilterFunc(func(content string) (string, error) {
+		return redactPhoneNumbers(content), nil
+	})
+
+	// StripImageMetadata removes query strings and fragments from linked
+	// Markdown images, which commonly carry tracking or device metadata.
+	StripImageMetadata = FilterFunc(func(content string) (string, error) {
+		return imageReg.ReplaceAllStringFunc(content, func(m string) string {
+			sub := imageReg.FindStringSubmatch(m)
+			u, err := url.Parse(sub[2])
+			if err != nil {
+				return m
+			}
+			u.RawQuery = ""
+			u.Fragment = ""
+			return sub[1] + u.String()
+		}), nil
+	})
+
+	// NormalizeWhitespace converts line endings to "\n", strips trailing
+	// spaces from lines, and collapses runs of blank lines.
+	NormalizeWhitespace = FilterFunc(func(content string) (string, error) {
+		content = strings.Replace(content, "\r\n", "\n", -1)
+		content = spaceReg.ReplaceAllString(content, "\n")
+		content = blankReg.ReplaceAllString(content, "\n\n")
+		return strings.TrimSpace(content), nil
+	})
+)
+
+// SetFilters sets the filters that run, in order, on PostParams.Content
+// before every post this Client creates or updates. Calling it with no
+// arguments removes all filters.
+func (c *Client) SetFilters(filters ...Filter) {
+	c.filters = filters
+}
+
+// filterParams runs the Client's filters on the given PostParams, returning a
+// filtered copy and leaving the original untouched.
+func (c *Client) filterParams(sp *PostParams) (*PostParams, error) {
+	if len(c.filters) == 0 {
+		return sp, nil
+	}
+	fp := *sp
+	var err error
+	for _, f := range c.filters {
+		perr := SafeCall("filter", func() {
+			fp.Content, err = f.Filter(fp.Content)
+		})
+		if perr != nil {
+			return nil, perr
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &fp, nil
+}
+
+// redactPhoneNumbers replaces the matches of phoneReg that stand on their own,
+// so parts of IP addresses, version numbers, and other dotted or longer
+// numbers are left alone.
+func redactPhoneNumbers(content string) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range phoneReg.FindAllStringIndex(content, -1) {
+		if !phoneBoundary(content, m[0], m[1]) {
+			continue
+		}
+		sb.WriteString(content[last:m[0]])
+		sb.WriteString("[phone redacted]")
+		last = m[1]
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+// phoneBoundary returns whether content[start:end] isn't part of a longer
+// word or number.
+func phoneBoundary(content string, start, end int) bool {
+	if start > 0 {
+		if b := content[start-1]; isPhoneWordByte(b) || b == '.' || b == '-' {
+			return false
+		}
+	}
+	if end < len(content) {
+		b := content[end]
+		if isPhoneWordByte(b) {
+			return false
+		}
+		if (b == '.' || b == '-') && end+1 < len(content) && content[end+1] >= '0' && content[end+1] <= '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isPhoneWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// prepareParams returns a copy of the given PostParams, ready to be sent to
+// the API: with the Client's defaults (for new posts), normalized language and
+// RTL setting, filters, and footer (including any license notice) applied.
+func (c *Client) prepareParams(sp *PostParams, creating bool) (*PostParams, error) {
+	if creating {
+		sp = c.applyDefaults(sp)
+	}
+	sp, err := normalizeLanguage(sp)
+	if err != nil {
+		return nil, err
+	}
+	sp, err = c.filterParams(applyRTL(sp))
+	if err != nil {
+		return nil, err
+	}
+	if footer := c.footerFor(sp); footer != "" && sp.Content != "" {
+		fp := *sp
+		fp.Content = AppendFooter(fp.Content, footer)
+		sp = &fp
+	}
+	return sp, nil
+}
@@ -0,0 +1,58 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestFilterParams(t *testing.T) {
+	c := NewClient()
+	c.SetFilters(RedactEmails, RedactPhoneNumbers, StripImageMetadata, NormalizeWhitespace)
+
+	sp := &PostParams{
+		Content: "Contact me at matt@write.as or +1 555-123-4567.  \r\n\r\n\r\n\r\n![Me](https://i.snap.as/abc.jpg?loc=40.7,-74.0)",
+	}
+	fp, err := c.filterParams(sp)
+	if err != nil {
+		t.Fatalf("Filter failed: %v", err)
+	}
+
+	expected := "Contact me at [email redacted] or [phone redacted].\n\n![Me](https://i.snap.as/abc.jpg)"
+	if fp.Content != expected {
+		t.Errorf("Unexpected filtered content: %q", fp.Content)
+	}
+	if sp.Content == fp.Content {
+		t.Errorf("Original params were modified")
+	}
+}
+
+func TestRedactPhoneNumbers(t *testing.T) {
+	redacted := []string{
+		"+1 555-123-4567",
+		"+44 20 7946 0958",
+		"+1 (555) 123-4567",
+		"(555) 123-4567",
+		"555-123-4567",
+		"555.123.4567",
+	}
+	for _, s := range redacted {
+		if got, _ := RedactPhoneNumbers("Call " + s + "."); got != "Call [phone redacted]." {
+			t.Errorf("Expected %q to be redacted, got %q", s, got)
+		}
+	}
+	kept := []string{
+		"192.168.100.200",
+		"10.123.456.7890",
+		"v1.22.333.4444",
+		"1.2.3.4",
+		"2024-01-15",
+		"ISBN 978-3-16-148410-0",
+		"order 1234567890123",
+		"12:30 on 2024-01-15",
+	}
+	for _, s := range kept {
+		if got, _ := RedactPhoneNumbers(s); got != s {
+			t.Errorf("Unexpected redaction of %q: %q", s, got)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"strings"
+)
+
+// footerMarker separates a post's content from a footer added by
+// AppendFooter. It's an HTML comment, so it's invisible once rendered.
+const footerMarker = "<!--writeas:footer-->"
+
+// AppendFooter appends the given footer to content, after an invisible marker.
+// Any footer previously added with AppendFooter is replaced, so it's safe to
+// call on content that's already been published with a footer.
+func AppendFooter(content, footer string) string {
+	content = strings.TrimRight(StripFooter(content), "\n")
+	if footer == "" {
+		return content
+	}
+	return content + "\n\n" + footerMarker + "\n" + footer
+}
+
+// StripFooter removes a footer added with AppendFooter from content.
+func StripFooter(content string) string {
+	if i := strings.LastIndex(content, footerMarker); i >= 0 {
+		return strings.TrimRight(content[:i], "\n")
+	}
+	return content
+}
+
+// WithFooter sets a footer, like a license notice or donation link, to append
+// to the content of every post the Client creates or updates. It replaces any
+// footer already added with AppendFooter, including a Profile's Footer.
+func WithFooter(footer string) Option {
+	return func(c *Client) {
+		c.footer = footer
+	}
+}
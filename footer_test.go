@@ -0,0 +1,33 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestAppendFooter(t *testing.T) {
+	content := AppendFooter("Hello.\n", "*CC BY 4.0*")
+	expected := "Hello.\n\n<!--writeas:footer-->\n*CC BY 4.0*"
+	if content != expected {
+		t.Errorf("Unexpected content: %q", content)
+	}
+
+	if res := AppendFooter(content, "*CC BY-SA 4.0*"); res != "Hello.\n\n<!--writeas:footer-->\n*CC BY-SA 4.0*" {
+		t.Errorf("Footer was duplicated: %q", res)
+	}
+	if res := StripFooter(content); res != "Hello." {
+		t.Errorf("Unexpected stripped content: %q", res)
+	}
+}
+
+func TestPrepareParamsFooter(t *testing.T) {
+	c := NewClient(WithFooter("Thanks for reading."))
+
+	sp, err := c.prepareParams(&PostParams{ID: "abc", Title: "New title"}, false)
+	if err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if sp.Content != "" {
+		t.Errorf("Footer added to update without content: %q", sp.Content)
+	}
+}
@@ -0,0 +1,124 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const frontMatterDelim = "---\n"
+
+// FrontMatter returns YAML front matter describing the given post, for
+// exporting it to static site generators and other Markdown tools. The
+// post's license is detected from its content.
+func FrontMatter(p *Post) string {
+	var b strings.Builder
+	b.WriteString(frontMatterDelim)
+	if p.Title != "" {
+		fmt.Fprintf(&b, "title: %s\n", strconv.Quote(p.Title))
+	}
+	if p.Slug != "" {
+		fmt.Fprintf(&b, "slug: %s\n", strconv.Quote(p.Slug))
+	}
+	fmt.Fprintf(&b, "id: %s\n", strconv.Quote(p.ID))
+	if !p.Created.IsZero() {
+		fmt.Fprintf(&b, "date: %s\n", p.Created.Format(time.RFC3339))
+	}
+	if !p.Updated.IsZero() {
+		fmt.Fprintf(&b, "updated: %s\n", p.Updated.Format(time.RFC3339))
+	}
+	if p.Language != nil {
+		fmt.Fprintf(&b, "lang: %s\n", strconv.Quote(*p.Language))
+	}
+	if p.RTL != nil {
+		fmt.Fprintf(&b, "rtl: %t\n", *p.RTL)
+	}
+	if len(p.Tags) > 0 {
+		b.WriteString("tags:\n")
+		for _, t := range p.Tags {
+			fmt.Fprintf(&b, "  - %s\n", strconv.Quote(t))
+		}
+	}
+	if l, ok := DetectLicense(p.Content); ok {
+		fmt.Fprintf(&b, "license: %s\n", strconv.Quote(l.ID))
+		fmt.Fprintf(&b, "license_url: %s\n", strconv.Quote(l.URL))
+	}
+	b.WriteString(frontMatterDelim)
+	return b.String()
+}
+
+// ParseFrontMatter reads a post exported as front matter by FrontMatter,
+// followed by its content. Every field FrontMatter writes is restored, with
+// times to the second; nil and empty Language and RTL are kept apart, since
+// FrontMatter only writes them when set.
+func ParseFrontMatter(doc string) (*Post, error) {
+	doc = strings.Replace(doc, "\r\n", "\n", -1)
+	if !strings.HasPrefix(doc, frontMatterDelim) {
+		return nil, fmt.Errorf("Missing front matter.")
+	}
+	end := strings.Index(doc[len(frontMatterDelim):], "\n"+frontMatterDelim)
+	if end < 0 {
+		return nil, fmt.Errorf("Unterminated front matter.")
+	}
+	header := doc[len(frontMatterDelim) : len(frontMatterDelim)+end+1]
+	p := &Post{Content: doc[len(frontMatterDelim)+end+1+len(frontMatterDelim):]}
+
+	for i, line := range strings.Split(strings.TrimSuffix(header, "\n"), "\n") {
+		if strings.HasPrefix(line, "  - ") {
+			t, err := frontMatterString(line[4:])
+			if err != nil {
+				return nil, fmt.Errorf("Front matter line %d: %v", i+1, err)
+			}
+			p.Tags = append(p.Tags, t)
+			continue
+		}
+		kv := strings.SplitN(line, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("Front matter line %d: expected \"key: value\".", i+1)
+		}
+		if err := setFrontMatterField(p, kv[0], strings.TrimSpace(kv[1])); err != nil {
+			return nil, fmt.Errorf("Front matter line %d: %v", i+1, err)
+		}
+	}
+	return p, nil
+}
+
+// setFrontMatterField sets the Post field for the given front matter key.
+// Unknown keys, and those derived from the content like "license", are
+// ignored.
+func setFrontMatterField(p *Post, key, val string) error {
+	var err error
+	switch key {
+	case "title":
+		p.Title, err = frontMatterString(val)
+	case "slug":
+		p.Slug, err = frontMatterString(val)
+	case "id":
+		p.ID, err = frontMatterString(val)
+	case "date":
+		p.Created, err = ParseTimestamp(val)
+	case "updated":
+		p.Updated, err = ParseTimestamp(val)
+	case "lang":
+		var lang string
+		if lang, err = frontMatterString(val); err == nil {
+			p.Language = &lang
+		}
+	case "rtl":
+		var rtl bool
+		if rtl, err = strconv.ParseBool(val); err == nil {
+			p.RTL = &rtl
+		}
+	}
+	return err
+}
+
+// frontMatterString reads a string value, which FrontMatter always quotes.
+func frontMatterString(val string) (string, error) {
+	if !strings.HasPrefix(val, `"`) {
+		return val, nil
+	}
+	return strconv.Unquote(val)
+}
@@ -0,0 +1,320 @@
+#author: Nguyễn Thái Sơn
+// Package gateway provides an http.Handler that exposes the client's post
+// operations over a small authenticated JSON API, so applications that
+// aren't written in Go can publish through one service holding everyone's
+// Write.as access tokens. The writeasd command serves it.
+//
+// Callers authenticate with a gateway key in the Authorization header, as
+// "Bearer <key>", which Keys maps to a user. Each user first stores their
+// Write.as access token with PUT /token, and then:
+//
+//	POST   /posts                    publishes a post
+//	GET    /posts/{id}               retrieves a post
+//	PUT    /posts/{id}               updates a post
+//	DELETE /posts/{id}               deletes a post, given its token in the
+//	                                 X-Post-Token header or a {"token"} body
+//	                                 if it's anonymous
+//	GET    /collections/{alias}/posts retrieves a collection's posts
+//
+// Responses are wrapped like the Write.as API's, in {"code", "data"} or
+// {"code", "error_msg"}. Errors from Write.as keep their status where it
+// means something to the caller, like 404 for a missing post or 403 when the
+// stored token is rejected, and are 502 Bad Gateway otherwise.
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/writeas/go-writeas"
+)
+
+// DefaultMaxBodySize is the largest request body accepted when
+// Handler.MaxBodySize isn't set.
+const DefaultMaxBodySize = 1 << 20
+
+// Handler serves the gateway API.
+type Handler struct {
+	// Keys maps each gateway key to the user it authenticates.
+	Keys map[string]string
+	// Tokens stores each user's Write.as access token.
+	Tokens TokenStore
+
+	// NewClient returns the Client requests are made with, before the
+	// user's token is set. It defaults to writeas.NewClient.
+	NewClient func() *writeas.Client
+	// MaxBodySize limits the size of accepted request bodies.
+	MaxBodySize int64
+
+	// OnError is called with any error returned to a caller. It may be nil.
+	OnError func(error)
+}
+
+type (
+	// postRequest is the body of POST /posts and PUT /posts/{id}: the
+	// PostParams, plus the collection that isn't part of their JSON.
+	postRequest struct {
+		writeas.PostParams
+		Collection string `json:"collection,omitempty"`
+	}
+
+	// httpError is an error with the status to respond with.
+	httpError struct {
+		code int
+		err  error
+	}
+)
+
+func (e *httpError) Error() string {
+	return e.err.Error()
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.authenticate(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="writeasd"`)
+		h.respond(w, http.StatusUnauthorized, nil, fmt.Errorf("Invalid or missing gateway key."))
+		return
+	}
+
+	code, data, err := h.route(user, r)
+	if err != nil {
+		var he *httpError
+		if errors.As(err, &he) {
+			code = he.code
+		} else {
+			code = upstreamStatus(err)
+		}
+	}
+	h.respond(w, code, data, err)
+}
+
+func (h *Handler) route(user string, r *http.Request) (int, interface{}, error) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "token":
+		return h.handleToken(user, r)
+	case len(parts) == 1 && parts[0] == "posts" && r.Method == http.MethodPost:
+		c, err := h.client(user)
+		if err != nil {
+			return 0, nil, err
+		}
+		sp, err := h.readPost(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		p, err := c.CreatePost(sp)
+		return http.StatusCreated, p, err
+	case len(parts) == 2 && parts[0] == "posts":
+		c, err := h.client(user)
+		if err != nil {
+			return 0, nil, err
+		}
+		return h.handlePost(c, parts[1], r)
+	case len(parts) == 3 && parts[0] == "collections" && parts[2] == "posts" && r.Method == http.MethodGet:
+		c, err := h.client(user)
+		if err != nil {
+			return 0, nil, err
+		}
+		posts, err := c.GetCollectionPosts(parts[1])
+		return http.StatusOK, posts, err
+	}
+	return 0, nil, &httpError{http.StatusNotFound, fmt.Errorf("No such endpoint: %s %s", r.Method, r.URL.Path)}
+}
+
+func (h *Handler) handleToken(user string, r *http.Request) (int, interface{}, error) {
+	switch r.Method {
+	case http.MethodPut:
+		body := struct {
+			Token string `json:"token"`
+		}{}
+		if err := h.readJSON(r, &body); err != nil {
+			return 0, nil, err
+		}
+		if body.Token == "" {
+			return 0, nil, &httpError{http.StatusBadRequest, fmt.Errorf("Missing token.")}
+		}
+		return http.StatusNoContent, nil, tokenStoreError(h.Tokens.SetToken(user, body.Token))
+	case http.MethodDelete:
+		return http.StatusNoContent, nil, tokenStoreError(h.Tokens.DeleteToken(user))
+	}
+	return 0, nil, &httpError{http.StatusMethodNotAllowed, fmt.Errorf("Method %s not allowed.", r.Method)}
+}
+
+func (h *Handler) handlePost(c *writeas.Client, id string, r *http.Request) (int, interface{}, error) {
+	switch r.Method {
+	case http.MethodGet:
+		p, err := c.GetPost(id)
+		return http.StatusOK, p, err
+	case http.MethodPut:
+		sp, err := h.readPost(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		sp.ID = id
+		p, err := c.UpdatePost(sp)
+		return http.StatusOK, p, err
+	case http.MethodDelete:
+		token, err := h.readPostToken(r)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusNoContent, nil, c.DeletePost(&writeas.PostParams{ID: id, Token: token})
+	}
+	return 0, nil, &httpError{http.StatusMethodNotAllowed, fmt.Errorf("Method %s not allowed.", r.Method)}
+}
+
+// authenticate returns the user the request's gateway key belongs to,
+// comparing it against every key in constant time.
+func (h *Handler) authenticate(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return "", false
+	}
+	key := []byte(strings.TrimPrefix(auth, "Bearer "))
+	user := ""
+	for k, u := range h.Keys {
+		if subtle.ConstantTimeCompare(key, []byte(k)) == 1 {
+			user = u
+		}
+	}
+	return user, user != ""
+}
+
+// client returns a Client authenticated as the user.
+func (h *Handler) client(user string) (*writeas.Client, error) {
+	token, err := h.Tokens.Token(user)
+	if err != nil {
+		return nil, tokenStoreError(err)
+	}
+	if token == "" {
+		return nil, &httpError{http.StatusForbidden, fmt.Errorf("No Write.as token stored for %s; store one with PUT /token.", user)}
+	}
+	newClient := h.NewClient
+	if newClient == nil {
+		newClient = func() *writeas.Client { return writeas.NewClient() }
+	}
+	c := newClient()
+	c.SetToken(token)
+	return c, nil
+}
+
+// tokenStoreError returns an error from the TokenStore as a 500, so it isn't
+// mistaken for an error from Write.as.
+func tokenStoreError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &httpError{http.StatusInternalServerError, fmt.Errorf("Token store: %v", err)}
+}
+
+func (h *Handler) readPost(r *http.Request) (*writeas.PostParams, error) {
+	req := &postRequest{}
+	if err := h.readJSON(r, req); err != nil {
+		return nil, err
+	}
+	sp := req.PostParams
+	sp.Collection = req.Collection
+	return &sp, nil
+}
+
+// readPostToken returns the post token a DELETE request was given in its
+// X-Post-Token header or body, if any. Tokens in the query string are
+// refused, since URLs end up in logs.
+func (h *Handler) readPostToken(r *http.Request) (string, error) {
+	if r.URL.Query().Get("token") != "" {
+		return "", &httpError{http.StatusBadRequest, fmt.Errorf("Send the post token in the X-Post-Token header or the body, not the URL.")}
+	}
+	if token := r.Header.Get("X-Post-Token"); token != "" {
+		return token, nil
+	}
+	if r.ContentLength == 0 {
+		return "", nil
+	}
+	body := struct {
+		Token string `json:"token"`
+	}{}
+	if err := h.readJSON(r, &body); err != nil {
+		return "", err
+	}
+	return body.Token, nil
+}
+
+func (h *Handler) readJSON(r *http.Request, v interface{}) error {
+	max := h.MaxBodySize
+	if max <= 0 {
+		max = DefaultMaxBodySize
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, max))
+	if err != nil {
+		return &httpError{http.StatusRequestEntityTooLarge, fmt.Errorf("Read body: %v", err)}
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		return &httpError{http.StatusBadRequest, fmt.Errorf("Invalid JSON: %v", err)}
+	}
+	return nil
+}
+
+// upstreamStatus returns the status to respond with for an error from a
+// Client call. The Client's errors aren't typed, so its messages for the API's
+// 4xx responses are recognized, along with the checks it makes itself.
+func upstreamStatus(err error) int {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, writeas.ErrNotSupported):
+		return http.StatusNotImplemented
+	case errors.Is(err, writeas.ErrInvalidToken), msg == "Not authenticated.":
+		// The caller is authenticated, but their stored token isn't
+		return http.StatusForbidden
+	case strings.HasSuffix(msg, " not found."):
+		return http.StatusNotFound
+	case msg == "Post unpublished.":
+		return http.StatusGone
+	case strings.HasPrefix(msg, "Bad request: "), strings.HasPrefix(msg, "Invalid "):
+		return http.StatusBadRequest
+	}
+	if m := problemStatusReg.FindStringSubmatch(msg); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		if code == http.StatusUnauthorized {
+			return http.StatusForbidden
+		}
+		return code
+	}
+	return http.StatusBadGateway
+}
+
+// problemStatusReg matches the Client's "Problem ...: <status>." errors for
+// other 4xx responses. A 401 is the stored token being rejected, so it's 403
+// like "Not authenticated.".
+var problemStatusReg = regexp.MustCompile(`^Problem [^:]*: (4\d\d)\.`)
+
+func (h *Handler) respond(w http.ResponseWriter, code int, data interface{}, err error) {
+	if code == http.StatusNoContent && err == nil {
+		w.WriteHeader(code)
+		return
+	}
+	res := struct {
+		Code         int         `json:"code"`
+		Data         interface{} `json:"data,omitempty"`
+		ErrorMessage string      `json:"error_msg,omitempty"`
+	}{Code: code, Data: data}
+	if err != nil {
+		res.Data = nil
+		res.ErrorMessage = err.Error()
+		if code == http.StatusInternalServerError {
+			// Details of the server, like file paths, are only for OnError
+			res.ErrorMessage = "Internal server error."
+		}
+		writeas.ReportError(h.OnError, err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,152 @@
+#author: Nguyễn Thái Sơn
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestHandler(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token writeas-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"code":401,"error_msg":"Not authenticated."}`)
+			return
+		}
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/collections/blog/posts":
+			sp := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&sp)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"code":201,"data":{"id":"p1","slug":"hi","body":%q}}`, sp["body"])
+		case r.Method == "GET" && r.URL.Path == "/api/posts/p1":
+			fmt.Fprint(w, `{"code":200,"data":{"id":"p1","body":"Hi"}}`)
+		case r.URL.Path == "/api/posts/gone":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":404,"error_msg":"Post not found."}`)
+		case r.Method == "DELETE" && r.URL.Path == "/api/posts/anon":
+			if r.URL.Query().Get("token") != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"code":403}`)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer api.Close()
+
+	h := &Handler{
+		Keys:   map[string]string{"key-1": "alice"},
+		Tokens: &FileTokenStore{Path: filepath.Join(t.TempDir(), "tokens.json")},
+		NewClient: func() *writeas.Client {
+			c, _ := writeas.NewClientForInstance(api.URL, writeas.WithInsecureHTTP())
+			return c
+		},
+	}
+	var postToken string
+	do := func(method, path, key, body string) (int, map[string]interface{}) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		if key != "" {
+			r.Header.Set("Authorization", "Bearer "+key)
+		}
+		if postToken != "" {
+			r.Header.Set("X-Post-Token", postToken)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		res := map[string]interface{}{}
+		json.NewDecoder(w.Body).Decode(&res)
+		return w.Code, res
+	}
+
+	if code, _ := do("GET", "/posts/p1", "", ""); code != http.StatusUnauthorized {
+		t.Errorf("Unexpected status without a key: %d", code)
+	}
+	if code, _ := do("GET", "/posts/p1", "wrong", ""); code != http.StatusUnauthorized {
+		t.Errorf("Unexpected status with a wrong key: %d", code)
+	}
+	if code, res := do("GET", "/posts/p1", "key-1", ""); code != http.StatusForbidden {
+		t.Errorf("Unexpected response without a stored token: %d %v", code, res)
+	}
+	if code, _ := do("PUT", "/token", "key-1", `{"token":"writeas-token"}`); code != http.StatusNoContent {
+		t.Fatalf("Unexpected status storing token: %d", code)
+	}
+
+	code, res := do("POST", "/posts", "key-1", `{"body":"Hello","collection":"blog"}`)
+	if code != http.StatusCreated {
+		t.Fatalf("Unexpected status publishing: %d %v", code, res)
+	}
+	if data, _ := res["data"].(map[string]interface{}); data["id"] != "p1" || data["body"] != "Hello" {
+		t.Errorf("Unexpected published post: %v", res)
+	}
+	if code, res = do("GET", "/posts/p1", "key-1", ""); code != http.StatusOK {
+		t.Errorf("Unexpected response getting post: %d %v", code, res)
+	}
+	if code, res = do("GET", "/nope", "key-1", ""); code != http.StatusNotFound || res["error_msg"] == nil {
+		t.Errorf("Unexpected response for unknown endpoint: %d %v", code, res)
+	}
+	if code, res = do("GET", "/posts/gone", "key-1", ""); code != http.StatusNotFound {
+		t.Errorf("Unexpected response for a missing post: %d %v", code, res)
+	}
+	if code, res = do("GET", "/posts/NOPE", "key-1", ""); code != http.StatusBadRequest {
+		t.Errorf("Unexpected response for an invalid post ID: %d %v", code, res)
+	}
+
+	if code, res = do("DELETE", "/posts/anon?token=secret", "key-1", ""); code != http.StatusBadRequest {
+		t.Errorf("Unexpected response for a token in the URL: %d %v", code, res)
+	}
+	if code, res = do("DELETE", "/posts/anon", "key-1", `{"token":"wrong"}`); code != http.StatusForbidden {
+		t.Errorf("Unexpected response for a wrong post token: %d %v", code, res)
+	}
+	if code, res = do("DELETE", "/posts/anon", "key-1", `{"token":"secret"}`); code != http.StatusNoContent {
+		t.Errorf("Unexpected response deleting with a token in the body: %d %v", code, res)
+	}
+	postToken = "secret"
+	if code, res = do("DELETE", "/posts/anon", "key-1", ""); code != http.StatusNoContent {
+		t.Errorf("Unexpected response deleting with a token header: %d %v", code, res)
+	}
+	postToken = ""
+
+	do("PUT", "/token", "key-1", `{"token":"revoked"}`)
+	if code, res = do("GET", "/posts/p1", "key-1", ""); code != http.StatusForbidden {
+		t.Errorf("Unexpected response for a rejected token: %d %v", code, res)
+	}
+
+	do("DELETE", "/token", "key-1", "")
+	if code, _ = do("GET", "/posts/p1", "key-1", ""); code != http.StatusForbidden {
+		t.Errorf("Unexpected status after deleting token: %d", code)
+	}
+}
+
+func TestHandlerTokenStoreError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	if err := ioutil.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var reported error
+	h := &Handler{
+		Keys:    map[string]string{"key-1": "alice"},
+		Tokens:  &FileTokenStore{Path: path},
+		OnError: func(err error) { reported = err },
+	}
+	r := httptest.NewRequest("GET", "/posts/p1", nil)
+	r.Header.Set("Authorization", "Bearer key-1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), path) {
+		t.Errorf("Unexpected response for a corrupt token file: %d %s", w.Code, w.Body)
+	}
+	if reported == nil || !strings.Contains(reported.Error(), path) {
+		t.Errorf("Expected the error to be reported, got: %v", reported)
+	}
+}
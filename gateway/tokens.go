@@ -0,0 +1,126 @@
+#author: Nguyễn Thái Sơn
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type (
+	// TokenStore stores each gateway user's Write.as access token.
+	TokenStore interface {
+		// Token returns the user's token, or "" if they haven't stored one.
+		Token(user string) (string, error)
+		SetToken(user, token string) error
+		DeleteToken(user string) error
+	}
+
+	// MemoryTokenStore is a TokenStore that keeps tokens in memory.
+	MemoryTokenStore struct {
+		mu     sync.Mutex
+		tokens map[string]string
+	}
+
+	// FileTokenStore is a TokenStore that keeps tokens in a JSON file that
+	// only its owner can read.
+	FileTokenStore struct {
+		Path string
+
+		mu sync.Mutex
+	}
+)
+
+// Token implements the TokenStore interface.
+func (s *MemoryTokenStore) Token(user string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[user], nil
+}
+
+// SetToken implements the TokenStore interface.
+func (s *MemoryTokenStore) SetToken(user, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens == nil {
+		s.tokens = map[string]string{}
+	}
+	s.tokens[user] = token
+	return nil
+}
+
+// DeleteToken implements the TokenStore interface.
+func (s *MemoryTokenStore) DeleteToken(user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, user)
+	return nil
+}
+
+// Token implements the TokenStore interface.
+func (s *FileTokenStore) Token(user string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	return tokens[user], nil
+}
+
+// SetToken implements the TokenStore interface.
+func (s *FileTokenStore) SetToken(user, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	tokens[user] = token
+	return s.save(tokens)
+}
+
+// DeleteToken implements the TokenStore interface.
+func (s *FileTokenStore) DeleteToken(user string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.load()
+	if err != nil {
+		return err
+	}
+	delete(tokens, user)
+	return s.save(tokens)
+}
+
+func (s *FileTokenStore) load() (map[string]string, error) {
+	tokens := map[string]string{}
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("Invalid token file %s: %v", s.Path, err)
+	}
+	return tokens, nil
+}
+
+// save replaces the token file atomically, so a crash while saving leaves
+// the previous tokens.
+func (s *FileTokenStore) save(tokens map[string]string) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(s.Path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(s.Path+".tmp", s.Path)
+}
@@ -0,0 +1,185 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+)
+
+const githubAPIURL = "https://api.github.com"
+
+type (
+	// Gist is a GitHub Gist.
+	Gist struct {
+		ID          string              `json:"id,omitempty"`
+		Description string              `json:"description"`
+		Public      bool                `json:"public"`
+		HTMLURL     string              `json:"html_url,omitempty"`
+		Files       map[string]GistFile `json:"files"`
+	}
+
+	// GistFile is a single file in a Gist.
+	GistFile struct {
+		Filename string `json:"filename,omitempty"`
+		Language string `json:"language,omitempty"`
+		Content  string `json:"content"`
+	}
+
+	// GistClient makes requests to the GitHub Gist API.
+	GistClient struct {
+		// Token is a GitHub personal access token, required for creating
+		// gists.
+		Token string
+
+		client  *http.Client
+		baseURL string
+	}
+)
+
+// NewGistClient creates a new GitHub Gist API client. The token is only needed
+// for exporting posts as gists.
+func NewGistClient(token string) *GistClient {
+	return &GistClient{
+		Token:   token,
+		client:  &http.Client{Timeout: defaultHTTPTimeout},
+		baseURL: githubAPIURL,
+	}
+}
+
+// GetGist retrieves a gist.
+func (gc *GistClient) GetGist(id string) (*Gist, error) {
+	g := &Gist{}
+	if err := gc.request("GET", "/gists/"+id, nil, g, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// CreateGist creates a new gist, returning it as created.
+func (gc *GistClient) CreateGist(g *Gist) (*Gist, error) {
+	res := &Gist{}
+	if err := gc.request("POST", "/gists", g, res, http.StatusCreated); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (gc *GistClient) request(method, path string, data, result interface{}, expected int) error {
+	b := new(bytes.Buffer)
+	if data != nil {
+		json.NewEncoder(b).Encode(data)
+	}
+	r, err := http.NewRequest(method, gc.baseURL+path, b)
+	if err != nil {
+		return fmt.Errorf("Create request: %v", err)
+	}
+	r.Header.Set("Accept", "application/vnd.github+json")
+	r.Header.Set("Content-Type", "application/json")
+	if gc.Token != "" {
+		r.Header.Set("Authorization", "Bearer "+gc.Token)
+	}
+
+	resp, err := gc.client.Do(r)
+	if err != nil {
+		return fmt.Errorf("Request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		var gerr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&gerr)
+		if resp.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("Gist not found.")
+		}
+		return fmt.Errorf("Problem with GitHub request: %d. %s", resp.StatusCode, gerr.Message)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// GistToPostParams converts a gist into a post. Markdown files are included
+// as-is, and all other files as fenced code blocks, in filename order.
+func GistToPostParams(g *Gist) *PostParams {
+	names := make([]string, 0, len(g.Files))
+	for name := range g.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		f := g.Files[name]
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		if isMarkdownFile(name) {
+			b.WriteString(strings.TrimSpace(f.Content))
+			continue
+		}
+		fence := "```"
+		for strings.Contains(f.Content, fence) {
+			fence += "`"
+		}
+		fmt.Fprintf(&b, "**%s**\n\n%s%s\n%s\n%s", name, fence, strings.ToLower(f.Language), strings.TrimRight(f.Content, "\n"), fence)
+	}
+
+	return &PostParams{
+		Title:   g.Description,
+		Content: b.String(),
+	}
+}
+
+// PostToGist converts a post into a gist with a single Markdown file.
+func PostToGist(p *Post) *Gist {
+	name := p.Slug
+	if name == "" {
+		name = p.ID
+	}
+	content := p.Content
+	if p.Title != "" {
+		content = "# " + p.Title + "\n\n" + content
+	}
+	return &Gist{
+		Description: postDisplayTitle(p),
+		Files: map[string]GistFile{
+			name + ".md": {Content: content},
+		},
+	}
+}
+
+// ImportGist publishes the given gist as a post, in the given collection or
+// anonymously if collection is empty.
+func (c *Client) ImportGist(gc *GistClient, gistID, collection string) (*Post, error) {
+	g, err := gc.GetGist(gistID)
+	if err != nil {
+		return nil, err
+	}
+	sp := GistToPostParams(g)
+	sp.Collection = collection
+	return c.CreatePost(sp)
+}
+
+// ExportGist creates a gist from the given post.
+func (c *Client) ExportGist(gc *GistClient, postID string, public bool) (*Gist, error) {
+	p, err := c.GetPost(postID)
+	if err != nil {
+		return nil, err
+	}
+	g := PostToGist(p)
+	g.Public = public
+	return gc.CreateGist(g)
+}
+
+func isMarkdownFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown", ".mdown", ".txt":
+		return true
+	}
+	return false
+}
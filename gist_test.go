@@ -0,0 +1,26 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestGistToPostParams(t *testing.T) {
+	sp := GistToPostParams(&Gist{
+		Description: "Hello, gist",
+		Files: map[string]GistFile{
+			"main.go":   {Language: "Go", Content: "package main\n"},
+			"README.md": {Content: "Some *notes*.\n"},
+		},
+	})
+
+	expected := "Some *notes*.\n\n**main.go**\n\n```go\npackage main\n```"
+	if sp.Title != "Hello, gist" || sp.Content != expected {
+		t.Errorf("Unexpected params: %+v", sp)
+	}
+
+	g := PostToGist(&Post{Slug: "hello", Title: "Hello", Content: "Hi."})
+	if f, ok := g.Files["hello.md"]; !ok || f.Content != "# Hello\n\nHi." {
+		t.Errorf("Unexpected gist: %+v", g)
+	}
+}
@@ -0,0 +1,58 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ContentHashVersion is the version of the algorithm ContentHash uses. It's
+// the prefix of every hash, and changes whenever the algorithm does.
+const ContentHashVersion = "v1"
+
+// ContentHash returns a hash of what the author wrote in the post: its title,
+// its content once normalized with Normalize, its font, language, and
+// direction. Things the API changes, like its views, dates, and slug, aren't
+// included, so two copies of a post have the same hash exactly when they
+// don't differ in a way the author would care about.
+//
+// Hashes look like "v1:" followed by the hex SHA-256 digest. A stored hash
+// with a different version than ContentHashVersion was made by another
+// algorithm, and should be recomputed rather than taken as a change.
+func ContentHash(p *Post) string {
+	lang := ""
+	if p.Language != nil {
+		lang = *p.Language
+	}
+	rtl := ""
+	if p.RTL != nil {
+		rtl = strconv.FormatBool(*p.RTL)
+	}
+
+	return hashFields([][2]string{
+		{"title", strings.TrimSpace(p.Title)},
+		{"body", Normalize(p.Content)},
+		{"font", p.Font},
+		{"lang", lang},
+		{"rtl", rtl},
+	})
+}
+
+// hashFields returns a versioned hash of the named fields. Each field is
+// length-prefixed, so moving text from one field to the next changes it.
+func hashFields(fields [][2]string) string {
+	h := sha256.New()
+	for _, f := range fields {
+		fmt.Fprintf(h, "%s %d\n%s\n", f[0], len(f[1]), f[1])
+	}
+	return ContentHashVersion + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// ContentHashCurrent reports whether the hash was made by the current
+// version of ContentHash, so it can be compared to new ones.
+func ContentHashCurrent(hash string) bool {
+	return strings.HasPrefix(hash, ContentHashVersion+":")
+}
@@ -0,0 +1,45 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+	"time"
+)
+
+func TestContentHash(t *testing.T) {
+	lang := "en"
+	p := &Post{ID: "abc", Title: "Hello", Content: "# Hi\n\nThere.", Language: &lang}
+	h := ContentHash(p)
+	if !ContentHashCurrent(h) || len(h) != len("v1:")+64 {
+		t.Errorf("Unexpected hash %s", h)
+	}
+
+	same := *p
+	same.Content = "# Hi \r\n\r\n\r\nThere.\n\n"
+	same.Views = 10
+	same.Updated = time.Now()
+	same.Slug = "hello-1"
+	if ContentHash(&same) != h {
+		t.Errorf("Expected API-only and whitespace changes to keep the hash")
+	}
+
+	for name, change := range map[string]func(*Post){
+		"title":    func(p *Post) { p.Title = "Hello!" },
+		"content":  func(p *Post) { p.Content = "# Hi\n\nThere!" },
+		"font":     func(p *Post) { p.Font = "mono" },
+		"language": func(p *Post) { p.Language = nil },
+		"rtl":      func(p *Post) { rtl := false; p.RTL = &rtl },
+		// Moving text between fields must change the hash too
+		"boundary": func(p *Post) { p.Title, p.Content = "Hello# Hi", "There." },
+	} {
+		changed := *p
+		change(&changed)
+		if ContentHash(&changed) == h {
+			t.Errorf("Expected a %s change to change the hash", name)
+		}
+	}
+
+	if ContentHashCurrent("v0:abc") {
+		t.Errorf("Expected another version not to be current")
+	}
+}
@@ -0,0 +1,60 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"sort"
+	"strings"
+)
+
+// CheckCollectionURLs verifies that the given collection, and every post in
+// it, is reachable at its expected URLs: on the instance, and on the
+// collection's custom domain if it has one. It returns the URLs that aren't,
+// ordered by URL, for catching drift after migrations and alias changes; use
+// LinkStatus.Missing to tell posts that are gone from other failures.
+func (c *Client) CheckCollectionURLs(alias string) ([]LinkStatus, error) {
+	coll, err := c.GetCollection(alias)
+	if err != nil {
+		return nil, err
+	}
+	posts, err := c.allCollectionPosts(alias)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []LinkStatus
+	for _, base := range c.collectionBaseURLs(coll) {
+		links = append(links, LinkStatus{URL: base})
+		for _, p := range posts {
+			if p.Slug != "" {
+				links = append(links, LinkStatus{URL: base + p.Slug, PostID: p.ID})
+			}
+		}
+	}
+
+	dead := c.checkURLs(links)
+	sort.Slice(dead, func(i, j int) bool { return dead[i].URL < dead[j].URL })
+	return dead, nil
+}
+
+// collectionBaseURLs returns the distinct URLs the collection is expected to
+// be served from, each ending in a slash.
+func (c *Client) collectionBaseURLs(coll *Collection) []string {
+	candidates := []string{c.instanceURL() + "/" + coll.Alias + "/", coll.URL}
+	if coll.Domain != "" {
+		candidates = append(candidates, "https://"+coll.Domain+"/")
+	}
+
+	var bases []string
+	seen := map[string]bool{}
+	for _, u := range candidates {
+		if u == "" {
+			continue
+		}
+		u = strings.TrimRight(u, "/") + "/"
+		if !seen[u] {
+			seen[u] = true
+			bases = append(bases, u)
+		}
+	}
+	return bases
+}
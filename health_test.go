@@ -0,0 +1,47 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckCollectionURLs(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blog/", "/blog/here":
+			w.WriteHeader(http.StatusOK)
+		case "/blog/deleted":
+			w.WriteHeader(http.StatusGone)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer site.Close()
+
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog", URL: site.URL + "/blog/"}
+	api.addPost("blog", Post{ID: "p1", Slug: "here"})
+	api.addPost("blog", Post{ID: "p2", Slug: "deleted"})
+	api.addPost("blog", Post{ID: "p3", Slug: "moved"})
+	c := api.client()
+	c.instance = site.URL
+
+	dead, err := c.CheckCollectionURLs("blog")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(dead) != 2 || dead[0].PostID != "p2" || dead[1].PostID != "p3" || !dead[0].Missing() || !dead[1].Missing() {
+		t.Errorf("Unexpected dead URLs: %+v", dead)
+	}
+}
+
+func TestCollectionBaseURLs(t *testing.T) {
+	c := NewClient()
+	bases := c.collectionBaseURLs(&Collection{Alias: "blog", URL: "https://blog.example.com", Domain: "blog.example.com"})
+	if len(bases) != 2 || bases[0] != "https://write.as/blog/" || bases[1] != "https://blog.example.com/" {
+		t.Errorf("Unexpected base URLs: %v", bases)
+	}
+}
@@ -0,0 +1,46 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+)
+
+// PanicError is returned in place of a panic in a user-provided callback, like
+// a Filter or an OnPublish hook, so a buggy callback can't crash the program
+// calling it.
+type PanicError struct {
+	// Hook names the callback that panicked.
+	Hook  string
+	Value interface{}
+	// Stack is the stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("Panic in %s: %v", e.Hook, e.Value)
+}
+
+// SafeCall calls f, recovering from any panic and returning it as a
+// *PanicError naming the given hook.
+func SafeCall(hook string, f func()) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Hook: hook, Value: v, Stack: debug.Stack()}
+		}
+	}()
+	f()
+	return nil
+}
+
+// ReportError calls onError with err, if both are set. A panic in onError
+// itself has nowhere else to go, so it's logged.
+func ReportError(onError func(error), err error) {
+	if onError == nil || err == nil {
+		return
+	}
+	if perr := SafeCall("OnError", func() { onError(err) }); perr != nil {
+		log.Printf("writeas: %v", perr)
+	}
+}
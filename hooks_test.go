@@ -0,0 +1,63 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSafeCall(t *testing.T) {
+	if err := SafeCall("ok", func() {}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	err := SafeCall("filter", func() { panic("boom") })
+	perr, ok := err.(*PanicError)
+	if !ok {
+		t.Fatalf("Expected a *PanicError, got %v", err)
+	}
+	if perr.Hook != "filter" || perr.Value != "boom" || len(perr.Stack) == 0 {
+		t.Errorf("Unexpected PanicError: %+v", perr)
+	}
+	if perr.Error() != "Panic in filter: boom" {
+		t.Errorf("Unexpected message: %s", perr.Error())
+	}
+}
+
+func TestFilterPanic(t *testing.T) {
+	c := NewClient()
+	c.SetFilters(FilterFunc(func(content string) (string, error) {
+		var m map[string]string
+		m["crash"] = content
+		return content, nil
+	}))
+
+	_, err := c.filterParams(&PostParams{Content: "Hello"})
+	if _, ok := err.(*PanicError); !ok {
+		t.Errorf("Expected a *PanicError, got %v", err)
+	}
+}
+
+func TestLintPanic(t *testing.T) {
+	c := NewClient()
+	c.SetLinters(LinterFunc(func(content string) []LintWarning {
+		panic("boom")
+	}), BasicLinter)
+
+	warnings := c.Lint(&PostParams{Content: "[broken]()"})
+	if len(warnings) != 2 || warnings[0].Rule != "panic" || warnings[1].Rule != "broken-link" {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+}
+
+func TestReportError(t *testing.T) {
+	var got error
+	ReportError(func(err error) { got = err }, errors.New("failed"))
+	if got == nil || got.Error() != "failed" {
+		t.Errorf("Unexpected reported error: %v", got)
+	}
+
+	// Neither a nil callback nor a panicking one should crash.
+	ReportError(nil, errors.New("failed"))
+	ReportError(func(error) { panic("boom") }, errors.New("failed"))
+}
@@ -0,0 +1,49 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxPostIDLength is the longest post ID ParsePostID accepts.
+const MaxPostIDLength = 32
+
+// ParsePostID checks that s is a well-formed post ID, returning it without
+// surrounding whitespace. Calls that take a post ID check it with
+// ParsePostID first, so bad input fails without a request.
+func ParsePostID(s string) (string, error) {
+	id := strings.TrimSpace(s)
+	if id == "" {
+		return "", fmt.Errorf("Invalid post ID: it's empty.")
+	}
+	if len(id) > MaxPostIDLength {
+		return "", fmt.Errorf("Invalid post ID %q: it's %d characters, but the most allowed is %d.", id, len(id), MaxPostIDLength)
+	}
+	for i, r := range []rune(id) {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return "", fmt.Errorf("Invalid post ID %q: %q at position %d isn't allowed; only lowercase letters and numbers are.", id, r, i+1)
+		}
+	}
+	return id, nil
+}
+
+// ParseCollectionAlias checks that s is a well-formed collection alias,
+// returning it lowercased and without surrounding whitespace, since aliases
+// are case-insensitive. Calls that take an alias check it with
+// ParseCollectionAlias first, so bad input fails without a request.
+func ParseCollectionAlias(s string) (string, error) {
+	alias := strings.ToLower(strings.TrimSpace(s))
+	if alias == "" {
+		return "", fmt.Errorf("Invalid collection alias: it's empty.")
+	}
+	if len(alias) > MaxAliasLength {
+		return "", fmt.Errorf("Invalid collection alias %q: it's %d characters, but the most allowed is %d.", alias, len(alias), MaxAliasLength)
+	}
+	for i, r := range []rune(alias) {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return "", fmt.Errorf("Invalid collection alias %q: %q at position %d isn't allowed; only letters, numbers, hyphens, and underscores are.", alias, r, i+1)
+		}
+	}
+	return alias, nil
+}
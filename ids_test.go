@@ -0,0 +1,80 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePostID(t *testing.T) {
+	if id, err := ParsePostID(" rf3t35fkax0aw\n"); err != nil || id != "rf3t35fkax0aw" {
+		t.Errorf("Unexpected result: %q, %v", id, err)
+	}
+
+	tests := map[string]string{
+		"":                      "it's empty.",
+		strings.Repeat("a", 33): "it's 33 characters, but the most allowed is 32.",
+		"rf3t35fkax0aW":         `'W' at position 13 isn't allowed`,
+		"ab/c":                  `'/' at position 3 isn't allowed`,
+	}
+	for in, msg := range tests {
+		if _, err := ParsePostID(in); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("Unexpected error for %q: %v", in, err)
+		}
+	}
+}
+
+func TestParseCollectionAlias(t *testing.T) {
+	if alias, err := ParseCollectionAlias(" My-Blog_2 "); err != nil || alias != "my-blog_2" {
+		t.Errorf("Unexpected result: %q, %v", alias, err)
+	}
+
+	tests := map[string]string{
+		" ":                      "it's empty.",
+		strings.Repeat("a", 101): "it's 101 characters, but the most allowed is 100.",
+		"my blog":                `' ' at position 3 isn't allowed`,
+		"blög":                   `'ö' at position 3 isn't allowed`,
+	}
+	for in, msg := range tests {
+		if _, err := ParseCollectionAlias(in); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("Unexpected error for %q: %v", in, err)
+		}
+	}
+}
+
+func TestInvalidIDFailsLocally(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	c := api.client()
+
+	if _, err := c.GetPost("../me"); err == nil {
+		t.Errorf("Expected an error for an invalid post ID")
+	}
+	if _, err := c.GetCollection("my blog"); err == nil {
+		t.Errorf("Expected an error for an invalid alias")
+	}
+	if len(api.requests) != 0 {
+		t.Errorf("Unexpected requests: %v", api.requests)
+	}
+}
+
+func TestIDsNormalized(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	p := api.addPost("blog", Post{Slug: "hello"})
+	c := api.client()
+
+	if _, err := c.GetCollection(" Blog "); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := c.GetPost(" " + p.ID + "\n"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := c.PinPost("BLOG", &PinnedPostParams{ID: " " + p.ID}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(api.pinned["blog"]) != 1 || api.pinned["blog"][0] != p.ID {
+		t.Errorf("Unexpected pins: %v", api.pinned)
+	}
+}
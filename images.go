@@ -0,0 +1,129 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ImageRef is an image referenced in post content.
+type ImageRef struct {
+	URL string
+	// Problem describes why the image is likely to break once published, or
+	// is empty if it looks fine.
+	Problem string
+}
+
+var (
+	mdImageReg   = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?`)
+	htmlImageReg = regexp.MustCompile(`(?i)<img[^>]+src=["']([^"']+)["']`)
+)
+
+// FindImages returns the images referenced in the given content, with any
+// problems that'd stop readers from seeing them: relative or file:// paths,
+// and hosts like localhost or private network addresses.
+func FindImages(content string) []ImageRef {
+	var refs []ImageRef
+	seen := map[string]bool{}
+	for _, reg := range []*regexp.Regexp{mdImageReg, htmlImageReg} {
+		for _, m := range reg.FindAllStringSubmatch(content, -1) {
+			if seen[m[1]] {
+				continue
+			}
+			seen[m[1]] = true
+			refs = append(refs, ImageRef{URL: m[1], Problem: imageProblem(m[1])})
+		}
+	}
+	return refs
+}
+
+func imageProblem(u string) string {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return "Malformed URL."
+	}
+	switch pu.Scheme {
+	case "http", "https":
+	case "file":
+		return "Local file."
+	case "data":
+		return ""
+	case "":
+		return "Relative path."
+	default:
+		return fmt.Sprintf("Unsupported scheme %q.", pu.Scheme)
+	}
+
+	host := strings.ToLower(pu.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".local") || strings.HasSuffix(host, ".internal") {
+		return "Local host."
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+		return "Private network address."
+	}
+	return ""
+}
+
+func isPrivateIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsUnspecified() {
+		return true
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4[0] == 10 ||
+			(ip4[0] == 172 && ip4[1]&0xf0 == 16) ||
+			(ip4[0] == 192 && ip4[1] == 168)
+	}
+	return len(ip) == net.IPv6len && ip[0]&0xfe == 0xfc
+}
+
+// RehostImages downloads each problematic image that's reachable from this
+// machine (e.g. on localhost or a private network) and uploads it with the
+// given ImageUploader, returning the content with references to the new URLs.
+// Images that can't be fetched, such as relative paths, are left as-is and
+// returned with their problems.
+func (c *Client) RehostImages(content string, up ImageUploader) (string, []ImageRef, error) {
+	var unresolved []ImageRef
+	for _, img := range FindImages(content) {
+		if img.Problem == "" {
+			continue
+		}
+		pu, err := url.Parse(img.URL)
+		if err != nil || (pu.Scheme != "http" && pu.Scheme != "https") {
+			unresolved = append(unresolved, img)
+			continue
+		}
+
+		newURL, err := c.rehostImage(img.URL, up)
+		if err != nil {
+			return content, nil, fmt.Errorf("Rehost %s: %v", img.URL, err)
+		}
+		content = strings.Replace(content, img.URL, newURL, -1)
+	}
+	return content, unresolved, nil
+}
+
+func (c *Client) rehostImage(u string, up ImageUploader) (string, error) {
+	// Fetch directly rather than through c.client, which may be going through
+	// a proxy that can't see local hosts.
+	hc := &http.Client{Timeout: defaultHTTPTimeout}
+	resp, err := hc.Get(u)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s", resp.Status)
+	}
+
+	ct := resp.Header.Get("Content-Type")
+	if mt, _, err := mime.ParseMediaType(ct); err != nil || !strings.HasPrefix(mt, "image/") {
+		return "", fmt.Errorf("Not an image: %s", ct)
+	}
+	return up.UploadImage(path.Base(resp.Request.URL.Path), ct, resp.Body)
+}
@@ -0,0 +1,21 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestFindImages(t *testing.T) {
+	refs := FindImages(`![a](https://i.snap.as/a.png) ![b](http://localhost:8080/b.png)
+![c](file:///home/me/c.png) ![d](images/d.png) <img src="http://192.168.1.5/e.jpg">`)
+
+	expected := []string{"", "Local host.", "Local file.", "Relative path.", "Private network address."}
+	if len(refs) != len(expected) {
+		t.Fatalf("Unexpected images: %+v", refs)
+	}
+	for i, r := range refs {
+		if r.Problem != expected[i] {
+			t.Errorf("Unexpected problem for %s: %q", r.URL, r.Problem)
+		}
+	}
+}
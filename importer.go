@@ -0,0 +1,227 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type (
+	// Document is a local Markdown file to be published.
+	Document struct {
+		// Path of the document, slash-separated and relative to the root of
+		// the import.
+		Path    string
+		Title   string
+		Content string
+	}
+
+	// ImportResult is a Document and the post it was published as.
+	ImportResult struct {
+		Document *Document
+		Post     *Post
+	}
+
+	// Importer publishes a set of Documents that may link to each other by
+	// relative path or, as in Obsidian vaults and Notion exports, by name.
+	// After every document is published, links between them are rewritten to
+	// the published post URLs, keeping cross-links intact.
+	Importer struct {
+		Client     *Client
+		Collection string
+	}
+
+	// linkIndex maps the ways documents refer to each other to published
+	// post URLs.
+	linkIndex struct {
+		byPath map[string]string
+		byName map[string]string
+	}
+)
+
+var (
+	wikiLinkReg   = regexp.MustCompile(`(!?)\[\[([^\]|#]*)(#[^\]|]*)?(?:\|([^\]]*))?\]\]`)
+	notionHashReg = regexp.MustCompile(`\s+[0-9a-f]{32}$`)
+	mdLinkReg     = regexp.MustCompile(`(\[[^\]]*\]\()([^)\s]+)(\))`)
+	mdRefDefReg   = regexp.MustCompile(`(?m)^(\s{0,3}\[[^\]]+\]:\s*)(\S+)()`)
+	h1Reg         = regexp.MustCompile(`^#\s+(.+?)\s*\n+`)
+)
+
+// NewDocument creates a Document from a file's path and content. The title is
+// taken from a leading "# " heading, which is removed from the content, or
+// else from the file name.
+func NewDocument(p, content string) Document {
+	d := Document{Path: filepath.ToSlash(p), Content: strings.Replace(content, "\r\n", "\n", -1)}
+	if m := h1Reg.FindStringSubmatch(d.Content); m != nil {
+		d.Title = m[1]
+		d.Content = d.Content[len(m[0]):]
+	} else {
+		d.Title = documentName(d.Path)
+	}
+	return d
+}
+
+// LoadDocuments reads every Markdown file under the given directory.
+func LoadDocuments(root string) ([]Document, error) {
+	var docs []Document
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isMarkdownFile(p) {
+			return nil
+		}
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, NewDocument(rel, string(data)))
+		return nil
+	})
+	return docs, err
+}
+
+// Import publishes the given documents, then updates any whose links to other
+// documents can be rewritten to published URLs. Results are returned in the
+// same order as docs.
+func (im *Importer) Import(docs []Document) *BatchResult[ImportResult] {
+	results := &BatchResult[ImportResult]{}
+	idx := newLinkIndex()
+	for i := range docs {
+		d := &docs[i]
+		p, err := im.Client.CreatePost(&PostParams{
+			Title:      d.Title,
+			Content:    d.Content,
+			Collection: im.Collection,
+		})
+		if err == nil {
+			idx.add(d, im.Client.postURL(p))
+		}
+		results.add(ImportResult{Document: d, Post: p}, err)
+	}
+
+	for i := range results.Items {
+		item := &results.Items[i]
+		if item.Err != nil {
+			continue
+		}
+		r := &item.Value
+		content := idx.rewrite(r.Document)
+		if content == r.Document.Content {
+			continue
+		}
+		p, err := im.Client.UpdatePost(&PostParams{
+			ID:      r.Post.ID,
+			Token:   r.Post.Token,
+			Title:   r.Document.Title,
+			Content: content,
+		})
+		if err != nil {
+			item.Err = err
+			continue
+		}
+		p.Token = r.Post.Token
+		r.Post = p
+	}
+	return results
+}
+
+func newLinkIndex() *linkIndex {
+	return &linkIndex{
+		byPath: map[string]string{},
+		byName: map[string]string{},
+	}
+}
+
+func (idx *linkIndex) add(d *Document, u string) {
+	idx.byPath[path.Clean(d.Path)] = u
+	for _, name := range []string{d.Title, documentName(d.Path), path.Base(d.Path), strings.TrimSuffix(d.Path, path.Ext(d.Path))} {
+		idx.byName[strings.ToLower(name)] = u
+	}
+}
+
+// rewrite returns the document's content with links to other documents
+// replaced by their published URLs.
+func (idx *linkIndex) rewrite(d *Document) string {
+	content := wikiLinkReg.ReplaceAllStringFunc(d.Content, func(m string) string {
+		sub := wikiLinkReg.FindStringSubmatch(m)
+		embed, target, anchor, alias := sub[1], sub[2], sub[3], sub[4]
+		if embed != "" {
+			return m
+		}
+		u, ok := idx.byName[strings.ToLower(strings.TrimSpace(target))]
+		if !ok {
+			return m
+		}
+		text := alias
+		if text == "" {
+			text = target
+		}
+		return "[" + text + "](" + u + headingAnchor(anchor) + ")"
+	})
+
+	for _, reg := range []*regexp.Regexp{mdLinkReg, mdRefDefReg} {
+		content = reg.ReplaceAllStringFunc(content, func(m string) string {
+			sub := reg.FindStringSubmatch(m)
+			if u, ok := idx.resolve(d, sub[2]); ok {
+				return sub[1] + u + sub[3]
+			}
+			return m
+		})
+	}
+	return content
+}
+
+// resolve finds the published URL for a Markdown link target that refers to
+// another document, either by a path relative to the linking document or, as
+// Notion exports do, by file name alone.
+func (idx *linkIndex) resolve(from *Document, target string) (string, bool) {
+	pu, err := url.Parse(target)
+	if err != nil || pu.Scheme != "" || pu.Host != "" || !isMarkdownFile(pu.Path) {
+		return "", false
+	}
+
+	u, ok := idx.byPath[path.Join(path.Dir(from.Path), pu.Path)]
+	if !ok && !strings.HasPrefix(pu.Path, "/") {
+		u, ok = idx.byPath[path.Clean(pu.Path)]
+	}
+	if !ok {
+		u, ok = idx.byName[strings.ToLower(documentName(pu.Path))]
+	}
+	if !ok {
+		return "", false
+	}
+	return u + headingAnchor(pu.Fragment), true
+}
+
+// documentName returns the name of a document from its path, without any
+// extension or Notion ID suffix.
+func documentName(p string) string {
+	name := strings.TrimSuffix(path.Base(p), path.Ext(p))
+	return notionHashReg.ReplaceAllString(name, "")
+}
+
+// headingAnchor converts an Obsidian "#Heading" reference into a URL
+// fragment.
+func headingAnchor(anchor string) string {
+	anchor = HeadingAnchor(strings.TrimPrefix(anchor, "#"))
+	if anchor == "" {
+		return ""
+	}
+	return "#" + anchor
+}
@@ -0,0 +1,71 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLinkIndexRewrite(t *testing.T) {
+	docs := []Document{
+		NewDocument("Daily Notes.md", "# Today\n\nSee [[Ideas#Big One|my ideas]] and [[Missing]]. ![[diagram.png]]"),
+		NewDocument("Ideas 0123456789abcdef0123456789abcdef.md", "Back to [notes](Daily%20Notes.md)."),
+	}
+	if docs[0].Title != "Today" || docs[1].Title != "Ideas" {
+		t.Fatalf("Unexpected titles: %q, %q", docs[0].Title, docs[1].Title)
+	}
+
+	idx := newLinkIndex()
+	idx.add(&docs[0], "https://write.as/blog/today")
+	idx.add(&docs[1], "https://write.as/blog/ideas")
+
+	expected := "See [my ideas](https://write.as/blog/ideas#big-one) and [[Missing]]. ![[diagram.png]]"
+	if res := idx.rewrite(&docs[0]); res != expected {
+		t.Errorf("Unexpected rewrite: %s", res)
+	}
+	if res := idx.rewrite(&docs[1]); res != "Back to [notes](https://write.as/blog/today)." {
+		t.Errorf("Unexpected rewrite: %s", res)
+	}
+}
+
+func TestLinkIndexRelativePaths(t *testing.T) {
+	docs := []Document{
+		NewDocument("guides/setup.md", "Next, read [usage](../usage.md#flags).\n\n[ref]: ./advanced/tips.md"),
+		NewDocument("usage.md", "See [setup](guides/setup.md)."),
+		NewDocument("guides/advanced/tips.md", "Tips."),
+	}
+	idx := newLinkIndex()
+	idx.add(&docs[0], "https://write.as/docs/setup")
+	idx.add(&docs[1], "https://write.as/docs/usage")
+	idx.add(&docs[2], "https://write.as/docs/tips")
+
+	expected := "Next, read [usage](https://write.as/docs/usage#flags).\n\n[ref]: https://write.as/docs/tips"
+	if res := idx.rewrite(&docs[0]); res != expected {
+		t.Errorf("Unexpected rewrite: %s", res)
+	}
+	if res := idx.rewrite(&docs[1]); res != "See [setup](https://write.as/docs/setup)." {
+		t.Errorf("Unexpected rewrite: %s", res)
+	}
+}
+
+func TestImport(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	im := &Importer{Client: api.client(), Collection: "blog"}
+
+	res := im.Import([]Document{
+		NewDocument("one.md", "# One\n\nSee [two](two.md)."),
+		NewDocument("two.md", "# Two\n\nHello."),
+	})
+	if err := res.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	posts := res.Succeeded()
+	if len(posts) != 2 || posts[0].Post.Title != "One" {
+		t.Fatalf("Unexpected results: %+v", posts)
+	}
+	if !strings.Contains(posts[0].Post.Content, "/"+posts[1].Post.ID) {
+		t.Errorf("Expected link to be rewritten: %s", posts[0].Post.Content)
+	}
+}
@@ -0,0 +1,45 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// WithInsecureHTTP allows NewClientForInstance to use a plain HTTP instance
+// URL, like "http://localhost:8080".
+//
+// For local development only: credentials and posts are sent unencrypted.
+func WithInsecureHTTP() Option {
+	return func(c *Client) {
+		c.insecureHTTP = true
+	}
+}
+
+// WithSkipTLSVerify turns off TLS certificate verification, so the Client can
+// talk to an instance with a self-signed certificate.
+//
+// For local development only: it leaves every request open to interception.
+func WithSkipTLSVerify() Option {
+	return func(c *Client) {
+		t := c.ownTransport()
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+	}
+}
+
+// ownTransport replaces the Client's transport with a clone, starting from
+// http.DefaultTransport if it doesn't have an *http.Transport, and returns
+// it. Options change the clone, so they never affect other clients sharing
+// the transport, like every client using http.DefaultTransport.
+func (c *Client) ownTransport() *http.Transport {
+	t, ok := c.client.Transport.(*http.Transport)
+	if !ok || t == nil {
+		t = http.DefaultTransport.(*http.Transport)
+	}
+	t = t.Clone()
+	c.client.Transport = t
+	return t
+}
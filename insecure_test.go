@@ -0,0 +1,47 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithSkipTLSVerify(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":200,"data":{"id":"abc"}}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClientForInstance(srv.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = c.GetPost("abc"); err == nil {
+		t.Errorf("Expected self-signed certificate to be rejected")
+	}
+
+	c, err = NewClientForInstance(srv.URL, WithSkipTLSVerify())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = c.GetPost("abc"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestWithSkipTLSVerifySharedTransport(t *testing.T) {
+	shared := &http.Transport{}
+	c := NewClient(WithTransport(shared), WithSkipTLSVerify())
+	if shared.TLSClientConfig != nil && shared.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("Expected the shared transport to be left alone")
+	}
+	if tr, ok := c.client.Transport.(*http.Transport); !ok || tr == shared || !tr.TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("Expected a clone of the transport that skips verification")
+	}
+
+	c = NewClient(WithSkipTLSVerify())
+	if tr := c.client.Transport.(*http.Transport); tr.Proxy == nil || !tr.ForceAttemptHTTP2 {
+		t.Errorf("Expected a clone of http.DefaultTransport")
+	}
+}
@@ -0,0 +1,58 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NewClientForInstance creates a new API client for a self-hosted WriteFreely
+// instance at the given URL, which may include a path prefix for instances
+// served behind a reverse proxy, like "https://example.com/writefreely". The
+// API is expected at "/api" under the instance URL; an instance URL already
+// ending in "/api" is used as-is.
+//
+// Plain HTTP URLs are rejected unless the WithInsecureHTTP option is given.
+// Options are applied after the instance URL is set, so WithBaseURL can still
+// point the Client at a different API URL.
+func NewClientForInstance(instanceURL string, opts ...Option) (*Client, error) {
+	instance, api, err := parseInstanceURL(instanceURL)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{
+		client:   &http.Client{Timeout: defaultHTTPTimeout},
+		baseURL:  api,
+		instance: instance,
+	}
+	c.applyOptions(opts)
+	if strings.HasPrefix(instance, "http:") && !c.insecureHTTP {
+		return nil, fmt.Errorf("Invalid instance URL %q: use https, or WithInsecureHTTP for local development.", instanceURL)
+	}
+	return c, nil
+}
+
+// parseInstanceURL validates an instance URL, returning the instance's public
+// URL and its API URL.
+func parseInstanceURL(s string) (instance, api string, err error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", "", fmt.Errorf("Invalid instance URL: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", fmt.Errorf("Invalid instance URL %q: scheme must be http or https.", s)
+	}
+	if u.Host == "" {
+		return "", "", fmt.Errorf("Invalid instance URL %q: missing host.", s)
+	}
+	if u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return "", "", fmt.Errorf("Invalid instance URL %q: only a scheme, host, and path are allowed.", s)
+	}
+
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	instance = strings.TrimSuffix(u.String(), "/api")
+	return instance, instance + "/api", nil
+}
@@ -0,0 +1,48 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewClientForInstance(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"code":200,"data":{"id":"abc"}}`))
+	}))
+	defer srv.Close()
+
+	if _, err := NewClientForInstance(srv.URL); err == nil {
+		t.Errorf("Expected plain HTTP to be rejected")
+	}
+	c, err := NewClientForInstance(srv.URL+"/writefreely/", WithInsecureHTTP())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	p, err := c.GetPost("abc")
+	if err != nil || path != "/writefreely/api/posts/abc" {
+		t.Errorf("Unexpected request path %q: %v", path, err)
+	}
+	if u := c.postURL(p); u != srv.URL+"/writefreely/abc" {
+		t.Errorf("Unexpected post URL: %s", u)
+	}
+
+	c, err = NewClientForInstance("https://example.com/writefreely/api")
+	if err != nil || c.baseURL != "https://example.com/writefreely/api" || c.instanceURL() != "https://example.com/writefreely" {
+		t.Errorf("Unexpected client: %+v, %v", c, err)
+	}
+
+	c, err = NewClientForInstance("https://example.com", WithBaseURL("https://api.example.com/"))
+	if err != nil || c.baseURL != "https://api.example.com" || c.instanceURL() != "https://example.com" {
+		t.Errorf("Expected WithBaseURL to be kept: %+v, %v", c, err)
+	}
+
+	for _, bad := range []string{"example.com", "ftp://example.com", "https://", "https://example.com/?lang=en"} {
+		if _, err := NewClientForInstance(bad); err == nil {
+			t.Errorf("Expected %q to be invalid", bad)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+#author: Nguyễn Thái Sơn
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestPostLifecycle(t *testing.T) {
+	c := newClient(t)
+	defer c.LogOut()
+
+	p, err := c.CreatePost(&writeas.PostParams{Title: "Integration", Content: "Created."})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if p, err = c.GetPost(p.ID); err != nil || p.Content != "Created." {
+		t.Fatalf("Get failed: %+v, %v", p, err)
+	}
+	if _, err = c.UpdatePost(&writeas.PostParams{ID: p.ID, Token: p.Token, Content: "Updated."}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err = c.DeletePost(&writeas.PostParams{ID: p.ID, Token: p.Token}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err = c.DeletePostIdempotent(&writeas.PostParams{ID: p.ID, Token: p.Token}); err != nil {
+		t.Errorf("Idempotent delete failed: %v", err)
+	}
+}
+
+func TestCollectionLifecycle(t *testing.T) {
+	c := newClient(t)
+	defer c.LogOut()
+
+	alias := fmt.Sprintf("it%d", time.Now().Unix())
+	coll, action, err := c.EnsureCollection(&writeas.CollectionParams{Alias: alias, Title: "Integration"})
+	if err != nil || action != writeas.EnsureCreated {
+		t.Fatalf("EnsureCollection failed: %+v, %s, %v", coll, action, err)
+	}
+
+	sp := &writeas.PostParams{Collection: alias, Slug: "hello", Title: "Hello", Content: "Hello, world."}
+	if _, action, err = c.EnsurePost(sp); err != nil || action != writeas.EnsureCreated {
+		t.Fatalf("EnsurePost failed: %s, %v", action, err)
+	}
+	if _, action, err = c.EnsurePost(sp); err != nil || action != writeas.EnsureUnchanged {
+		t.Errorf("EnsurePost should be idempotent: %s, %v", action, err)
+	}
+	p, err := c.GetCollectionPost(alias, "hello")
+	if err != nil {
+		t.Fatalf("GetCollectionPost failed: %v", err)
+	}
+	if err = c.PinPost(alias, &writeas.PinnedPostParams{ID: p.ID, Position: 1}); err != nil {
+		t.Errorf("Pin failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err = c.Backup(&buf); err != nil {
+		t.Errorf("Backup failed: %v", err)
+	}
+}
+
+func TestWhoAmI(t *testing.T) {
+	c := newClient(t)
+	u, err := c.WhoAmI()
+	if err != nil || u.Username != instance.User {
+		t.Fatalf("WhoAmI failed: %+v, %v", u, err)
+	}
+	if err = c.LogOut(); err != nil {
+		t.Fatalf("Log out failed: %v", err)
+	}
+	if info, err := c.IntrospectToken(); err != nil || info.Valid {
+		t.Errorf("Expected token to be invalid after logging out: %+v, %v", info, err)
+	}
+}
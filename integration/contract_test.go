@@ -0,0 +1,129 @@
+#author: Nguyễn Thái Sơn
+//go:build integration && contract
+// +build integration,contract
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/writeas/go-writeas"
+)
+
+// scenario exercises one API behavior, returning an observation that's
+// compared between targets, like "supported" or an error message.
+type scenario struct {
+	name string
+	run  func(c *writeas.Client) string
+}
+
+var scenarios = []scenario{
+	{"custom slug", func(c *writeas.Client) string {
+		alias, err := contractCollection(c)
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		p, err := c.CreatePost(&writeas.PostParams{Collection: alias, Slug: "contract-slug", Content: "Slug."})
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		defer c.DeletePost(&writeas.PostParams{ID: p.ID})
+		return fmt.Sprintf("slug %s", p.Slug)
+	}},
+	{"backdated post", func(c *writeas.Client) string {
+		created := time.Date(2019, 4, 1, 7, 30, 0, 0, time.UTC)
+		p, err := c.CreatePost(&writeas.PostParams{Content: "Backdated.", Created: &created})
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		defer c.DeletePost(&writeas.PostParams{ID: p.ID, Token: p.Token})
+		return fmt.Sprintf("created kept: %t", p.Created.Equal(created))
+	}},
+	{"delete missing post", func(c *writeas.Client) string {
+		return errString(c.DeletePost(&writeas.PostParams{ID: "contractmissing"}))
+	}},
+	{"sessions", func(c *writeas.Client) string {
+		_, err := c.ListSessions()
+		return errString(err)
+	}},
+	{"subscribers", func(c *writeas.Client) string {
+		alias, err := contractCollection(c)
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		_, err = c.GetCollectionSubscribers(alias)
+		return errString(err)
+	}},
+	{"email verification", func(c *writeas.Client) string {
+		return errString(c.RequestEmailVerification())
+	}},
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return "error: " + err.Error()
+}
+
+// contractCollection ensures a collection for the contract tests exists.
+func contractCollection(c *writeas.Client) (string, error) {
+	alias := "contract-tests"
+	_, _, err := c.EnsureCollection(&writeas.CollectionParams{Alias: alias, Title: "Contract Tests"})
+	return alias, err
+}
+
+// TestContract runs every scenario against Write.as' development instance
+// and the WriteFreely test instance, logging where their behavior differs.
+// The results are written as JSON to CONTRACT_REPORT, if set, for updating
+// the client's notes on which calls each kind of instance supports.
+// Credentials for Write.as come from WRITEAS_DEV_USER and WRITEAS_DEV_PASS.
+func TestContract(t *testing.T) {
+	user, pass := os.Getenv("WRITEAS_DEV_USER"), os.Getenv("WRITEAS_DEV_PASS")
+	if user == "" {
+		t.Skip("WRITEAS_DEV_USER not set")
+	}
+	wa := writeas.NewDevClient()
+	if _, err := wa.LogIn(user, pass); err != nil {
+		t.Fatalf("Unable to log in to Write.as: %v", err)
+	}
+	defer wa.LogOut()
+	wf := newClient(t)
+	defer wf.LogOut()
+
+	report := map[string]map[string]string{}
+	for _, s := range scenarios {
+		report[s.name] = map[string]string{
+			"writeas":     s.run(wa),
+			"writefreely": s.run(wf),
+		}
+	}
+
+	names := make([]string, 0, len(report))
+	for name := range report {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		r := report[name]
+		if r["writeas"] != r["writefreely"] {
+			t.Logf("%s differs:\n  write.as:    %s\n  WriteFreely: %s", name, r["writeas"], r["writefreely"])
+		}
+	}
+
+	if path := os.Getenv("CONTRACT_REPORT"); path != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+#author: Nguyễn Thái Sơn
+// Package integration runs the client against a real WriteFreely instance, to
+// catch API incompatibilities the offline tests miss. Its tests only build
+// with the "integration" tag, and need Docker:
+//
+//	go test -tags integration ./integration
+//
+// By default a WriteFreely container is started from WRITEFREELY_IMAGE (or
+// "writeas/writefreely:latest") and an admin user is provisioned in it. To
+// test an instance that's already running instead, set WRITEFREELY_URL,
+// WRITEFREELY_USER, and WRITEFREELY_PASS.
+//
+// Contract tests, which compare Write.as' behavior with WriteFreely's, also
+// need the "contract" tag:
+//
+//	go test -tags "integration contract" ./integration
+package integration
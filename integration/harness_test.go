@@ -0,0 +1,115 @@
+#author: Nguyễn Thái Sơn
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/writeas/go-writeas"
+)
+
+const (
+	defaultImage = "writeas/writefreely:latest"
+	testUser     = "integration"
+	testPass     = "integration-password"
+)
+
+// instance is the WriteFreely instance the tests run against.
+var instance struct {
+	URL       string
+	User      string
+	Pass      string
+	container string
+}
+
+func TestMain(m *testing.M) {
+	if err := setUp(); err != nil {
+		fmt.Fprintf(os.Stderr, "integration: %v\n", err)
+		tearDown()
+		os.Exit(1)
+	}
+	code := m.Run()
+	tearDown()
+	os.Exit(code)
+}
+
+func setUp() error {
+	if u := os.Getenv("WRITEFREELY_URL"); u != "" {
+		instance.URL = u
+		instance.User = os.Getenv("WRITEFREELY_USER")
+		instance.Pass = os.Getenv("WRITEFREELY_PASS")
+		return nil
+	}
+
+	image := os.Getenv("WRITEFREELY_IMAGE")
+	if image == "" {
+		image = defaultImage
+	}
+	id, err := docker("run", "-d", "--rm", "-p", "127.0.0.1::8080", image)
+	if err != nil {
+		return fmt.Errorf("start container: %v", err)
+	}
+	instance.container = id
+
+	port, err := docker("port", id, "8080")
+	if err != nil {
+		return fmt.Errorf("get port: %v", err)
+	}
+	instance.URL = "http://" + strings.TrimSpace(strings.Split(port, "\n")[0])
+	if err = waitUntilUp(instance.URL, time.Minute); err != nil {
+		return err
+	}
+
+	if _, err = docker("exec", id, "writefreely", "user", "create", "--admin", testUser+":"+testPass); err != nil {
+		return fmt.Errorf("create user: %v", err)
+	}
+	instance.User, instance.Pass = testUser, testPass
+	return nil
+}
+
+func tearDown() {
+	if instance.container != "" {
+		docker("rm", "-f", instance.container)
+	}
+}
+
+func docker(args ...string) (string, error) {
+	out, err := exec.Command("docker", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("docker %s: %v: %s", args[0], err, out)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func waitUntilUp(url string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("instance at %s didn't come up within %s", url, timeout)
+}
+
+// newClient returns a Client logged in to the test instance.
+func newClient(t *testing.T) *writeas.Client {
+	c, err := writeas.NewClientForInstance(instance.URL, writeas.WithInsecureHTTP())
+	if err != nil {
+		t.Fatalf("Unable to create client: %v", err)
+	}
+	if _, err = c.LogIn(instance.User, instance.Pass); err != nil {
+		t.Fatalf("Unable to log in: %v", err)
+	}
+	return c
+}
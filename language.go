@@ -0,0 +1,56 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"strings"
+)
+
+// RTLLanguages holds the codes of languages written right-to-left. Posts in
+// these languages have PostParams.IsRTL set automatically, unless it's set
+// explicitly.
+var RTLLanguages = map[string]bool{
+	"ar":  true, // Arabic
+	"ckb": true, // Central Kurdish
+	"dv":  true, // Divehi
+	"fa":  true, // Persian
+	"he":  true, // Hebrew
+	"ps":  true, // Pashto
+	"sd":  true, // Sindhi
+	"ug":  true, // Uyghur
+	"ur":  true, // Urdu
+	"yi":  true, // Yiddish
+}
+
+// IsRTLLanguage reports whether the language with the given code, like "ar" or
+// "fa-IR", is written right-to-left.
+func IsRTLLanguage(lang string) bool {
+	base := strings.ToLower(strings.SplitN(strings.Replace(lang, "_", "-", -1), "-", 2)[0])
+	return RTLLanguages[base]
+}
+
+// normalizeLanguage returns a copy of the given PostParams with its Language
+// validated and normalized with NormalizeLanguage.
+func normalizeLanguage(sp *PostParams) (*PostParams, error) {
+	if sp.Language == nil {
+		return sp, nil
+	}
+	lang, err := NormalizeLanguage(*sp.Language)
+	if err != nil {
+		return nil, err
+	}
+	lp := *sp
+	lp.Language = &lang
+	return &lp, nil
+}
+
+// applyRTL returns a copy of the given PostParams with IsRTL set from its
+// Language, if IsRTL isn't already set.
+func applyRTL(sp *PostParams) *PostParams {
+	if sp.Language == nil || sp.IsRTL != nil {
+		return sp
+	}
+	rp := *sp
+	rtl := IsRTLLanguage(*sp.Language)
+	rp.IsRTL = &rtl
+	return &rp
+}
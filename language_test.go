@@ -0,0 +1,24 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestApplyRTL(t *testing.T) {
+	ar, en := "ar-EG", "en"
+	ltr := false
+
+	if sp := applyRTL(&PostParams{Language: &ar}); sp.IsRTL == nil || !*sp.IsRTL {
+		t.Errorf("Expected Arabic post to be RTL")
+	}
+	if sp := applyRTL(&PostParams{Language: &en}); sp.IsRTL == nil || *sp.IsRTL {
+		t.Errorf("Expected English post to be LTR")
+	}
+	if sp := applyRTL(&PostParams{Language: &ar, IsRTL: &ltr}); *sp.IsRTL {
+		t.Errorf("Expected explicit IsRTL to be kept")
+	}
+	if sp := applyRTL(&PostParams{}); sp.IsRTL != nil {
+		t.Errorf("Expected IsRTL to stay unset without a language")
+	}
+}
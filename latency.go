@@ -0,0 +1,146 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the latency histogram buckets kept for
+// each endpoint. A final bucket counts slower calls.
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+type (
+	// EndpointLatency is the latency histogram of calls to one endpoint.
+	EndpointLatency struct {
+		Count int64
+		Total time.Duration
+		Max   time.Duration
+		// Buckets counts calls by latency, with one count for each of
+		// LatencyBuckets and a final one for slower calls.
+		Buckets []int64
+	}
+
+	// SlowCall describes an API call that took longer than the threshold set
+	// with WithSlowCallThreshold.
+	SlowCall struct {
+		// Endpoint is the method and path of the call, with IDs replaced,
+		// like "GET /posts/{id}".
+		Endpoint string
+		Took     time.Duration
+		Status   int
+	}
+
+	// latencyTracker records the latency of a Client's calls.
+	latencyTracker struct {
+		mu        sync.Mutex
+		endpoints map[string]*EndpointLatency
+
+		threshold time.Duration
+		onSlow    func(SlowCall)
+	}
+)
+
+// WithSlowCallThreshold calls onSlow for every API call that takes longer than
+// threshold. If onSlow is nil, slow calls are logged with the standard logger.
+func WithSlowCallThreshold(threshold time.Duration, onSlow func(SlowCall)) Option {
+	return func(c *Client) {
+		c.latency.threshold = threshold
+		c.latency.onSlow = onSlow
+	}
+}
+
+// Mean returns the mean latency of the calls.
+func (l EndpointLatency) Mean() time.Duration {
+	if l.Count == 0 {
+		return 0
+	}
+	return l.Total / time.Duration(l.Count)
+}
+
+// Latencies returns the latency histograms of the API calls the Client made,
+// by endpoint, like "GET /posts/{id}".
+func (c *Client) Latencies() map[string]EndpointLatency {
+	c.latency.mu.Lock()
+	defer c.latency.mu.Unlock()
+	res := make(map[string]EndpointLatency, len(c.latency.endpoints))
+	for name, l := range c.latency.endpoints {
+		cp := *l
+		cp.Buckets = append([]int64(nil), l.Buckets...)
+		res[name] = cp
+	}
+	return res
+}
+
+// record adds a call to the endpoint's histogram, reporting it if it was slow.
+func (t *latencyTracker) record(endpoint string, took time.Duration, status int) {
+	t.mu.Lock()
+	if t.endpoints == nil {
+		t.endpoints = map[string]*EndpointLatency{}
+	}
+	l, ok := t.endpoints[endpoint]
+	if !ok {
+		l = &EndpointLatency{Buckets: make([]int64, len(LatencyBuckets)+1)}
+		t.endpoints[endpoint] = l
+	}
+	l.Count++
+	l.Total += took
+	if took > l.Max {
+		l.Max = took
+	}
+	i := 0
+	for i < len(LatencyBuckets) && took > LatencyBuckets[i] {
+		i++
+	}
+	l.Buckets[i]++
+	threshold, onSlow := t.threshold, t.onSlow
+	t.mu.Unlock()
+
+	if threshold <= 0 || took <= threshold {
+		return
+	}
+	sc := SlowCall{Endpoint: endpoint, Took: took, Status: status}
+	if onSlow == nil {
+		log.Printf("writeas: slow call %s took %s (status %d)", sc.Endpoint, sc.Took, sc.Status)
+		return
+	}
+	if err := SafeCall("onSlow", func() { onSlow(sc) }); err != nil {
+		log.Printf("writeas: %v", err)
+	}
+}
+
+// endpointActions are path segments that name an action or listing, rather
+// than an ID, after "posts" or "collections".
+var endpointActions = map[string]bool{
+	"claim":       true,
+	"posts":       true,
+	"pin":         true,
+	"unpin":       true,
+	"subscribers": true,
+}
+
+// endpointName returns the method and API path of a call, with the IDs,
+// aliases, and slugs in it replaced by "{id}".
+func endpointName(method, path string) string {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segs); i++ {
+		switch segs[i-1] {
+		case "posts", "collections", "sessions", "users":
+			if !endpointActions[segs[i]] {
+				segs[i] = "{id}"
+			}
+		}
+	}
+	return method + " /" + strings.Join(segs, "/")
+}
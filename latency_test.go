@@ -0,0 +1,68 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEndpointName(t *testing.T) {
+	tests := map[string]string{
+		"/posts/abc":                    "GET /posts/{id}",
+		"/posts/claim":                  "GET /posts/claim",
+		"/collections/blog/posts/hello": "GET /collections/{id}/posts/{id}",
+		"/collections/blog/pin":         "GET /collections/{id}/pin",
+		"/me/sessions/s1":               "GET /me/sessions/{id}",
+		"/me/posts":                     "GET /me/posts",
+	}
+	for path, expected := range tests {
+		if name := endpointName("GET", path); name != expected {
+			t.Errorf("endpointName(%q) = %q; expected %q", path, name, expected)
+		}
+	}
+}
+
+func TestLatencies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/posts/slow" {
+			time.Sleep(60 * time.Millisecond)
+		}
+		w.Write([]byte(`{"code":200,"data":{"id":"abc"}}`))
+	}))
+	defer srv.Close()
+
+	var slow []SlowCall
+	c, _ := NewClientForInstance(srv.URL, WithInsecureHTTP(), WithSlowCallThreshold(50*time.Millisecond, func(sc SlowCall) {
+		slow = append(slow, sc)
+	}))
+	c.GetPost("fast")
+	c.GetPost("slow")
+
+	l := c.Latencies()["GET /posts/{id}"]
+	if l.Count != 2 || l.Max < 60*time.Millisecond || l.Buckets[len(l.Buckets)-1] != 0 {
+		t.Errorf("Unexpected latency: %+v", l)
+	}
+	if len(slow) != 1 || slow[0].Endpoint != "GET /posts/{id}" || slow[0].Status != http.StatusOK {
+		t.Errorf("Unexpected slow calls: %+v", slow)
+	}
+}
+
+func TestLatenciesClock(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":200,"data":{"id":"abc"}}`))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := ClockFunc(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+	c, _ := NewClientForInstance(srv.URL, WithInsecureHTTP(), WithClock(clock))
+	c.GetPost("abc")
+	if l := c.Latencies()["GET /posts/{id}"]; l.Count != 1 || l.Max < time.Second {
+		t.Errorf("Expected latency to be timed with the Client's Clock: %+v", l)
+	}
+}
@@ -0,0 +1,93 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// License is a content license that posts can be published under.
+type License struct {
+	// ID is the license's SPDX identifier, e.g. "CC-BY-4.0".
+	ID   string
+	Name string
+	URL  string
+}
+
+// Common licenses for published writing.
+var (
+	CC0        = License{"CC0-1.0", "CC0 1.0", "https://creativecommons.org/publicdomain/zero/1.0/"}
+	CCBY40     = License{"CC-BY-4.0", "CC BY 4.0", "https://creativecommons.org/licenses/by/4.0/"}
+	CCBYSA40   = License{"CC-BY-SA-4.0", "CC BY-SA 4.0", "https://creativecommons.org/licenses/by-sa/4.0/"}
+	CCBYND40   = License{"CC-BY-ND-4.0", "CC BY-ND 4.0", "https://creativecommons.org/licenses/by-nd/4.0/"}
+	CCBYNC40   = License{"CC-BY-NC-4.0", "CC BY-NC 4.0", "https://creativecommons.org/licenses/by-nc/4.0/"}
+	CCBYNCSA40 = License{"CC-BY-NC-SA-4.0", "CC BY-NC-SA 4.0", "https://creativecommons.org/licenses/by-nc-sa/4.0/"}
+	CCBYNCND40 = License{"CC-BY-NC-ND-4.0", "CC BY-NC-ND 4.0", "https://creativecommons.org/licenses/by-nc-nd/4.0/"}
+)
+
+var licenses = []License{CC0, CCBY40, CCBYSA40, CCBYND40, CCBYNC40, CCBYNCSA40, CCBYNCND40}
+
+// LicenseByID returns the known License with the given SPDX identifier,
+// case-insensitively.
+func LicenseByID(id string) (License, bool) {
+	for _, l := range licenses {
+		if strings.EqualFold(l.ID, id) {
+			return l, true
+		}
+	}
+	return License{}, false
+}
+
+// DetectLicense finds the license a post was published under, by looking for
+// a known license URL in its content.
+func DetectLicense(content string) (License, bool) {
+	// Check longer URLs first, so by-nc-sa isn't mistaken for by-nc
+	sorted := make([]License, len(licenses))
+	copy(sorted, licenses)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i].URL) > len(sorted[j].URL)
+	})
+	for _, l := range sorted {
+		if strings.Contains(content, strings.TrimSuffix(l.URL, "/")) {
+			return l, true
+		}
+	}
+	return License{}, false
+}
+
+// Notice returns a Markdown license notice for the License.
+func (l License) Notice() string {
+	return fmt.Sprintf("This post is licensed under [%s](%s).", l.Name, l.URL)
+}
+
+// WithCollectionLicense sets the License for posts the Client creates or
+// updates in the given collection. Its notice is added to each post's footer.
+func WithCollectionLicense(alias string, l License) Option {
+	return func(c *Client) {
+		if c.licenses == nil {
+			c.licenses = map[string]License{}
+		}
+		c.licenses[alias] = l
+	}
+}
+
+// footerFor returns the footer to append to the given post, combining the
+// Client's footer with the license notice for the post's collection.
+func (c *Client) footerFor(sp *PostParams) string {
+	footer := c.footer
+	if l, ok := c.licenses[sp.Collection]; ok && sp.Collection != "" {
+		footer = joinFooter(footer, l.Notice())
+	}
+	return footer
+}
+
+func joinFooter(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	return strings.Join(nonEmpty, "\n\n")
+}
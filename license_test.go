@@ -0,0 +1,37 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLicenses(t *testing.T) {
+	c := NewClient(WithFooter("Written by Matt."), WithCollectionLicense("blog", CCBYNCSA40))
+
+	sp, err := c.prepareParams(&PostParams{Content: "Hello.", Collection: "blog"}, true)
+	if err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if !strings.HasSuffix(sp.Content, "Written by Matt.\n\n"+CCBYNCSA40.Notice()) {
+		t.Errorf("Unexpected content: %q", sp.Content)
+	}
+
+	l, ok := DetectLicense(sp.Content)
+	if !ok || l.ID != "CC-BY-NC-SA-4.0" {
+		t.Errorf("Unexpected detected license: %+v", l)
+	}
+
+	p := &Profile{Name: "work", License: "cc-by-4.0"}
+	if res := p.Apply(&PostParams{Content: "Hi."}); !strings.HasSuffix(res.Content, CCBY40.Notice()) {
+		t.Errorf("Unexpected profile content: %q", res.Content)
+	}
+}
+
+func TestFrontMatterLicense(t *testing.T) {
+	fm := FrontMatter(&Post{ID: "abc", Title: "Hi", Content: AppendFooter("Hello.", CC0.Notice())})
+	expected := "---\ntitle: \"Hi\"\nid: \"abc\"\nlicense: \"CC0-1.0\"\nlicense_url: \"https://creativecommons.org/publicdomain/zero/1.0/\"\n---\n"
+	if fm != expected {
+		t.Errorf("Unexpected front matter:\n%s", fm)
+	}
+}
@@ -0,0 +1,18 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+// Limits enforced by the Write.as API. Batch tooling should chunk requests
+// with these instead of hard-coding its own numbers.
+const (
+	// MaxClaimPosts is the most posts that can be claimed in a single
+	// ClaimPosts request.
+	MaxClaimPosts = 100
+	// PostsPerPage is the number of posts returned in each page of a
+	// collection's posts.
+	PostsPerPage = 10
+	// MaxTitleLength is the longest post title, in characters, that the
+	// API will store.
+	MaxTitleLength = 255
+	// MaxAliasLength is the longest collection alias the API accepts.
+	MaxAliasLength = 100
+)
@@ -0,0 +1,162 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LinkStatus is the result of checking a single outbound link.
+type LinkStatus struct {
+	URL    string
+	PostID string
+	// Code is the HTTP status code returned for the link, or 0 if the
+	// request failed.
+	Code int
+	Err  error
+}
+
+const (
+	linkCheckWorkers = 8
+	linkCheckRetries = 2
+)
+
+var linkReg = regexp.MustCompile(`https?://[^\s<>()\[\]"']+`)
+
+// Dead reports whether the link couldn't be reached or returned an error
+// status.
+func (s LinkStatus) Dead() bool {
+	return s.Err != nil || s.Code >= 400
+}
+
+// Missing reports whether the link returned 404 Not Found or 410 Gone, as
+// opposed to failing for another reason.
+func (s LinkStatus) Missing() bool {
+	return s.Code == http.StatusNotFound || s.Code == http.StatusGone
+}
+
+func (s LinkStatus) String() string {
+	if s.Err != nil {
+		return fmt.Sprintf("%s: %v", s.URL, s.Err)
+	}
+	return fmt.Sprintf("%s: %d", s.URL, s.Code)
+}
+
+// ExtractLinks returns the unique outbound http(s) links in the given content,
+// in the order they first appear.
+func ExtractLinks(content string) []string {
+	seen := map[string]bool{}
+	var links []string
+	for _, l := range linkReg.FindAllString(content, -1) {
+		l = strings.TrimRight(l, ".,;:!?*_")
+		if !seen[l] {
+			seen[l] = true
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+// CheckLinks verifies every outbound link in the given post, returning the
+// ones that are dead.
+func (c *Client) CheckLinks(postID string) ([]LinkStatus, error) {
+	p, err := c.GetPost(postID)
+	if err != nil {
+		return nil, err
+	}
+	return c.checkPostLinks(&[]Post{*p}), nil
+}
+
+// CheckCollectionLinks verifies every outbound link in the given collection's
+// posts, on every page, returning the ones that are dead.
+func (c *Client) CheckCollectionLinks(alias string) ([]LinkStatus, error) {
+	posts, err := c.allCollectionPosts(alias)
+	if err != nil {
+		return nil, err
+	}
+	return c.checkPostLinks(&posts), nil
+}
+
+func (c *Client) checkPostLinks(posts *[]Post) []LinkStatus {
+	var links []LinkStatus
+	for _, p := range *posts {
+		for _, l := range ExtractLinks(p.Content) {
+			links = append(links, LinkStatus{URL: l, PostID: p.ID})
+		}
+	}
+	return c.checkURLs(links)
+}
+
+// checkURLs checks the URLs of the given LinkStatuses concurrently, returning
+// the ones that are dead.
+func (c *Client) checkURLs(links []LinkStatus) []LinkStatus {
+	jobs := make(chan LinkStatus)
+	results := make(chan LinkStatus)
+
+	var wg sync.WaitGroup
+	for i := 0; i < linkCheckWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range jobs {
+				s.Code, s.Err = c.checkLink(s.URL)
+				results <- s
+			}
+		}()
+	}
+	go func() {
+		for _, l := range links {
+			jobs <- l
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var dead []LinkStatus
+	for s := range results {
+		if s.Dead() {
+			dead = append(dead, s)
+		}
+	}
+	return dead
+}
+
+// checkLink requests the given URL with HEAD, falling back to GET for servers
+// that don't support HEAD unless the Client is in low-bandwidth mode, and
+// retrying on network errors and server errors.
+func (c *Client) checkLink(url string) (int, error) {
+	var code int
+	var err error
+	for attempt := 0; attempt <= linkCheckRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 500 * time.Millisecond)
+		}
+		code, err = c.linkStatus("HEAD", url)
+		if err == nil && !c.lowBandwidth && (code == http.StatusMethodNotAllowed || code == http.StatusNotImplemented) {
+			code, err = c.linkStatus("GET", url)
+		}
+		if err == nil && code < 500 {
+			break
+		}
+	}
+	return code, err
+}
+
+func (c *Client) linkStatus(method, url string) (int, error) {
+	r, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	r.Header.Set("User-Agent", c.userAgent())
+	resp, err := c.client.Do(r)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,58 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	links := ExtractLinks("See [Write.as](https://write.as/about). Also https://write.as/about and <https://snap.as>.")
+	if len(links) != 2 || links[0] != "https://write.as/about" || links[1] != "https://snap.as" {
+		t.Errorf("Unexpected links: %v", links)
+	}
+}
+
+func TestCheckPostLinks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	dead := c.checkPostLinks(&[]Post{
+		{ID: "a", Content: "[ok](" + srv.URL + "/ok) and [gone](" + srv.URL + "/gone)"},
+	})
+	if len(dead) != 1 || dead[0].Code != http.StatusNotFound || dead[0].PostID != "a" {
+		t.Errorf("Unexpected dead links: %v", dead)
+	}
+}
+
+func TestCheckCollectionLinks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	for i := 0; i < PostsPerPage; i++ {
+		api.addPost("blog", Post{ID: fmt.Sprintf("p%02d", i), Content: "[ok](" + srv.URL + "/ok)"})
+	}
+	api.addPost("blog", Post{ID: "p99", Content: "[gone](" + srv.URL + "/gone)"})
+
+	dead, err := api.client().CheckCollectionLinks("blog")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(dead) != 1 || dead[0].PostID != "p99" {
+		t.Errorf("Unexpected dead links: %v", dead)
+	}
+}
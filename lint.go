@@ -0,0 +1,159 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type (
+	// LintWarning describes a possible problem in post content.
+	LintWarning struct {
+		// Line is the 1-based line number the warning applies to, or 0 if it
+		// applies to the whole post.
+		Line    int
+		Rule    string
+		Message string
+	}
+
+	// Linter checks post content for problems before publishing.
+	Linter interface {
+		Lint(content string) []LintWarning
+	}
+
+	// LinterFunc is an adapter to allow the use of ordinary functions as
+	// Linters.
+	LinterFunc func(content string) []LintWarning
+)
+
+// Lint calls f(content).
+func (f LinterFunc) Lint(content string) []LintWarning {
+	return f(content)
+}
+
+func (w LintWarning) String() string {
+	if w.Line == 0 {
+		return fmt.Sprintf("%s: %s", w.Rule, w.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", w.Line, w.Rule, w.Message)
+}
+
+var (
+	fenceReg    = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+	headingReg  = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	inlineLink  = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)]*)\)`)
+	refLink     = regexp.MustCompile(`\[([^\]]+)\]\[([^\]]*)\]`)
+	refDef      = regexp.MustCompile(`^\s{0,3}\[([^\]]+)\]:\s*\S+`)
+	unclosedURL = regexp.MustCompile(`\]\([^)]*$`)
+)
+
+// BasicLinter checks for broken Markdown links, unclosed code fences, and
+// duplicate headings.
+var BasicLinter = LinterFunc(basicLint)
+
+func basicLint(content string) []LintWarning {
+	var warnings []LintWarning
+	lines, openFence := markdownLines(content)
+
+	defs := map[string]bool{}
+	for _, l := range lines {
+		if m := refDef.FindStringSubmatch(l.text); !l.code && m != nil {
+			defs[strings.ToLower(m[1])] = true
+		}
+	}
+
+	headings := map[string]int{}
+	for _, l := range lines {
+		if l.code {
+			continue
+		}
+		for _, m := range inlineLink.FindAllStringSubmatch(l.text, -1) {
+			target := strings.TrimSpace(m[2])
+			if target == "" {
+				warnings = append(warnings, LintWarning{l.num, "broken-link", fmt.Sprintf("Link %q has no URL.", m[1])})
+			} else if strings.ContainsAny(strings.Fields(target)[0], "<>") {
+				warnings = append(warnings, LintWarning{l.num, "broken-link", fmt.Sprintf("Link %q has a malformed URL.", m[1])})
+			}
+		}
+		if unclosedURL.MatchString(l.text) {
+			warnings = append(warnings, LintWarning{l.num, "broken-link", "Link URL is missing a closing parenthesis."})
+		}
+		for _, m := range refLink.FindAllStringSubmatch(l.text, -1) {
+			ref := m[2]
+			if ref == "" {
+				ref = m[1]
+			}
+			if !defs[strings.ToLower(ref)] {
+				warnings = append(warnings, LintWarning{l.num, "broken-link", fmt.Sprintf("Reference %q is never defined.", ref)})
+			}
+		}
+		if m := headingReg.FindStringSubmatch(l.text); m != nil {
+			key := strings.ToLower(m[2])
+			if first, ok := headings[key]; ok {
+				warnings = append(warnings, LintWarning{l.num, "duplicate-heading", fmt.Sprintf("Heading %q already used on line %d.", m[2], first)})
+			} else {
+				headings[key] = l.num
+			}
+		}
+	}
+
+	if openFence > 0 {
+		warnings = append(warnings, LintWarning{openFence, "unclosed-fence", "Code fence is never closed."})
+	}
+	return warnings
+}
+
+// mdLine is a single line of Markdown, with whether it's part of a fenced
+// code block (including the fence itself).
+type mdLine struct {
+	num  int
+	text string
+	code bool
+}
+
+// markdownLines splits content into lines, also returning the line number of
+// a code fence that's never closed, or 0.
+func markdownLines(content string) ([]mdLine, int) {
+	raw := strings.Split(strings.Replace(content, "\r\n", "\n", -1), "\n")
+	lines := make([]mdLine, len(raw))
+	fence, openFence := "", 0
+	for i, t := range raw {
+		lines[i] = mdLine{num: i + 1, text: t, code: fence != ""}
+		if m := fenceReg.FindStringSubmatch(t); m != nil {
+			if fence == "" {
+				fence, openFence = m[1], i+1
+				lines[i].code = true
+			} else if fence == m[1] {
+				fence, openFence = "", 0
+			}
+		}
+	}
+	return lines, openFence
+}
+
+// SetLinters sets the Linters used by Lint. By default, only BasicLinter is
+// used.
+func (c *Client) SetLinters(linters ...Linter) {
+	c.linters = linters
+}
+
+// Lint checks the given PostParams' content with the Client's Linters,
+// returning any warnings. It's meant to be called before publishing, so
+// writing tools can surface problems to the author; it never blocks a post.
+// A Linter that panics is reported as a warning with the rule "panic".
+func (c *Client) Lint(sp *PostParams) []LintWarning {
+	linters := c.linters
+	if linters == nil {
+		linters = []Linter{BasicLinter}
+	}
+	var warnings []LintWarning
+	for _, l := range linters {
+		var lw []LintWarning
+		if err := SafeCall("linter", func() { lw = l.Lint(sp.Content) }); err != nil {
+			lw = []LintWarning{{Rule: "panic", Message: err.Error()}}
+		}
+		warnings = append(warnings, lw...)
+	}
+	return warnings
+}
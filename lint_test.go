@@ -0,0 +1,26 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestBasicLinter(t *testing.T) {
+	content := "# Intro\n\nSee [the docs]() and [home][site].\n\n# Intro\n\n```go\n# Not a heading\n"
+
+	warnings := NewClient().Lint(&PostParams{Content: content})
+	rules := map[string]int{}
+	for _, w := range warnings {
+		t.Logf("%s", w)
+		rules[w.Rule]++
+	}
+	if rules["broken-link"] != 2 {
+		t.Errorf("Expected 2 broken-link warnings, got %d", rules["broken-link"])
+	}
+	if rules["duplicate-heading"] != 1 {
+		t.Errorf("Expected 1 duplicate-heading warning, got %d", rules["duplicate-heading"])
+	}
+	if rules["unclosed-fence"] != 1 {
+		t.Errorf("Expected 1 unclosed-fence warning, got %d", rules["unclosed-fence"])
+	}
+}
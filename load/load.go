@@ -0,0 +1,241 @@
+#author: Nguyễn Thái Sơn
+// Package load generates read and write traffic against a WriteFreely
+// instance with the Write.as client, for operators validating an instance's
+// capacity before launch.
+//
+// Only run it against instances you operate. To prevent accidents, it
+// refuses to run against Write.as, requires the instance's host name to be
+// confirmed, caps the request rate and duration, and deletes the posts it
+// creates.
+package load
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/writeas/go-writeas"
+)
+
+// Safeguard limits.
+const (
+	MaxRate        = 200
+	MaxConcurrency = 64
+	MaxDuration    = 30 * time.Minute
+)
+
+// protectedHosts are never load tested.
+var protectedHosts = []string{"write.as", "writeas7pm7rcdqg.onion"}
+
+type (
+	// Config describes a load test.
+	Config struct {
+		// Client talks to the target instance.
+		Client *writeas.Client
+		// Confirm must be the host name of the target instance, like
+		// "blog.example.com", as a guard against testing the wrong one.
+		Confirm string
+
+		// Duration of the test, up to MaxDuration.
+		Duration time.Duration
+		// Rate of requests per second across all workers, up to MaxRate.
+		Rate float64
+		// Concurrency is the number of workers, up to MaxConcurrency.
+		// Defaults to 4.
+		Concurrency int
+
+		// WriteRatio is the fraction of requests, from 0 to 1, that publish
+		// anonymous posts. The rest read posts.
+		WriteRatio float64
+		// PostIDs are read by read requests, along with any posts written
+		// during the test.
+		PostIDs []string
+
+		// Seed seeds the choice between reads and writes.
+		Seed int64
+	}
+
+	// Report summarizes a load test.
+	Report struct {
+		Reads    int
+		Writes   int
+		Errors   int
+		Duration time.Duration
+		// Latency percentiles of successful requests.
+		P50, P95, P99 time.Duration
+		// FirstErrors holds up to 10 of the errors that occurred.
+		FirstErrors []error
+	}
+)
+
+// check enforces the safeguards on the Config, filling in defaults.
+func (cfg *Config) check() error {
+	if cfg.Client == nil {
+		return errors.New("No client given.")
+	}
+	u, err := url.Parse(cfg.Client.BaseURL())
+	if err != nil {
+		return fmt.Errorf("Invalid client URL: %v", err)
+	}
+	host := u.Hostname()
+	for _, h := range protectedHosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return fmt.Errorf("Refusing to load test %s.", host)
+		}
+	}
+	if cfg.Confirm != host {
+		return fmt.Errorf("Confirm must be the target host, %q.", host)
+	}
+	if cfg.Duration <= 0 || cfg.Duration > MaxDuration {
+		return fmt.Errorf("Duration must be between 0 and %s.", MaxDuration)
+	}
+	if cfg.Rate <= 0 || cfg.Rate > MaxRate {
+		return fmt.Errorf("Rate must be between 0 and %d requests per second.", MaxRate)
+	}
+	if cfg.Concurrency == 0 {
+		cfg.Concurrency = 4
+	}
+	if cfg.Concurrency < 0 || cfg.Concurrency > MaxConcurrency {
+		return fmt.Errorf("Concurrency must be between 1 and %d.", MaxConcurrency)
+	}
+	if cfg.WriteRatio < 0 || cfg.WriteRatio > 1 {
+		return errors.New("WriteRatio must be between 0 and 1.")
+	}
+	if cfg.WriteRatio == 0 && len(cfg.PostIDs) == 0 {
+		return errors.New("Reads need PostIDs or some writes.")
+	}
+	return nil
+}
+
+// Run runs the load test described by cfg until its Duration passes or ctx is
+// done, then deletes the posts it created.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if err := cfg.check(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, cfg.Duration)
+	defer cancel()
+
+	r := &runner{cfg: &cfg, ids: append([]string(nil), cfg.PostIDs...), rnd: rand.New(rand.NewSource(cfg.Seed))}
+	ticks := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range ticks {
+				r.request()
+			}
+		}()
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.Rate))
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+			select {
+			case ticks <- struct{}{}:
+			default:
+				// Every worker is busy, so the instance can't keep up
+			}
+		}
+	}
+	ticker.Stop()
+	close(ticks)
+	wg.Wait()
+
+	rep := r.report(time.Since(start))
+	r.cleanUp()
+	return rep, nil
+}
+
+type runner struct {
+	cfg *Config
+
+	mu        sync.Mutex
+	rnd       *rand.Rand
+	ids       []string
+	created   []writeas.PostParams
+	latencies []time.Duration
+	reads     int
+	writes    int
+	errs      []error
+}
+
+func (r *runner) request() {
+	r.mu.Lock()
+	write := r.rnd.Float64() < r.cfg.WriteRatio || len(r.ids) == 0
+	id := ""
+	if !write {
+		id = r.ids[r.rnd.Intn(len(r.ids))]
+	}
+	r.mu.Unlock()
+
+	start := time.Now()
+	var err error
+	var p *writeas.Post
+	if write {
+		p, err = r.cfg.Client.CreatePost(&writeas.PostParams{Title: "Load test", Content: "Load test post, to be deleted."})
+	} else {
+		_, err = r.cfg.Client.GetPost(id)
+	}
+	took := time.Since(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if write {
+		r.writes++
+	} else {
+		r.reads++
+	}
+	if err != nil {
+		r.errs = append(r.errs, err)
+		return
+	}
+	r.latencies = append(r.latencies, took)
+	if p != nil {
+		r.ids = append(r.ids, p.ID)
+		r.created = append(r.created, writeas.PostParams{ID: p.ID, Token: p.Token})
+	}
+}
+
+func (r *runner) report(d time.Duration) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep := &Report{Reads: r.reads, Writes: r.writes, Errors: len(r.errs), Duration: d}
+	if len(r.errs) > 10 {
+		rep.FirstErrors = r.errs[:10]
+	} else {
+		rep.FirstErrors = r.errs
+	}
+	sort.Slice(r.latencies, func(i, j int) bool { return r.latencies[i] < r.latencies[j] })
+	rep.P50 = percentile(r.latencies, 0.50)
+	rep.P95 = percentile(r.latencies, 0.95)
+	rep.P99 = percentile(r.latencies, 0.99)
+	return rep
+}
+
+func (r *runner) cleanUp() {
+	for i := range r.created {
+		r.cfg.Client.DeletePostIdempotent(&r.created[i])
+	}
+}
+
+// percentile returns the pth percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted)-1) * p)
+	return sorted[i]
+}
@@ -0,0 +1,75 @@
+#author: Nguyễn Thái Sơn
+package load
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestRun(t *testing.T) {
+	var mu sync.Mutex
+	posts := map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "POST":
+			id := fmt.Sprintf("p%d", len(posts))
+			posts[id] = true
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"code":201,"data":{"id":%q,"token":"t"}}`, id)
+		case "GET":
+			fmt.Fprintf(w, `{"code":200,"data":{"id":%q}}`, strings.TrimPrefix(r.URL.Path, "/api/posts/"))
+		case "DELETE":
+			delete(posts, strings.TrimPrefix(r.URL.Path, "/api/posts/"))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := writeas.NewClientForInstance(srv.URL, writeas.WithInsecureHTTP())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep, err := Run(context.Background(), Config{
+		Client:     c,
+		Confirm:    "127.0.0.1",
+		Duration:   300 * time.Millisecond,
+		Rate:       100,
+		WriteRatio: 0.5,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rep.Writes == 0 || rep.Reads == 0 || rep.Errors != 0 || rep.P50 == 0 {
+		t.Errorf("Unexpected report: %+v", rep)
+	}
+	if len(posts) != 0 {
+		t.Errorf("Expected created posts to be deleted, %d left", len(posts))
+	}
+}
+
+func TestSafeguards(t *testing.T) {
+	local, _ := writeas.NewClientForInstance("http://localhost:8080", writeas.WithInsecureHTTP())
+	tests := []Config{
+		{Client: writeas.NewClient(), Confirm: "write.as", Duration: time.Second, Rate: 1, WriteRatio: 1},
+		{Client: writeas.NewDevClient(), Confirm: "development.write.as", Duration: time.Second, Rate: 1, WriteRatio: 1},
+		{Client: local, Confirm: "example.com", Duration: time.Second, Rate: 1, WriteRatio: 1},
+		{Client: local, Confirm: "localhost", Duration: time.Hour, Rate: 1, WriteRatio: 1},
+		{Client: local, Confirm: "localhost", Duration: time.Second, Rate: 1000, WriteRatio: 1},
+		{Client: local, Confirm: "localhost", Duration: time.Second, Rate: 1},
+	}
+	for i, cfg := range tests {
+		if _, err := Run(context.Background(), cfg); err == nil {
+			t.Errorf("Expected config %d to be refused", i)
+		}
+	}
+}
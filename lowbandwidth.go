@@ -0,0 +1,43 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import "time"
+
+// LowBandwidthCacheTTL is the least time read responses are cached in
+// low-bandwidth mode.
+const LowBandwidthCacheTTL = 10 * time.Minute
+
+// WithLowBandwidth puts the Client in low-bandwidth mode, for mobile and
+// metered connections. In it, the Client:
+//
+//   - caches read responses for at least LowBandwidthCacheTTL, whatever the
+//     ReadEndpoints Policy's CacheTTL,
+//   - doesn't send hedged requests, whatever its HedgeAfter,
+//   - doesn't check links with GET when HEAD isn't supported, in CheckLinks
+//     and CheckCollectionLinks, reporting the HEAD response instead, and
+//   - doesn't prefetch collections in WarmCache.
+//
+// The API has no way to ask for fewer fields, so responses themselves aren't
+// smaller. Programs built on the Client can check LowBandwidth to skip their
+// own prefetching.
+func WithLowBandwidth() Option {
+	return func(c *Client) {
+		c.lowBandwidth = true
+	}
+}
+
+// LowBandwidth reports whether the Client is in low-bandwidth mode.
+func (c *Client) LowBandwidth() bool {
+	return c.lowBandwidth
+}
+
+// lowBandwidthPolicy returns a copy of the given ReadEndpoints policy
+// adjusted for low-bandwidth mode.
+func lowBandwidthPolicy(ps *policyState) *policyState {
+	lp := *ps
+	if lp.CacheTTL < LowBandwidthCacheTTL {
+		lp.CacheTTL = LowBandwidthCacheTTL
+	}
+	lp.HedgeAfter = 0
+	return &lp
+}
@@ -0,0 +1,34 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLowBandwidth(t *testing.T) {
+	srv := newPolicyServer()
+	defer srv.Close()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClient(WithBaseURL(srv.URL), WithClock(ClockFunc(func() time.Time { return now })),
+		WithLowBandwidth(),
+		WithPolicy(ReadEndpoints, Policy{CacheTTL: time.Minute, HedgeAfter: time.Millisecond}))
+	if !c.LowBandwidth() {
+		t.Fatalf("Expected the Client to be in low-bandwidth mode")
+	}
+
+	ps := c.policy(ReadEndpoints)
+	if ps.CacheTTL != LowBandwidthCacheTTL || ps.HedgeAfter != 0 {
+		t.Errorf("Unexpected low-bandwidth policy: %+v", ps.Policy)
+	}
+	if c.policies[ReadEndpoints].CacheTTL != time.Minute {
+		t.Errorf("Expected the configured policy to be left as-is")
+	}
+
+	c.GetPost("abc")
+	now = now.Add(5 * time.Minute)
+	c.GetPost("abc")
+	if len(srv.bodies) != 1 {
+		t.Errorf("Expected 1 request with the raised cache TTL, got %d", len(srv.bodies))
+	}
+}
@@ -0,0 +1,215 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type (
+	// postDoc is the canonical encoding of a Post, in both JSON and YAML.
+	// Optional fields are pointers, so nil and empty values survive a round
+	// trip, and times are in UTC.
+	postDoc struct {
+		ID         string      `json:"id" yaml:"id"`
+		Slug       string      `json:"slug" yaml:"slug"`
+		URL        string      `json:"url,omitempty" yaml:"url,omitempty"`
+		Token      string      `json:"token" yaml:"token"`
+		Font       string      `json:"appearance" yaml:"appearance"`
+		Language   *string     `json:"language,omitempty" yaml:"language,omitempty"`
+		RTL        *bool       `json:"rtl,omitempty" yaml:"rtl,omitempty"`
+		Listed     bool        `json:"listed" yaml:"listed"`
+		Created    string      `json:"created,omitempty" yaml:"created,omitempty"`
+		Updated    string      `json:"updated,omitempty" yaml:"updated,omitempty"`
+		Title      string      `json:"title" yaml:"title"`
+		Content    string      `json:"body" yaml:"body"`
+		Views      int64       `json:"views" yaml:"views"`
+		Tags       *[]string   `json:"tags,omitempty" yaml:"tags,omitempty"`
+		Images     *[]string   `json:"images,omitempty" yaml:"images,omitempty"`
+		OwnerName  string      `json:"owner,omitempty" yaml:"owner,omitempty"`
+		Collection *Collection `json:"collection,omitempty" yaml:"collection,omitempty"`
+	}
+
+	// paramsDoc is the canonical YAML encoding of PostParams. Unlike the JSON
+	// sent to the API, it includes the ID and Collection.
+	paramsDoc struct {
+		ID         string              `yaml:"id,omitempty"`
+		Token      string              `yaml:"token,omitempty"`
+		Collection string              `yaml:"collection,omitempty"`
+		Slug       string              `yaml:"slug,omitempty"`
+		Title      string              `yaml:"title,omitempty"`
+		Content    string              `yaml:"body,omitempty"`
+		Font       string              `yaml:"font,omitempty"`
+		IsRTL      *bool               `yaml:"rtl,omitempty"`
+		Language   *string             `yaml:"lang,omitempty"`
+		Crosspost  []map[string]string `yaml:"crosspost,omitempty"`
+		Created    string              `yaml:"created,omitempty"`
+	}
+)
+
+// MarshalJSON encodes a Post canonically: times are in UTC and omitted when
+// zero, and Language, RTL, Tags, and Images are omitted only when nil, so
+// decoding the result gives back an identical Post.
+func (p Post) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newPostDoc(&p))
+}
+
+// MarshalYAML encodes a Post with the same fields and guarantees as
+// MarshalJSON. It implements the Marshaler interface of the common YAML
+// packages.
+func (p Post) MarshalYAML() (interface{}, error) {
+	return newPostDoc(&p), nil
+}
+
+// UnmarshalYAML decodes a Post encoded by MarshalYAML. It implements the
+// Unmarshaler interface of the common YAML packages.
+func (p *Post) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	doc := &postDoc{}
+	if err := unmarshal(doc); err != nil {
+		return err
+	}
+	created, err := parseDocTime(doc.Created)
+	if err != nil {
+		return err
+	}
+	updated, err := parseDocTime(doc.Updated)
+	if err != nil {
+		return err
+	}
+	*p = Post{
+		ID:         doc.ID,
+		Slug:       doc.Slug,
+		URL:        doc.URL,
+		Token:      doc.Token,
+		Font:       doc.Font,
+		Language:   doc.Language,
+		RTL:        doc.RTL,
+		Listed:     doc.Listed,
+		Created:    created,
+		Updated:    updated,
+		Title:      doc.Title,
+		Content:    doc.Content,
+		Views:      doc.Views,
+		OwnerName:  doc.OwnerName,
+		Collection: doc.Collection,
+	}
+	if doc.Tags != nil {
+		p.Tags = *doc.Tags
+	}
+	if doc.Images != nil {
+		p.Images = *doc.Images
+	}
+	return nil
+}
+
+// apiTimeLayout is the layout the API accepts for a post's creation time,
+// which has no fractional seconds.
+const apiTimeLayout = "2006-01-02T15:04:05Z"
+
+// MarshalJSON encodes PostParams as the API expects them, with Created in
+// UTC, to the second. IsRTL and Language are omitted only when nil.
+func (sp PostParams) MarshalJSON() ([]byte, error) {
+	type params PostParams
+	aux := struct {
+		params
+		Created *string `json:"created,omitempty"`
+	}{params: params(sp)}
+	if sp.Created != nil {
+		created := sp.Created.UTC().Truncate(time.Second).Format(apiTimeLayout)
+		aux.Created = &created
+	}
+	return json.Marshal(aux)
+}
+
+// MarshalYAML encodes PostParams canonically, including the ID and
+// Collection that aren't part of their JSON encoding. It implements the
+// Marshaler interface of the common YAML packages.
+func (sp PostParams) MarshalYAML() (interface{}, error) {
+	doc := &paramsDoc{
+		ID:         sp.ID,
+		Token:      sp.Token,
+		Collection: sp.Collection,
+		Slug:       sp.Slug,
+		Title:      sp.Title,
+		Content:    sp.Content,
+		Font:       sp.Font,
+		IsRTL:      sp.IsRTL,
+		Language:   sp.Language,
+		Crosspost:  sp.Crosspost,
+	}
+	if sp.Created != nil {
+		doc.Created = formatDocTime(*sp.Created)
+	}
+	return doc, nil
+}
+
+// UnmarshalYAML decodes PostParams encoded by MarshalYAML. It implements the
+// Unmarshaler interface of the common YAML packages.
+func (sp *PostParams) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	doc := &paramsDoc{}
+	if err := unmarshal(doc); err != nil {
+		return err
+	}
+	*sp = PostParams{
+		ID:         doc.ID,
+		Token:      doc.Token,
+		Collection: doc.Collection,
+		Slug:       doc.Slug,
+		Title:      doc.Title,
+		Content:    doc.Content,
+		Font:       doc.Font,
+		IsRTL:      doc.IsRTL,
+		Language:   doc.Language,
+		Crosspost:  doc.Crosspost,
+	}
+	if doc.Created != "" {
+		created, err := ParseTimestamp(doc.Created)
+		if err != nil {
+			return err
+		}
+		sp.Created = &created
+	}
+	return nil
+}
+
+func newPostDoc(p *Post) *postDoc {
+	doc := &postDoc{
+		ID:         p.ID,
+		Slug:       p.Slug,
+		URL:        p.URL,
+		Token:      p.Token,
+		Font:       p.Font,
+		Language:   p.Language,
+		RTL:        p.RTL,
+		Listed:     p.Listed,
+		Created:    formatDocTime(p.Created),
+		Updated:    formatDocTime(p.Updated),
+		Title:      p.Title,
+		Content:    p.Content,
+		Views:      p.Views,
+		OwnerName:  p.OwnerName,
+		Collection: p.Collection,
+	}
+	if p.Tags != nil {
+		doc.Tags = &p.Tags
+	}
+	if p.Images != nil {
+		doc.Images = &p.Images
+	}
+	return doc
+}
+
+// formatDocTime formats t in UTC, or returns "" for the zero time.
+func formatDocTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseDocTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return ParseTimestamp(s)
+}
@@ -0,0 +1,172 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+func goldenPost() *Post {
+	lang := ""
+	rtl := false
+	return &Post{
+		ID:       "rf3t35fkax0aw",
+		Slug:     "my-first-post",
+		Font:     "norm",
+		Language: &lang,
+		RTL:      &rtl,
+		Listed:   true,
+		Created:  time.Date(2018, 3, 2, 15, 4, 5, 123000000, time.UTC),
+		Title:    "My First Post",
+		Content:  "Hello, world.\n",
+		Views:    42,
+		Tags:     []string{},
+	}
+}
+
+// checkGolden compares got with the named file in testdata, rewriting it
+// instead when run with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("Unexpected %s:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestPostJSONGolden(t *testing.T) {
+	p := goldenPost()
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "post.golden.json", append(data, '\n'))
+
+	decoded := &Post{}
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, p) {
+		t.Errorf("Unexpected round trip:\n%+v\nwant:\n%+v", decoded, p)
+	}
+
+	// nil optional fields must stay nil, not become empty.
+	data, _ = json.Marshal(Post{ID: "abc"})
+	decoded = &Post{}
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Language != nil || decoded.RTL != nil || decoded.Tags != nil || decoded.Images != nil {
+		t.Errorf("Unexpected non-nil fields: %+v", decoded)
+	}
+}
+
+// yamlRoundTrip passes v through its MarshalYAML and UnmarshalYAML methods, the
+// way a YAML package would, using JSON for the intermediate document.
+func yamlRoundTrip(t *testing.T, in interface {
+	MarshalYAML() (interface{}, error)
+}, out interface {
+	UnmarshalYAML(func(interface{}) error) error
+}) {
+	doc, err := in.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = out.UnmarshalYAML(func(v interface{}) error {
+		return json.Unmarshal(data, v)
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostYAMLRoundTrip(t *testing.T) {
+	p := goldenPost()
+	decoded := &Post{}
+	yamlRoundTrip(t, p, decoded)
+	if !reflect.DeepEqual(decoded, p) {
+		t.Errorf("Unexpected round trip:\n%+v\nwant:\n%+v", decoded, p)
+	}
+}
+
+func TestPostParamsRoundTrip(t *testing.T) {
+	lang := "ar"
+	rtl := true
+	created := time.Date(2018, 3, 2, 10, 4, 5, 0, time.FixedZone("EST", -5*60*60))
+	sp := &PostParams{
+		ID:         "rf3t35fkax0aw",
+		Collection: "blog",
+		Title:      "Title",
+		Content:    "Body",
+		IsRTL:      &rtl,
+		Language:   &lang,
+		Created:    &created,
+	}
+
+	data, err := json.Marshal(sp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "params.golden.json", append(data, '\n'))
+
+	decoded := &PostParams{}
+	yamlRoundTrip(t, sp, decoded)
+	if !decoded.Created.Equal(created) {
+		t.Errorf("Unexpected created time: %s", decoded.Created)
+	}
+	decoded.Created = sp.Created
+	if !reflect.DeepEqual(decoded, sp) {
+		t.Errorf("Unexpected round trip:\n%+v\nwant:\n%+v", decoded, sp)
+	}
+}
+
+func TestPostParamsCreatedSeconds(t *testing.T) {
+	created := time.Date(2018, 3, 2, 10, 4, 5, 999999999, time.FixedZone("EST", -5*60*60))
+	data, err := json.Marshal(&PostParams{Content: "Body", Created: &created})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"created":"2018-03-02T15:04:05Z"`) {
+		t.Errorf("Unexpected JSON: %s", data)
+	}
+}
+
+func TestFrontMatterRoundTrip(t *testing.T) {
+	p := goldenPost()
+	p.Created = p.Created.Truncate(time.Second)
+	p.Tags = []string{"go", "writing \"well\""}
+	doc := FrontMatter(p) + p.Content
+	checkGolden(t, "post.golden.md", []byte(doc))
+
+	decoded, err := ParseFrontMatter(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded.Font, decoded.Listed, decoded.Views = p.Font, p.Listed, p.Views
+	if !reflect.DeepEqual(decoded, p) {
+		t.Errorf("Unexpected round trip:\n%+v\nwant:\n%+v", decoded, p)
+	}
+
+	if _, err = ParseFrontMatter("No front matter"); err == nil {
+		t.Errorf("Expected an error for missing front matter")
+	}
+}
@@ -0,0 +1,84 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The Write.as API has no per-post or per-collection math setting: math is
+// rendered by MathJax when a post's content contains LaTeX delimiters. These
+// helpers make sure those blocks reach the renderer intact.
+
+var (
+	mathReg     = regexp.MustCompile(`(?s)\$\$.+?\$\$|\\\[.+?\\\]|\\\(.+?\\\)|\$[^\s$](?:[^$\n]*?[^\s$])?\$`)
+	codeSpanReg = regexp.MustCompile("`[^`\n]+`")
+)
+
+// HasMath reports whether the given content contains LaTeX math blocks,
+// outside of code.
+func HasMath(content string) bool {
+	found := false
+	eachProse(content, func(s string) string {
+		if mathReg.MatchString(s) {
+			found = true
+		}
+		return s
+	})
+	return found
+}
+
+// EscapeMath escapes Markdown-significant characters (\, _, and *) inside
+// LaTeX math blocks, so emphasis and backslash escapes don't mangle them
+// before MathJax sees them. Code blocks and spans are left untouched.
+func EscapeMath(content string) string {
+	return eachProse(content, func(s string) string {
+		return mathReg.ReplaceAllStringFunc(s, escapeMarkdown)
+	})
+}
+
+// EscapeMathFilter is a Filter that runs EscapeMath, for use with SetFilters.
+var EscapeMathFilter = FilterFunc(func(content string) (string, error) {
+	return EscapeMath(content), nil
+})
+
+var mdEscaper = strings.NewReplacer(`\`, `\\`, `_`, `\_`, `*`, `\*`)
+
+func escapeMarkdown(s string) string {
+	return mdEscaper.Replace(s)
+}
+
+// eachProse applies f to the parts of Markdown content outside of fenced code
+// blocks and code spans.
+func eachProse(content string, f func(string) string) string {
+	lines, _ := markdownLines(content)
+
+	var out, prose []string
+	flush := func() {
+		if len(prose) == 0 {
+			return
+		}
+		block := strings.Join(prose, "\n")
+		spans := codeSpanReg.FindAllStringIndex(block, -1)
+		var b strings.Builder
+		last := 0
+		for _, sp := range spans {
+			b.WriteString(f(block[last:sp[0]]))
+			b.WriteString(block[sp[0]:sp[1]])
+			last = sp[1]
+		}
+		b.WriteString(f(block[last:]))
+		out = append(out, b.String())
+		prose = prose[:0]
+	}
+	for _, l := range lines {
+		if l.code {
+			flush()
+			out = append(out, l.text)
+		} else {
+			prose = append(prose, l.text)
+		}
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,20 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestEscapeMath(t *testing.T) {
+	content := "Energy: $E = m_1 c^2$ and\n\n$$\\sum_{i=1}^n a_i * b_i \\\\ x$$\n\n```\n$a_b$\n```\n\nAlso `$x_y$` and snake_case."
+	expected := "Energy: $E = m\\_1 c^2$ and\n\n$$\\\\sum\\_{i=1}^n a\\_i \\* b\\_i \\\\\\\\ x$$\n\n```\n$a_b$\n```\n\nAlso `$x_y$` and snake_case."
+	if res := EscapeMath(content); res != expected {
+		t.Errorf("Unexpected escaped content:\n%s", res)
+	}
+	if !HasMath(content) {
+		t.Errorf("Expected math in content")
+	}
+	if HasMath("Costs $5 and $10.") {
+		t.Errorf("Expected prices not to be math")
+	}
+}
@@ -0,0 +1,234 @@
+#author: Nguyễn Thái Sơn
+// Package matrix bridges a Matrix room to Write.as, publishing messages that
+// start with a command prefix as posts and replying with their URLs.
+package matrix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/writeas/go-writeas"
+)
+
+// DefaultPrefix is the command prefix used when Bridge.Prefix isn't set.
+const DefaultPrefix = "!publish"
+
+// Bridge listens in a Matrix room and publishes messages starting with Prefix.
+// The first line of a message becomes the post title if it's a Markdown
+// heading.
+type Bridge struct {
+	Client     *writeas.Client
+	Collection string
+
+	// Homeserver is the base URL of the Matrix homeserver, e.g.
+	// "https://matrix.org".
+	Homeserver  string
+	AccessToken string
+	RoomID      string
+	Prefix      string
+
+	// HTTPClient is used for requests to the homeserver. Defaults to a client
+	// with a timeout suitable for long-polling.
+	HTTPClient *http.Client
+	// OnError is called with errors that occur while handling messages. It
+	// may be nil.
+	OnError func(error)
+
+	run writeas.RunState
+}
+
+// replyTimeout bounds how long replies to published messages are given to
+// send once the Bridge is closing.
+const replyTimeout = 30 * time.Second
+
+type syncResponse struct {
+	NextBatch string `json:"next_batch"`
+	Rooms     struct {
+		Join map[string]struct {
+			Timeline struct {
+				Events []event `json:"events"`
+			} `json:"timeline"`
+		} `json:"join"`
+	} `json:"rooms"`
+}
+
+type event struct {
+	Type    string `json:"type"`
+	EventID string `json:"event_id"`
+	Sender  string `json:"sender"`
+	Content struct {
+		MsgType string `json:"msgtype"`
+		Body    string `json:"body"`
+	} `json:"content"`
+}
+
+// Run syncs with the homeserver until ctx is done or Close is called, handling
+// only messages sent after it starts.
+func (b *Bridge) Run(ctx context.Context) error {
+	ctx, err := b.run.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer b.run.End()
+
+	since, err := b.sync(ctx, "", 0)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		next, err := b.sync(ctx, since.NextBatch, 30*time.Second)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			b.reportError(err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		if room, ok := next.Rooms.Join[b.RoomID]; ok {
+			for _, ev := range room.Timeline.Events {
+				b.handleEvent(ev)
+			}
+		}
+		since = next
+	}
+}
+
+// Close stops Run, waiting for messages already received to be published and
+// replied to first.
+func (b *Bridge) Close() error {
+	return b.run.Close()
+}
+
+// handleEvent publishes the message in the event, if it's a publish command.
+// It isn't interrupted by the Bridge closing, so received messages are never
+// lost.
+func (b *Bridge) handleEvent(ev event) {
+	if ev.Type != "m.room.message" || ev.Content.MsgType != "m.text" {
+		return
+	}
+	sp, ok := b.postParams(ev.Content.Body)
+	if !ok {
+		return
+	}
+
+	p, err := b.Client.CreatePost(sp)
+	reply := ""
+	if err != nil {
+		reply = fmt.Sprintf("Unable to publish: %v", err)
+	} else {
+		reply = "Published: " + p.URL
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), replyTimeout)
+	defer cancel()
+	if err = b.send(ctx, reply); err != nil {
+		b.reportError(err)
+	}
+}
+
+// postParams returns the post to publish for the given message body, if it's
+// a publish command.
+func (b *Bridge) postParams(body string) (*writeas.PostParams, bool) {
+	prefix := b.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	if !strings.HasPrefix(body, prefix) {
+		return nil, false
+	}
+	content := strings.TrimSpace(body[len(prefix):])
+	if content == "" {
+		return nil, false
+	}
+
+	sp := &writeas.PostParams{Content: content, Collection: b.Collection}
+	if lines := strings.SplitN(content, "\n", 2); strings.HasPrefix(lines[0], "# ") {
+		sp.Title = strings.TrimSpace(lines[0][2:])
+		sp.Content = ""
+		if len(lines) > 1 {
+			sp.Content = strings.TrimSpace(lines[1])
+		}
+	}
+	return sp, true
+}
+
+func (b *Bridge) sync(ctx context.Context, since string, timeout time.Duration) (*syncResponse, error) {
+	q := url.Values{}
+	q.Set("timeout", strconv.FormatInt(int64(timeout/time.Millisecond), 10))
+	q.Set("filter", fmt.Sprintf(`{"room":{"rooms":[%q],"timeline":{"types":["m.room.message"]}}}`, b.RoomID))
+	if since != "" {
+		q.Set("since", since)
+	}
+
+	res := &syncResponse{}
+	err := b.do(ctx, "GET", "/_matrix/client/v3/sync?"+q.Encode(), nil, res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (b *Bridge) send(ctx context.Context, msg string) error {
+	path := fmt.Sprintf("/_matrix/client/v3/rooms/%s/send/m.room.message/writeas-%s",
+		url.PathEscape(b.RoomID), b.Client.NewID())
+	return b.do(ctx, "PUT", path, map[string]string{
+		"msgtype": "m.notice",
+		"body":    msg,
+	}, nil)
+}
+
+func (b *Bridge) do(ctx context.Context, method, path string, data, result interface{}) error {
+	var body bytes.Buffer
+	if data != nil {
+		json.NewEncoder(&body).Encode(data)
+	}
+	r, err := http.NewRequest(method, strings.TrimRight(b.Homeserver, "/")+path, &body)
+	if err != nil {
+		return fmt.Errorf("Create request: %v", err)
+	}
+	r = r.WithContext(ctx)
+	r.Header.Set("Authorization", "Bearer "+b.AccessToken)
+	r.Header.Set("Content-Type", "application/json")
+
+	hc := b.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: time.Minute}
+	}
+	resp, err := hc.Do(r)
+	if err != nil {
+		return fmt.Errorf("Request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var merr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&merr)
+		return fmt.Errorf("Matrix error: %d. %s", resp.StatusCode, merr.Error)
+	}
+	if result != nil {
+		return json.NewDecoder(resp.Body).Decode(result)
+	}
+	return nil
+}
+
+func (b *Bridge) reportError(err error) {
+	writeas.ReportError(b.OnError, err)
+}
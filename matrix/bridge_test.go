@@ -0,0 +1,93 @@
+#author: Nguyễn Thái Sơn
+package matrix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestPostParams(t *testing.T) {
+	b := &Bridge{Collection: "team"}
+
+	if _, ok := b.postParams("Just chatting"); ok {
+		t.Errorf("Expected non-command message to be ignored")
+	}
+
+	sp, ok := b.postParams("!publish # Standup notes\nWe shipped it.")
+	if !ok {
+		t.Fatalf("Expected command message to be published")
+	}
+	if sp.Title != "Standup notes" || sp.Content != "We shipped it." || sp.Collection != "team" {
+		t.Errorf("Unexpected params: %+v", sp)
+	}
+}
+
+func TestClose(t *testing.T) {
+	b := &Bridge{}
+	if err := b.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := b.Run(context.Background()); err != writeas.ErrClosed {
+		t.Errorf("Expected closed bridge not to run, got %v", err)
+	}
+}
+
+func TestHandleEventReplyURL(t *testing.T) {
+	var reply string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/_matrix/") {
+			var msg map[string]string
+			json.NewDecoder(r.Body).Decode(&msg)
+			reply = msg["body"]
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"code":201,"data":{"id":"abc","slug":"standup"}}`))
+	}))
+	defer srv.Close()
+
+	b := &Bridge{
+		Client:     writeas.NewClient(writeas.WithBaseURL(srv.URL + "/api")),
+		Collection: "team",
+		Homeserver: srv.URL,
+	}
+	ev := event{Type: "m.room.message"}
+	ev.Content.MsgType = "m.text"
+	ev.Content.Body = "!publish Standup"
+	b.handleEvent(ev)
+	if reply != "Published: "+srv.URL+"/team/standup" {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+}
+
+func TestRunAlreadyRunning(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	b := &Bridge{Homeserver: srv.URL}
+	errs := make(chan error)
+	go func() {
+		errs <- b.Run(context.Background())
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	if err := b.Run(context.Background()); err == nil || err == writeas.ErrClosed {
+		t.Errorf("Expected a second Run to fail, got %v", err)
+	}
+	b.Close()
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Run to have returned after Close")
+	}
+}
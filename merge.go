@@ -0,0 +1,76 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"strings"
+)
+
+// Conflict markers written by Merge3 around lines changed differently on each
+// side.
+const (
+	conflictLocal  = "<<<<<<< local"
+	conflictSep    = "======="
+	conflictRemote = ">>>>>>> remote"
+)
+
+// Merge3 merges the local and remote versions of post content, both changed
+// from a common base, line by line. Changes made on only one side are kept.
+// Where both sides changed the same lines differently, the merged content
+// contains both versions between conflict markers and conflict is true.
+func Merge3(base, local, remote string) (merged string, conflict bool) {
+	o, a, b := splitLines(base), splitLines(local), splitLines(remote)
+	ma, mb := lcsMatches(o, a), lcsMatches(o, b)
+
+	var out []string
+	i, ai, bi := 0, 0, 0
+	for i <= len(o) {
+		// Find the next base line kept on both sides
+		j := i
+		for j < len(o) && (ma[j] < 0 || mb[j] < 0) {
+			j++
+		}
+		aj, bj := len(a), len(b)
+		if j < len(o) {
+			aj, bj = ma[j], mb[j]
+		}
+
+		oc, ac, bc := o[i:j], a[ai:aj], b[bi:bj]
+		switch {
+		case linesEqual(ac, oc):
+			out = append(out, bc...)
+		case linesEqual(bc, oc), linesEqual(ac, bc):
+			out = append(out, ac...)
+		default:
+			conflict = true
+			out = append(out, conflictLocal)
+			out = append(out, ac...)
+			out = append(out, conflictSep)
+			out = append(out, bc...)
+			out = append(out, conflictRemote)
+		}
+
+		if j == len(o) {
+			break
+		}
+		out = append(out, o[j])
+		i, ai, bi = j+1, aj+1, bj+1
+	}
+
+	merged = strings.Join(out, "\n")
+	if len(out) > 0 && (strings.HasSuffix(local, "\n") || strings.HasSuffix(remote, "\n")) {
+		merged += "\n"
+	}
+	return merged, conflict
+}
+
+func linesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	base := "one\ntwo\nthree\nfour\n"
+
+	merged, conflict := Merge3(base, "one\n2\nthree\nfour\n", "one\ntwo\nthree\nfour\nfive\n")
+	if conflict || merged != "one\n2\nthree\nfour\nfive\n" {
+		t.Errorf("Unexpected merge: %q, %t", merged, conflict)
+	}
+
+	merged, conflict = Merge3(base, "one\ntwo\n3\nfour\n", "one\ntwo\nTHREE\nfour\n")
+	expected := "one\ntwo\n<<<<<<< local\n3\n=======\nTHREE\n>>>>>>> remote\nfour\n"
+	if !conflict || merged != expected {
+		t.Errorf("Unexpected merge: %q, %t", merged, conflict)
+	}
+
+	merged, conflict = Merge3(base, "one\nfour\n", "one\nfour\n")
+	if conflict || merged != "one\nfour\n" {
+		t.Errorf("Unexpected merge: %q, %t", merged, conflict)
+	}
+}
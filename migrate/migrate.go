@@ -0,0 +1,167 @@
+#author: Nguyễn Thái Sơn
+// Package migrate copies a user's collections and posts from one account to
+// another, like from Write.as to a self-hosted WriteFreely instance or back.
+package migrate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/writeas/go-writeas"
+)
+
+// Kinds of items copied, reported in Events.
+const (
+	KindCollection = "collection"
+	KindPost       = "post"
+	KindPin        = "pin"
+)
+
+type (
+	// Migrator copies collections and posts between two authenticated
+	// Clients. Posts keep their slugs and creation dates, so running it again
+	// updates the posts it already copied instead of duplicating them.
+	// Anonymous posts, which have no slug, are copied every time.
+	Migrator struct {
+		From *writeas.Client
+		To   *writeas.Client
+
+		// Pins lists the slugs of posts to pin in each collection, by alias,
+		// in order. If it's nil, the pins recorded in From's PinStore are
+		// copied.
+		Pins map[string][]string
+
+		// Progress is called after each item is copied. It may be nil. If it
+		// panics, Run stops and returns a *writeas.PanicError.
+		Progress func(Event)
+	}
+
+	// Event reports the progress of a migration.
+	Event struct {
+		Kind string
+		// Name is the alias of a collection, or a post's "alias/slug" or
+		// ID.
+		Name  string
+		Done  int
+		Total int
+	}
+
+	// Result counts what was copied.
+	Result struct {
+		Collections int
+		Posts       int
+		Pins        int
+	}
+)
+
+// Run copies every collection and post of the From account to the To account,
+// then pins posts as given in Pins, recording them in To's PinStore.
+func (m *Migrator) Run() (*Result, error) {
+	colls, err := m.From.GetUserCollections()
+	if err != nil {
+		return nil, fmt.Errorf("Get collections: %v", err)
+	}
+	posts, err := m.From.GetUserPosts()
+	if err != nil {
+		return nil, fmt.Errorf("Get posts: %v", err)
+	}
+	sort.SliceStable(*posts, func(i, j int) bool { return (*posts)[i].Created.Before((*posts)[j].Created) })
+	pins := m.Pins
+	if pins == nil {
+		pins = map[string][]string{}
+		for _, coll := range *colls {
+			if pins[coll.Alias], err = m.From.Pinned(coll.Alias); err != nil {
+				return nil, fmt.Errorf("Get pins: %v", err)
+			}
+		}
+	}
+
+	total := len(*colls) + len(*posts)
+	for _, slugs := range pins {
+		total += len(slugs)
+	}
+	res := &Result{}
+	done := 0
+	progress := func(kind, name string) error {
+		done++
+		if m.Progress == nil {
+			return nil
+		}
+		return writeas.SafeCall("Progress", func() {
+			m.Progress(Event{Kind: kind, Name: name, Done: done, Total: total})
+		})
+	}
+
+	for _, coll := range *colls {
+		_, _, err := m.To.EnsureCollection(&writeas.CollectionParams{
+			Alias:       coll.Alias,
+			Title:       coll.Title,
+			Description: coll.Description,
+			StyleSheet:  coll.StyleSheet,
+		})
+		if err != nil {
+			return res, fmt.Errorf("Collection %s: %v", coll.Alias, err)
+		}
+		res.Collections++
+		if err := progress(KindCollection, coll.Alias); err != nil {
+			return res, err
+		}
+	}
+
+	for i := range *posts {
+		p := &(*posts)[i]
+		name, err := m.copyPost(p)
+		if err != nil {
+			return res, fmt.Errorf("Post %s: %v", name, err)
+		}
+		res.Posts++
+		if err := progress(KindPost, name); err != nil {
+			return res, err
+		}
+	}
+
+	aliases := make([]string, 0, len(pins))
+	for alias := range pins {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		if len(pins[alias]) == 0 {
+			continue
+		}
+		if err := m.To.SetPinned(alias, pins[alias]); err != nil {
+			return res, fmt.Errorf("Pin %s: %v", alias, err)
+		}
+		for _, slug := range pins[alias] {
+			res.Pins++
+			if err := progress(KindPin, alias+"/"+slug); err != nil {
+				return res, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// copyPost copies the post to the To account, returning its name.
+func (m *Migrator) copyPost(p *writeas.Post) (string, error) {
+	sp := &writeas.PostParams{
+		Title:    p.Title,
+		Content:  p.Content,
+		Font:     p.Font,
+		IsRTL:    p.RTL,
+		Language: p.Language,
+	}
+	if !p.Created.IsZero() {
+		created := p.Created
+		sp.Created = &created
+	}
+
+	if p.Collection == nil || p.Slug == "" {
+		_, err := m.To.CreatePost(sp)
+		return p.ID, err
+	}
+	sp.Collection = p.Collection.Alias
+	sp.Slug = p.Slug
+	_, _, err := m.To.EnsurePost(sp)
+	return p.Collection.Alias + "/" + p.Slug, err
+}
@@ -0,0 +1,110 @@
+#author: Nguyễn Thái Sơn
+package migrate
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/writeas/go-writeas"
+)
+
+func respond(w http.ResponseWriter, code int, data interface{}) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "data": data})
+}
+
+func TestMigrate(t *testing.T) {
+	created := time.Date(2019, 4, 1, 7, 30, 0, 0, time.UTC)
+	from := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me/collections":
+			respond(w, http.StatusOK, []writeas.Collection{{Alias: "blog", Title: "Blog"}})
+		case "/me/posts":
+			respond(w, http.StatusOK, []writeas.Post{
+				{ID: "p1", Slug: "hello", Title: "Hello", Content: "Hi.", Created: created, Collection: &writeas.Collection{Alias: "blog"}},
+			})
+		}
+	}))
+	defer from.Close()
+
+	var posted []writeas.PostParams
+	var pinned []writeas.PinnedPostParams
+	to := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/collections/blog":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404}`))
+		case r.URL.Path == "/collections":
+			respond(w, http.StatusCreated, writeas.Collection{Alias: "blog", Title: "Blog"})
+		case r.Method == "GET" && r.URL.Path == "/collections/blog/posts/hello":
+			if len(posted) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code":404}`))
+				return
+			}
+			respond(w, http.StatusOK, writeas.Post{ID: "new", Slug: "hello", Title: "Hello", Content: "Hi."})
+		case r.URL.Path == "/collections/blog/posts":
+			sp := writeas.PostParams{}
+			json.NewDecoder(r.Body).Decode(&sp)
+			posted = append(posted, sp)
+			respond(w, http.StatusCreated, writeas.Post{ID: "new", Slug: sp.Slug})
+		case r.URL.Path == "/collections/blog/pin":
+			json.NewDecoder(r.Body).Decode(&pinned)
+			respond(w, http.StatusOK, []writeas.BatchPostResult{{ID: "new", Code: http.StatusOK}})
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer to.Close()
+
+	var events []string
+	m := &Migrator{
+		From: writeas.NewClient(writeas.WithBaseURL(from.URL)),
+		To:   writeas.NewClient(writeas.WithBaseURL(to.URL)),
+		Pins: map[string][]string{"blog": {"hello"}},
+		Progress: func(e Event) {
+			events = append(events, e.Kind+" "+e.Name)
+			if e.Total != 3 {
+				t.Errorf("Unexpected total: %d", e.Total)
+			}
+		},
+	}
+	res, err := m.Run()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.Collections != 1 || res.Posts != 1 || res.Pins != 1 {
+		t.Errorf("Unexpected result: %+v", res)
+	}
+	if len(posted) != 1 || posted[0].Slug != "hello" || !posted[0].Created.Equal(created) {
+		t.Errorf("Unexpected posts: %+v", posted)
+	}
+	if len(pinned) != 1 || pinned[0].ID != "new" || pinned[0].Position != 1 {
+		t.Errorf("Unexpected pins: %+v", pinned)
+	}
+	if strings.Join(events, ", ") != "collection blog, post blog/hello, pin blog/hello" {
+		t.Errorf("Unexpected events: %v", events)
+	}
+
+	// Without Pins, the pins recorded by From are copied
+	pins := &writeas.MemoryPinStore{}
+	pins.SavePins("blog", []string{"hello"})
+	pinned = nil
+	m = &Migrator{
+		From: writeas.NewClient(writeas.WithBaseURL(from.URL), writeas.WithPinStore(pins)),
+		To:   writeas.NewClient(writeas.WithBaseURL(to.URL)),
+	}
+	if res, err = m.Run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.Pins != 1 || len(pinned) != 1 || pinned[0].ID != "new" {
+		t.Errorf("Unexpected pins: %+v, %+v", res, pinned)
+	}
+	if slugs, _ := m.To.Pinned("blog"); len(slugs) != 1 || slugs[0] != "hello" {
+		t.Errorf("Unexpected recorded pins: %v", slugs)
+	}
+}
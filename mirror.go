@@ -0,0 +1,249 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// Mirror polls an external RSS or Atom feed and republishes new entries
+	// into a collection, linking back to the original.
+	Mirror struct {
+		Client     *Client
+		FeedURL    string
+		Collection string
+		Interval   time.Duration
+
+		// Seen records which entries have already been published. Defaults to
+		// an in-memory store, which forgets entries between restarts.
+		Seen SeenStore
+
+		// OnPublish is called for each published entry. It may be nil. If it
+		// panics, Poll stops and returns a *PanicError.
+		OnPublish func(FeedEntry, *Post)
+		// OnError is called with errors that occur while polling in Run. It
+		// may be nil.
+		OnError func(error)
+
+		run RunState
+	}
+
+	// SeenStore records the IDs of feed entries that have been published.
+	SeenStore interface {
+		Seen(id string) (bool, error)
+		MarkSeen(id string) error
+	}
+
+	// MemorySeenStore is a SeenStore that keeps IDs in memory.
+	MemorySeenStore struct {
+		mu  sync.Mutex
+		ids map[string]bool
+	}
+
+	// FileSeenStore is a SeenStore that keeps IDs in a file, one per line.
+	FileSeenStore struct {
+		Path string
+
+		mu  sync.Mutex
+		ids map[string]bool
+	}
+)
+
+// DefaultMirrorInterval is the polling interval used when a Mirror's Interval
+// isn't set.
+const DefaultMirrorInterval = 15 * time.Minute
+
+// Poll fetches the feed once and publishes any entries not yet seen, oldest
+// first by their published date, returning the number published. If an entry
+// can't be marked seen, its post is deleted again, so it isn't published twice.
+func (m *Mirror) Poll() (int, error) {
+	if m.Seen == nil {
+		m.Seen = &MemorySeenStore{}
+	}
+
+	r, err := http.NewRequest("GET", m.FeedURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	r.Header.Set("User-Agent", m.Client.userAgent())
+	resp, err := m.Client.client.Do(r)
+	if err != nil {
+		return 0, fmt.Errorf("Fetch feed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("Fetch feed: %s", resp.Status)
+	}
+
+	entries, err := ParseFeed(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	// Feeds are usually newest first, so reverse them before sorting, to
+	// keep entries without dates oldest first too
+	sorted := make([]FeedEntry, len(entries))
+	for i, e := range entries {
+		sorted[len(entries)-1-i] = e
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Published.Before(sorted[j].Published)
+	})
+
+	n := 0
+	for _, e := range sorted {
+		seen, err := m.Seen.Seen(e.ID)
+		if err != nil {
+			return n, err
+		}
+		if seen {
+			continue
+		}
+
+		p, err := m.Client.CreatePost(m.postParams(e))
+		if err != nil {
+			return n, err
+		}
+		if err = m.Seen.MarkSeen(e.ID); err != nil {
+			// Unpublish the entry, so it isn't published twice when it's
+			// seen again on the next poll
+			if derr := m.Client.DeletePostIdempotent(&PostParams{ID: p.ID, Token: p.Token}); derr != nil {
+				return n, fmt.Errorf("Mark entry %s seen: %v; delete its post %s: %v", e.ID, err, p.ID, derr)
+			}
+			return n, err
+		}
+		n++
+		if m.OnPublish != nil {
+			if err = SafeCall("OnPublish", func() { m.OnPublish(e, p) }); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Run polls the feed immediately, then every Interval until ctx is done or
+// Close is called.
+func (m *Mirror) Run(ctx context.Context) error {
+	ctx, err := m.run.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.run.End()
+
+	interval := m.Interval
+	if interval <= 0 {
+		interval = DefaultMirrorInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Poll(); err != nil {
+			ReportError(m.OnError, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops Run, waiting for the entries of a poll in progress to be
+// published first.
+func (m *Mirror) Close() error {
+	return m.run.Close()
+}
+
+func (m *Mirror) postParams(e FeedEntry) *PostParams {
+	content := e.Content
+	if e.Link != "" {
+		content += fmt.Sprintf("\n\n*Originally published at [%s](%s).*", e.Link, e.Link)
+	}
+	return &PostParams{
+		Title:      e.Title,
+		Content:    content,
+		Collection: m.Collection,
+	}
+}
+
+// Seen implements the SeenStore interface.
+func (s *MemorySeenStore) Seen(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ids[id], nil
+}
+
+// MarkSeen implements the SeenStore interface.
+func (s *MemorySeenStore) MarkSeen(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ids == nil {
+		s.ids = map[string]bool{}
+	}
+	s.ids[id] = true
+	return nil
+}
+
+// Seen implements the SeenStore interface.
+func (s *FileSeenStore) Seen(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return false, err
+	}
+	return s.ids[id], nil
+}
+
+// MarkSeen implements the SeenStore interface.
+func (s *FileSeenStore) MarkSeen(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintln(f, id); err != nil {
+		f.Close()
+		return err
+	}
+	s.ids[id] = true
+	return f.Close()
+}
+
+func (s *FileSeenStore) load() error {
+	if s.ids != nil {
+		return nil
+	}
+	ids := map[string]bool{}
+	f, err := os.Open(s.Path)
+	if os.IsNotExist(err) {
+		s.ids = ids
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		ids[sc.Text()] = true
+	}
+	if err = sc.Err(); err != nil {
+		return err
+	}
+	s.ids = ids
+	return nil
+}
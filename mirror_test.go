@@ -0,0 +1,134 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// feedServer serves an RSS feed of the given items, which can be changed
+// between polls.
+type feedServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	items []string
+}
+
+func newFeedServer(items ...string) *feedServer {
+	fs := &feedServer{items: items}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fs.mu.Lock()
+		defer fs.mu.Unlock()
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel>%s</channel></rss>`, strings.Join(fs.items, ""))
+	}))
+	return fs
+}
+
+func (fs *feedServer) add(item string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.items = append([]string{item}, fs.items...)
+}
+
+func feedItem(title, date string) string {
+	return fmt.Sprintf("<item><title>%s</title><description>%s body</description><pubDate>%s</pubDate></item>", title, title, date)
+}
+
+func TestMirrorPoll(t *testing.T) {
+	// Out of order, and without guids or links
+	feed := newFeedServer(
+		feedItem("Second", "Mon, 02 Jan 2006 15:04:05 +0000"),
+		feedItem("Third", "Tue, 03 Jan 2006 15:04:05 +0000"),
+		feedItem("First", "Sun, 01 Jan 2006 15:04:05 +0000"),
+	)
+	defer feed.Close()
+	api := newFakeAPI(t)
+	defer api.Close()
+
+	var titles []string
+	seen := &FileSeenStore{Path: filepath.Join(t.TempDir(), "seen")}
+	m := &Mirror{Client: api.client(), FeedURL: feed.URL, Seen: seen, OnPublish: func(e FeedEntry, p *Post) {
+		titles = append(titles, p.Title)
+	}}
+	if n, err := m.Poll(); err != nil || n != 3 {
+		t.Fatalf("Unexpected poll: %d, %v", n, err)
+	}
+	if strings.Join(titles, ", ") != "First, Second, Third" {
+		t.Errorf("Unexpected order: %v", titles)
+	}
+
+	// A restarted mirror remembers what it published
+	feed.add(feedItem("Fourth", "Wed, 04 Jan 2006 15:04:05 +0000"))
+	m = &Mirror{Client: api.client(), FeedURL: feed.URL, Seen: &FileSeenStore{Path: seen.Path}}
+	if n, err := m.Poll(); err != nil || n != 1 {
+		t.Fatalf("Unexpected poll after restart: %d, %v", n, err)
+	}
+	if len(api.posts) != 4 {
+		t.Errorf("Unexpected posts: %d", len(api.posts))
+	}
+}
+
+// failingSeenStore is a SeenStore that can't mark entries seen.
+type failingSeenStore struct {
+	MemorySeenStore
+}
+
+func (s *failingSeenStore) MarkSeen(id string) error {
+	return errors.New("disk full")
+}
+
+func TestMirrorPollMarkSeenFails(t *testing.T) {
+	feed := newFeedServer(feedItem("Hello", "Sun, 01 Jan 2006 15:04:05 +0000"))
+	defer feed.Close()
+	api := newFakeAPI(t)
+	defer api.Close()
+
+	m := &Mirror{Client: api.client(), FeedURL: feed.URL, Seen: &failingSeenStore{}}
+	if n, err := m.Poll(); err == nil || n != 0 {
+		t.Fatalf("Unexpected poll: %d, %v", n, err)
+	}
+	if len(api.posts) != 0 {
+		t.Errorf("Expected the post to be deleted, got %d posts", len(api.posts))
+	}
+}
+
+func TestMirrorRun(t *testing.T) {
+	feed := newFeedServer(feedItem("First", "Sun, 01 Jan 2006 15:04:05 +0000"))
+	defer feed.Close()
+	api := newFakeAPI(t)
+	defer api.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	published := make(chan string, 2)
+	m := &Mirror{Client: api.client(), FeedURL: feed.URL, Interval: 10 * time.Millisecond, OnPublish: func(e FeedEntry, p *Post) {
+		published <- e.Title
+	}}
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+
+	if title := <-published; title != "First" {
+		t.Errorf("Unexpected first entry: %s", title)
+	}
+	feed.add(feedItem("Second", "Mon, 02 Jan 2006 15:04:05 +0000"))
+	if title := <-published; title != "Second" {
+		t.Errorf("Unexpected second entry: %s", title)
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Unexpected error from Run: %v", err)
+	}
+	if len(api.posts) != 2 {
+		t.Errorf("Unexpected posts: %d", len(api.posts))
+	}
+}
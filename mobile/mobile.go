@@ -0,0 +1,159 @@
+#author: Nguyễn Thái Sơn
+// Package mobile is a simplified facade over the client for Android and iOS
+// apps, built with gomobile:
+//
+//	gomobile bind -target=android github.com/writeas/go-writeas/mobile
+//
+// gomobile can only bind a few types, so its functions only take and return
+// strings, numbers, bools, []byte, errors, and the Post struct here. Lists
+// are returned as JSON arrays of Post.
+package mobile
+
+import (
+	"encoding/json"
+
+	"github.com/writeas/go-writeas"
+)
+
+// Client makes requests to Write.as, or a WriteFreely instance.
+type Client struct {
+	c *writeas.Client
+}
+
+// Post is a published post.
+type Post struct {
+	ID    string `json:"id"`
+	Slug  string `json:"slug"`
+	Token string `json:"token"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+	// Collection is the alias of the post's collection, if it's in one.
+	Collection string `json:"collection"`
+	// Created is when the post was published, in Unix seconds.
+	Created int64 `json:"created"`
+}
+
+// NewClient returns a Client for Write.as.
+func NewClient() *Client {
+	return &Client{c: writeas.NewClient()}
+}
+
+// NewClientForInstance returns a Client for the WriteFreely instance at the
+// given URL.
+func NewClientForInstance(instanceURL string) (*Client, error) {
+	c, err := writeas.NewClientForInstance(instanceURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{c: c}, nil
+}
+
+// SetToken sets the access token of the user to make requests as. Apps
+// should keep it in secure storage instead of the user's password.
+func (c *Client) SetToken(token string) {
+	c.c.SetToken(token)
+}
+
+// LogIn logs the user in and makes the following requests as them, returning
+// their access token.
+func (c *Client) LogIn(username, password string) (string, error) {
+	u, err := c.c.LogIn(username, password)
+	if err != nil {
+		return "", err
+	}
+	return u.AccessToken, nil
+}
+
+// LogOut logs the user out, invalidating their access token.
+func (c *Client) LogOut() error {
+	return c.c.LogOut()
+}
+
+// Publish publishes a post, in the collection with the given alias, or
+// anonymously if it's empty.
+func (c *Client) Publish(title, body, collection string) (*Post, error) {
+	p, err := c.c.CreatePost(&writeas.PostParams{
+		Title:      title,
+		Content:    body,
+		Collection: collection,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newPost(p), nil
+}
+
+// Update replaces the title and body of a post. The token is only needed
+// for anonymous posts.
+func (c *Client) Update(id, token, title, body string) (*Post, error) {
+	p, err := c.c.UpdatePost(&writeas.PostParams{
+		ID:      id,
+		Token:   token,
+		Title:   title,
+		Content: body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newPost(p), nil
+}
+
+// Delete deletes a post. The token is only needed for anonymous posts.
+func (c *Client) Delete(id, token string) error {
+	return c.c.DeletePost(&writeas.PostParams{ID: id, Token: token})
+}
+
+// GetPost retrieves a post by its ID.
+func (c *Client) GetPost(id string) (*Post, error) {
+	p, err := c.c.GetPost(id)
+	if err != nil {
+		return nil, err
+	}
+	return newPost(p), nil
+}
+
+// UserPosts returns the logged-in user's posts as a JSON array of Post.
+func (c *Client) UserPosts() ([]byte, error) {
+	posts, err := c.c.GetUserPosts()
+	if err != nil {
+		return nil, err
+	}
+	return postsJSON(*posts)
+}
+
+// CollectionPosts returns the posts in the collection with the given alias
+// as a JSON array of Post.
+func (c *Client) CollectionPosts(alias string) ([]byte, error) {
+	posts, err := c.c.GetCollectionPosts(alias)
+	if err != nil {
+		return nil, err
+	}
+	return postsJSON(*posts)
+}
+
+func newPost(p *writeas.Post) *Post {
+	mp := &Post{
+		ID:    p.ID,
+		Slug:  p.Slug,
+		Token: p.Token,
+		Title: p.Title,
+		Body:  p.Content,
+		URL:   p.URL,
+	}
+	if p.Collection != nil {
+		mp.Collection = p.Collection.Alias
+	}
+	if !p.Created.IsZero() {
+		mp.Created = p.Created.Unix()
+	}
+	return mp
+}
+
+func postsJSON(posts []writeas.Post) ([]byte, error) {
+	res := make([]*Post, len(posts))
+	for i := range posts {
+		res[i] = newPost(&posts[i])
+	}
+	return json.Marshal(res)
+}
@@ -0,0 +1,49 @@
+#author: Nguyễn Thái Sơn
+package mobile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/collections/blog/posts":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"code":201,"data":{"id":"p1","slug":"hi","title":"Hi","body":"Hello","created":"2024-01-02T03:04:05Z","collection":{"alias":"blog"}}}`)
+		case r.Method == "GET" && r.URL.Path == "/api/collections/blog/posts":
+			fmt.Fprint(w, `{"code":200,"data":{"alias":"blog","posts":[{"id":"p1","slug":"hi"},{"id":"p2","slug":"bye"}]}}`)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	wc, err := writeas.NewClientForInstance(srv.URL, writeas.WithInsecureHTTP())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{c: wc}
+
+	p, err := c.Publish("Hi", "Hello", "blog")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.ID != "p1" || p.Collection != "blog" || p.Body != "Hello" || p.Created != 1704164645 || p.URL != srv.URL+"/blog/hi" {
+		t.Errorf("Unexpected post: %+v", p)
+	}
+
+	data, err := c.CollectionPosts("blog")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	posts := []Post{}
+	if err = json.Unmarshal(data, &posts); err != nil || len(posts) != 2 || posts[1].URL != srv.URL+"/blog/bye" {
+		t.Errorf("Unexpected posts: %s, %v", data, err)
+	}
+}
@@ -0,0 +1,89 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+type (
+	// NavMenu is a collection's navigation menu, made of its pinned posts,
+	// for themes and static exports.
+	NavMenu struct {
+		Items []NavItem `json:"items"`
+	}
+
+	// NavItem is a link in a NavMenu.
+	NavItem struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		// Position is where the post is pinned, starting at 1.
+		Position int `json:"position"`
+	}
+)
+
+// NavMenu returns the menu of the pages, in order of position.
+func (ps *Pages) NavMenu() *NavMenu {
+	m := &NavMenu{Items: []NavItem{}}
+	for _, pg := range ps.List() {
+		m.Items = append(m.Items, NavItem{Title: pg.Title, URL: pg.Post.URL, Position: pg.Position})
+	}
+	return m
+}
+
+// NavMenu reads the pinned posts of the collection with the given alias and
+// returns their menu. The pinned posts are given by slug, in order, like a
+// ManifestCollection's Pinned, or if pinned is nil, they're the ones recorded
+// in the Client's PinStore.
+func (c *Client) NavMenu(alias string, pinned []string) (*NavMenu, error) {
+	if pinned == nil {
+		var err error
+		if pinned, err = c.Pinned(alias); err != nil {
+			return nil, err
+		}
+	}
+	m := &NavMenu{Items: []NavItem{}}
+	for i, slug := range pinned {
+		p, err := c.findCollectionPost(alias, slug)
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			return nil, fmt.Errorf("Pinned post %s/%s not found.", alias, slug)
+		}
+		m.Items = append(m.Items, NavItem{Title: postDisplayTitle(p), URL: p.URL, Position: i + 1})
+	}
+	return m, nil
+}
+
+// sorted returns the items in order of position.
+func (m *NavMenu) sorted() []NavItem {
+	items := append([]NavItem{}, m.Items...)
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Position < items[j].Position })
+	return items
+}
+
+// Markdown renders the menu as a Markdown list of links.
+func (m *NavMenu) Markdown() string {
+	var b strings.Builder
+	for _, it := range m.sorted() {
+		fmt.Fprintf(&b, "- [%s](%s)\n", navLinkText.Replace(it.Title), it.URL)
+	}
+	return b.String()
+}
+
+// HTML renders the menu as a <nav> element with a list of links.
+func (m *NavMenu) HTML() string {
+	var b strings.Builder
+	b.WriteString("<nav>\n<ul>\n")
+	for _, it := range m.sorted() {
+		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a></li>\n", html.EscapeString(it.URL), html.EscapeString(it.Title))
+	}
+	b.WriteString("</ul>\n</nav>\n")
+	return b.String()
+}
+
+// navLinkText escapes the characters that would end a Markdown link's text.
+var navLinkText = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
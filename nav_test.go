@@ -0,0 +1,45 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import "testing"
+
+func TestNavMenu(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	c := api.client()
+	pages := c.Pages("blog")
+	pages.EnsurePage("Contact", "Email me.", 2)
+	pages.EnsurePage("About [me]", "Hi.", 1)
+
+	m := pages.NavMenu()
+	if len(m.Items) != 2 || m.Items[0].Title != "About [me]" || m.Items[0].URL != api.URL+"/blog/about-me" {
+		t.Fatalf("Unexpected menu %+v", m.Items)
+	}
+	md := "- [About \\[me\\]](" + api.URL + "/blog/about-me)\n- [Contact](" + api.URL + "/blog/contact)\n"
+	if got := m.Markdown(); got != md {
+		t.Errorf("Unexpected Markdown:\n%s", got)
+	}
+	html := "<nav>\n<ul>\n<li><a href=\"" + api.URL + "/blog/about-me\">About [me]</a></li>\n<li><a href=\"" + api.URL + "/blog/contact\">Contact</a></li>\n</ul>\n</nav>\n"
+	if got := m.HTML(); got != html {
+		t.Errorf("Unexpected HTML:\n%s", got)
+	}
+
+	fromSlugs, err := c.NavMenu("blog", []string{"contact", "about-me"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fromSlugs.Items[0].Title != "Contact" || fromSlugs.Items[1].Position != 2 {
+		t.Errorf("Unexpected menu %+v", fromSlugs.Items)
+	}
+	recorded, err := c.NavMenu("blog", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(recorded.Items) != 2 || recorded.Items[0].Title != "About [me]" || recorded.Items[1].Title != "Contact" {
+		t.Errorf("Unexpected menu from recorded pins %+v", recorded.Items)
+	}
+	if _, err = c.NavMenu("blog", []string{"missing"}); err == nil {
+		t.Errorf("Expected an error for a missing pinned post")
+	}
+}
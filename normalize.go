@@ -0,0 +1,73 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"regexp"
+	"strings"
+)
+
+var headingSpaceReg = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)[ \t]*$`)
+
+// Normalize returns post content in a canonical form, so that comparisons
+// between local and remote copies of a post aren't thrown off by spurious
+// whitespace differences. It:
+//
+//   - converts line endings to "\n"
+//   - strips trailing whitespace, keeping two-space hard line breaks
+//   - puts a single space after heading markers and a blank line around
+//     headings
+//   - collapses runs of blank lines and trims blank lines at either end
+//
+// Fenced code blocks are left untouched, other than their line endings.
+// Lines like "#tag" are hashtags, not headings, and are also left alone.
+func Normalize(content string) string {
+	lines, _ := markdownLines(content)
+
+	var out []string
+	blank := func() {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+	}
+	afterHeading := false
+	for _, l := range lines {
+		if l.code {
+			if afterHeading {
+				blank()
+				afterHeading = false
+			}
+			out = append(out, l.text)
+			continue
+		}
+
+		t := strings.TrimRight(l.text, " \t")
+		if strings.HasSuffix(l.text, "  ") && t != "" {
+			t += "  "
+		}
+		if t == "" {
+			blank()
+			continue
+		}
+		if afterHeading {
+			blank()
+			afterHeading = false
+		}
+		if m := headingSpaceReg.FindStringSubmatch(t); m != nil && m[2] != "" {
+			blank()
+			t = m[1] + " " + m[2]
+			afterHeading = true
+		}
+		out = append(out, t)
+	}
+
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return strings.Join(out, "\n")
+}
+
+// ContentEqual reports whether two versions of post content are the same once
+// normalized.
+func ContentEqual(a, b string) bool {
+	return Normalize(a) == Normalize(b)
+}
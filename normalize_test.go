@@ -0,0 +1,17 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	content := "\r\n#  Title  \r\nIntro line. \t\r\nHard break  \r\n\r\n\r\n\r\n#tag\n```\ncode   \n\n\n```\n##Not a heading\n\n"
+	expected := "# Title\n\nIntro line.\nHard break  \n\n#tag\n```\ncode   \n\n\n```\n##Not a heading"
+	if res := Normalize(content); res != expected {
+		t.Errorf("Unexpected normalized content: %q", res)
+	}
+	if !ContentEqual("# Hi\nThere", "#   Hi\n\nThere\n") {
+		t.Errorf("Expected content to be equal")
+	}
+}
@@ -0,0 +1,81 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Option configures a Client when it's created.
+//
+//	c := writeas.NewClient(writeas.WithDefaults(writeas.PostParams{
+//		Font:       "serif",
+//		Collection: "blog",
+//	}))
+type Option func(*Client)
+
+// WithDefaults sets PostParams that every post created by the Client inherits,
+// unless the post sets its own. Only Font, IsRTL, Language, Crosspost, and
+// Collection are used.
+func WithDefaults(defaults PostParams) Option {
+	return func(c *Client) {
+		c.defaults = &defaults
+	}
+}
+
+// WithClaimChunkSize sets the number of posts ClaimPosts sends in each request,
+// instead of MaxClaimPosts.
+func WithClaimChunkSize(n int) Option {
+	return func(c *Client) {
+		c.claimChunkSize = n
+	}
+}
+
+// WithBaseURL sets the URL of the API the Client talks to, like
+// "https://writefreely.example.com/api", for using a self-hosted WriteFreely
+// instance.
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(url, "/")
+	}
+}
+
+// WithTransport sets the http.RoundTripper the Client makes requests with, e.g.
+// for logging, caching, or injecting faults in tests.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.client.Transport = rt
+	}
+}
+
+func (c *Client) applyOptions(opts []Option) *Client {
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// applyDefaults returns a copy of the given PostParams with the Client's
+// defaults filled in, leaving the original untouched.
+func (c *Client) applyDefaults(sp *PostParams) *PostParams {
+	if c.defaults == nil {
+		return sp
+	}
+	dp := *sp
+	if dp.Font == "" {
+		dp.Font = c.defaults.Font
+	}
+	if dp.IsRTL == nil {
+		dp.IsRTL = c.defaults.IsRTL
+	}
+	if dp.Language == nil {
+		dp.Language = c.defaults.Language
+	}
+	if dp.Crosspost == nil {
+		dp.Crosspost = c.defaults.Crosspost
+	}
+	if dp.Collection == "" {
+		dp.Collection = c.defaults.Collection
+	}
+	return &dp
+}
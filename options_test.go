@@ -0,0 +1,25 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestWithDefaults(t *testing.T) {
+	lang := "en"
+	c := NewClient(WithDefaults(PostParams{
+		Font:       "serif",
+		Language:   &lang,
+		Collection: "blog",
+		Title:      "Ignored",
+	}))
+
+	sp := &PostParams{Content: "Hi", Font: "mono"}
+	dp := c.applyDefaults(sp)
+	if dp.Font != "mono" || dp.Language == nil || *dp.Language != "en" || dp.Collection != "blog" || dp.Title != "" {
+		t.Errorf("Unexpected params: %+v", dp)
+	}
+	if sp.Collection != "" {
+		t.Errorf("Original params were modified")
+	}
+}
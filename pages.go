@@ -0,0 +1,136 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type (
+	// Page is a static page of a collection, like About or Contact: a post
+	// pinned to the collection at Position, starting at 1.
+	Page struct {
+		Title    string
+		Slug     string
+		Position int
+		Post     *Post
+	}
+
+	// Pages manages a collection's static pages. It records their pins in
+	// the Client's PinStore, but only knows the titles of the pages ensured
+	// through it, which makes it suited to provisioning scripts that set up
+	// every page at once:
+	//
+	//	pages := c.Pages("blog")
+	//	pages.EnsurePage("About", about, 1)
+	//	pages.EnsurePage("Contact", contact, 2)
+	Pages struct {
+		client *Client
+		alias  string
+		pages  []Page
+	}
+)
+
+// Pages returns the Pages of the collection with the given alias.
+func (c *Client) Pages(alias string) *Pages {
+	return &Pages{client: c, alias: alias}
+}
+
+// EnsurePage creates or updates the page with the given title, like
+// EnsurePost, and pins it at the given position. Its slug is made from the
+// title, so "About Me" is at /about-me. A position of 0 keeps the page where
+// it was, or puts a new one after the others.
+func (ps *Pages) EnsurePage(title, content string, position int) (*Page, EnsureAction, error) {
+	slug := pageSlug(title)
+	if slug == "" {
+		return nil, "", fmt.Errorf("Page title %q has no letters or digits for a slug.", title)
+	}
+	i := ps.index(slug)
+	if position <= 0 {
+		if i >= 0 {
+			position = ps.pages[i].Position
+		} else {
+			position = len(ps.pages) + 1
+		}
+	}
+
+	p, action, err := ps.client.EnsurePost(&PostParams{
+		Title:      title,
+		Content:    content,
+		Slug:       slug,
+		Collection: ps.alias,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	// Pin every time, since the page's position may have been changed on
+	// the site
+	if err = ps.client.PinPost(ps.alias, &PinnedPostParams{ID: p.ID, Position: position}); err != nil {
+		return nil, "", err
+	}
+	if err = ps.client.recordPin(ps.alias, slug, position); err != nil {
+		return nil, "", err
+	}
+
+	pg := Page{Title: title, Slug: slug, Position: position, Post: p}
+	if i >= 0 {
+		ps.pages[i] = pg
+	} else {
+		ps.pages = append(ps.pages, pg)
+	}
+	return &pg, action, nil
+}
+
+// RemovePage unpins the page with the given slug, leaving its post in the
+// collection.
+func (ps *Pages) RemovePage(slug string) error {
+	i := ps.index(slug)
+	if i < 0 {
+		return fmt.Errorf("No page %s.", slug)
+	}
+	if err := ps.client.UnpinPost(ps.alias, &PinnedPostParams{ID: ps.pages[i].Post.ID}); err != nil {
+		return err
+	}
+	if err := ps.client.recordUnpin(ps.alias, slug); err != nil {
+		return err
+	}
+	ps.pages = append(ps.pages[:i], ps.pages[i+1:]...)
+	return nil
+}
+
+// List returns the pages, in order of position.
+func (ps *Pages) List() []Page {
+	pages := append([]Page{}, ps.pages...)
+	sort.SliceStable(pages, func(i, j int) bool { return pages[i].Position < pages[j].Position })
+	return pages
+}
+
+func (ps *Pages) index(slug string) int {
+	for i := range ps.pages {
+		if ps.pages[i].Slug == slug {
+			return i
+		}
+	}
+	return -1
+}
+
+// pageSlug returns a slug for a page title: its letters and digits,
+// lowercased, with hyphens between words.
+func pageSlug(title string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	return sb.String()
+}
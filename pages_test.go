@@ -0,0 +1,70 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPages(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	c := api.client()
+	pages := c.Pages("blog")
+
+	about, action, err := pages.EnsurePage("About Me", "Hi.", 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if action != EnsureCreated || about.Slug != "about-me" || about.Position != 1 {
+		t.Errorf("Unexpected page %+v, %s", about, action)
+	}
+	if _, _, err = pages.EnsurePage("Contact", "Email me.", 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, action, _ = pages.EnsurePage("About Me", "Hi.", 3); action != EnsureUnchanged {
+		t.Errorf("Expected an unchanged page, got %s", action)
+	}
+
+	var order []string
+	for _, pg := range pages.List() {
+		order = append(order, fmt.Sprintf("%d:%s", pg.Position, pg.Slug))
+	}
+	if fmt.Sprint(order) != "[2:contact 3:about-me]" {
+		t.Errorf("Unexpected pages %v", order)
+	}
+	contact := api.collectionPost("blog", "contact")
+	if fmt.Sprint(api.pinned["blog"]) != fmt.Sprint([]string{contact.ID, about.Post.ID}) {
+		t.Errorf("Expected every EnsurePage to pin, got %v", api.pinned["blog"])
+	}
+	if slugs, _ := c.Pinned("blog"); fmt.Sprint(slugs) != "[contact about-me]" {
+		t.Errorf("Unexpected recorded pins %v", slugs)
+	}
+
+	if err = pages.RemovePage("contact"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(pages.List()) != 1 || len(api.pinned["blog"]) != 1 {
+		t.Errorf("Expected the page to be unpinned, got %v", api.pinned["blog"])
+	}
+	if slugs, _ := c.Pinned("blog"); fmt.Sprint(slugs) != "[about-me]" {
+		t.Errorf("Unexpected recorded pins %v", slugs)
+	}
+	if _, _, err = pages.EnsurePage("!!!", "", 1); err == nil {
+		t.Errorf("Expected an error for a title without a slug")
+	}
+}
+
+func TestPageSlug(t *testing.T) {
+	for title, want := range map[string]string{
+		"About":         "about",
+		"  Contact Us!": "contact-us",
+		"FAQ & Help":    "faq-help",
+		"Über uns":      "über-uns",
+	} {
+		if got := pageSlug(title); got != want {
+			t.Errorf("Unexpected slug for %q: %q", title, got)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MinPasswordLength is the shortest password ChangePassword accepts before
+// asking the API.
+const MinPasswordLength = 8
+
+// WeakPasswordError is returned when a new password is rejected as too weak.
+type WeakPasswordError struct {
+	Reason string
+}
+
+func (e *WeakPasswordError) Error() string {
+	return "Password is too weak: " + e.Reason
+}
+
+// RateLimitError is returned when too many requests were made, and the call
+// should be tried again later.
+type RateLimitError struct {
+	Message string
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Message == "" {
+		return "Too many requests. Try again later."
+	}
+	return "Too many requests: " + e.Message
+}
+
+// ChangePassword changes the authenticated user's password, returning a
+// *WeakPasswordError if the new one is rejected and a *RateLimitError if
+// there have been too many attempts.
+func (c *Client) ChangePassword(current, new string) error {
+	if len([]rune(new)) < MinPasswordLength {
+		return &WeakPasswordError{Reason: fmt.Sprintf("it must be at least %d characters.", MinPasswordLength)}
+	}
+	if strings.TrimSpace(new) == "" || new == current {
+		return &WeakPasswordError{Reason: "it must be different from the current password."}
+	}
+
+	env, err := c.post("/me/password", map[string]string{
+		"current_pass": current,
+		"new_pass":     new,
+	}, &struct{}{})
+	if err != nil {
+		return err
+	}
+
+	status := env.Code
+	if status == http.StatusOK || status == http.StatusNoContent {
+		return nil
+	} else if c.isNotLoggedIn(status) {
+		return fmt.Errorf("Not authenticated.")
+	} else if status == http.StatusForbidden {
+		return fmt.Errorf("Incorrect password.")
+	} else if status == http.StatusBadRequest {
+		return &WeakPasswordError{Reason: env.ErrorMessage}
+	} else if status == http.StatusTooManyRequests {
+		return &RateLimitError{Message: env.ErrorMessage}
+	}
+	return fmt.Errorf("Problem changing password: %d. %v\n", status, err)
+}
+
+// RequestPasswordReset sends a password reset link to the given email
+// address, if it belongs to an account. It returns a *RateLimitError if there
+// have been too many requests.
+func (c *Client) RequestPasswordReset(email string) error {
+	if !strings.Contains(email, "@") {
+		return fmt.Errorf("Invalid email address.")
+	}
+
+	env, err := c.post("/auth/password/reset", map[string]string{
+		"email": email,
+	}, &struct{}{})
+	if err != nil {
+		return err
+	}
+
+	status := env.Code
+	if status == http.StatusOK || status == http.StatusNoContent || status == http.StatusAccepted {
+		return nil
+	} else if status == http.StatusBadRequest {
+		return fmt.Errorf("Bad request: %s", env.ErrorMessage)
+	} else if status == http.StatusTooManyRequests {
+		return &RateLimitError{Message: env.ErrorMessage}
+	}
+	return fmt.Errorf("Problem requesting password reset: %d. %v\n", status, err)
+}
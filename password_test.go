@@ -0,0 +1,34 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestChangePassword(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me/password":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":400,"error_msg":"Password is too common."}`))
+		case "/auth/password/reset":
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"code":429}`))
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	if err, ok := c.ChangePassword("old-password", "short").(*WeakPasswordError); !ok {
+		t.Errorf("Expected short password to be rejected, got %v", err)
+	}
+	err, ok := c.ChangePassword("old-password", "password123").(*WeakPasswordError)
+	if !ok || err.Reason != "Password is too common." {
+		t.Errorf("Expected API to reject password, got %v", err)
+	}
+	if err, ok := c.RequestPasswordReset("writer@example.com").(*RateLimitError); !ok {
+		t.Errorf("Expected rate limit error, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+type (
+	// PinStore records the posts pinned in each collection, as slugs in
+	// order. The API pins and unpins posts but doesn't list the pinned ones,
+	// so this is how a Client knows them. Plan, SetPinned, Pages, and
+	// PublishArchive record the pins they make, and Backup, NavMenu, and
+	// Plan read them.
+	PinStore interface {
+		// Pins returns the slugs pinned in the collection, or nil if none
+		// were recorded.
+		Pins(alias string) ([]string, error)
+		SavePins(alias string, slugs []string) error
+	}
+
+	// MemoryPinStore is a PinStore that keeps pins in memory. It's what the
+	// Client uses unless it's given another with WithPinStore.
+	MemoryPinStore struct {
+		mu   sync.Mutex
+		pins map[string][]string
+	}
+
+	// FilePinStore is a PinStore that keeps the pins of every collection in
+	// a JSON file, so they're remembered between runs.
+	FilePinStore struct {
+		Path string
+
+		mu sync.Mutex
+	}
+)
+
+// WithPinStore sets where the Client records pinned posts.
+func WithPinStore(s PinStore) Option {
+	return func(c *Client) {
+		c.pins = s
+	}
+}
+
+// pinStore returns the store the Client records pinned posts in.
+func (c *Client) pinStore() PinStore {
+	if c.pins != nil {
+		return c.pins
+	}
+	return &c.memPins
+}
+
+// Pinned returns the slugs of the posts pinned in the collection with the
+// given alias, in order, as recorded in the Client's PinStore.
+func (c *Client) Pinned(alias string) ([]string, error) {
+	return c.pinStore().Pins(alias)
+}
+
+// SetPinned pins the posts with the given slugs in the collection with the
+// given alias, in order, and unpins those recorded as pinned that aren't
+// among them. Only the changes from the recorded pins are made, and the new
+// pins are recorded.
+func (c *Client) SetPinned(alias string, slugs []string) error {
+	changes, err := c.planPins(alias, slugs)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return c.ApplyChangeset(&Changeset{Changes: changes})
+}
+
+// recordPin records that the post with the given slug was pinned at position,
+// starting at 1, or after the others if it's 0.
+func (c *Client) recordPin(alias, slug string, position int) error {
+	pins, err := c.pinStore().Pins(alias)
+	if err != nil {
+		return err
+	}
+	pins = withoutSlug(pins, slug)
+	at := len(pins)
+	if position > 0 && position-1 < at {
+		at = position - 1
+	}
+	pins = append(pins[:at], append([]string{slug}, pins[at:]...)...)
+	return c.pinStore().SavePins(alias, pins)
+}
+
+// recordUnpin records that the post with the given slug was unpinned.
+func (c *Client) recordUnpin(alias, slug string) error {
+	pins, err := c.pinStore().Pins(alias)
+	if err != nil {
+		return err
+	}
+	return c.pinStore().SavePins(alias, withoutSlug(pins, slug))
+}
+
+func withoutSlug(slugs []string, slug string) []string {
+	kept := make([]string, 0, len(slugs))
+	for _, s := range slugs {
+		if s != slug {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// Pins implements the PinStore interface.
+func (s *MemoryPinStore) Pins(alias string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.pins[alias]...), nil
+}
+
+// SavePins implements the PinStore interface.
+func (s *MemoryPinStore) SavePins(alias string, slugs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pins == nil {
+		s.pins = map[string][]string{}
+	}
+	s.pins[alias] = append([]string(nil), slugs...)
+	return nil
+}
+
+// Pins implements the PinStore interface.
+func (s *FilePinStore) Pins(alias string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pins, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return pins[alias], nil
+}
+
+// SavePins implements the PinStore interface. The file is replaced
+// atomically, so a crash while saving leaves the previous pins.
+func (s *FilePinStore) SavePins(alias string, slugs []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pins, err := s.load()
+	if err != nil {
+		return err
+	}
+	if len(slugs) == 0 {
+		delete(pins, alias)
+	} else {
+		pins[alias] = slugs
+	}
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(s.Path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(s.Path+".tmp", s.Path)
+}
+
+func (s *FilePinStore) load() (map[string][]string, error) {
+	pins := map[string][]string{}
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return pins, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &pins); err != nil {
+		return nil, fmt.Errorf("Invalid pins file: %v", err)
+	}
+	return pins, nil
+}
@@ -0,0 +1,289 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"io"
+)
+
+// ChangeKind is the kind of change in a Changeset.
+type ChangeKind string
+
+// Kinds of changes.
+const (
+	ChangeCreate ChangeKind = "create"
+	ChangeUpdate ChangeKind = "update"
+	ChangeDelete ChangeKind = "delete"
+	ChangePin    ChangeKind = "pin"
+	ChangeUnpin  ChangeKind = "unpin"
+)
+
+var changeSymbols = map[ChangeKind]string{
+	ChangeCreate: "+",
+	ChangeUpdate: "~",
+	ChangeDelete: "-",
+	ChangePin:    "^",
+	ChangeUnpin:  "v",
+}
+
+type (
+	// Change is a single change to a collection or post that a Changeset will
+	// make.
+	Change struct {
+		Kind ChangeKind
+		// Collection is the alias of the collection changed, or the post's
+		// collection.
+		Collection string
+		// Slug is set for changes to posts.
+		Slug string
+		// OldSlug is set for updates that move a post from another slug.
+		OldSlug string
+		// Position is where a pinned post goes, starting at 1.
+		Position int
+		// Old and New are the content of an updated post before and after
+		// the change.
+		Old, New string
+
+		postID string
+		coll   *CollectionParams
+		post   *PostParams
+	}
+
+	// Changeset is the typed list of changes needed to reconcile the remote
+	// state with a Manifest, as returned by Plan. It can be rendered for
+	// review before it's applied with ApplyChangeset.
+	Changeset struct {
+		Changes []Change
+	}
+)
+
+// Target returns "collection <alias>" or "post <alias>/<slug>", followed by
+// "(from <old slug>)" for posts moved from another slug.
+func (ch Change) Target() string {
+	if ch.Slug == "" {
+		return "collection " + ch.Collection
+	}
+	if ch.OldSlug != "" {
+		return "post " + ch.Collection + "/" + ch.Slug + " (from " + ch.OldSlug + ")"
+	}
+	return "post " + ch.Collection + "/" + ch.Slug
+}
+
+// RedirectStub returns the content of a short post pointing readers to the
+// new URL of a post that moved.
+func RedirectStub(title, url string) string {
+	if title == "" {
+		title = url
+	}
+	return fmt.Sprintf("This post has moved to [%s](%s).", title, url)
+}
+
+// Plan compares the given Manifest with the remote state of the user's
+// collections and posts, returning the changes needed without making any.
+func (c *Client) Plan(m *Manifest) (*Changeset, error) {
+	colls, posts, err := c.manifestParams(m)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := &Changeset{}
+	for i, coll := range m.Collections {
+		existing, err := c.findCollection(coll.Alias)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			cs.Changes = append(cs.Changes, Change{Kind: ChangeCreate, Collection: coll.Alias, coll: colls[i]})
+		} else if !collectionMatches(existing, colls[i]) {
+			cs.Changes = append(cs.Changes, Change{Kind: ChangeUpdate, Collection: coll.Alias, coll: colls[i]})
+		}
+
+		keep := map[string]bool{}
+		for j, sp := range posts[i] {
+			keep[sp.Slug] = true
+			var p *Post
+			oldSlug := ""
+			if existing != nil {
+				if p, err = c.findCollectionPost(coll.Alias, sp.Slug); err != nil {
+					return nil, err
+				}
+				for _, slug := range coll.Posts[j].OldSlugs {
+					if coll.RedirectStubs {
+						keep[slug] = true
+					}
+					if p != nil || oldSlug != "" {
+						continue
+					}
+					if p, err = c.findCollectionPost(coll.Alias, slug); err != nil {
+						return nil, err
+					}
+					if p != nil {
+						oldSlug = slug
+					}
+				}
+			}
+			if p == nil {
+				cs.Changes = append(cs.Changes, Change{Kind: ChangeCreate, Collection: coll.Alias, Slug: sp.Slug, post: sp})
+				continue
+			}
+			want, err := c.prepareParams(sp, false)
+			if err != nil {
+				return nil, err
+			}
+			if oldSlug != "" || !postMatches(p, want) {
+				cs.Changes = append(cs.Changes, Change{Kind: ChangeUpdate, Collection: coll.Alias, Slug: sp.Slug, OldSlug: oldSlug, Old: p.Content, New: want.Content, postID: p.ID, post: sp})
+			}
+			if oldSlug != "" && coll.RedirectStubs {
+				moved := Post{Slug: sp.Slug, Collection: existing}
+				stub := &PostParams{Collection: coll.Alias, Slug: oldSlug, Title: sp.Title, Content: RedirectStub(sp.Title, c.postURL(&moved))}
+				cs.Changes = append(cs.Changes, Change{Kind: ChangeCreate, Collection: coll.Alias, Slug: oldSlug, post: stub})
+			}
+		}
+
+		if coll.Prune && existing != nil {
+			remote, err := c.allCollectionPosts(coll.Alias)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range remote {
+				if !keep[p.Slug] {
+					cs.Changes = append(cs.Changes, Change{Kind: ChangeDelete, Collection: coll.Alias, Slug: p.Slug, Old: p.Content, postID: p.ID})
+				}
+			}
+		}
+
+		pins, err := c.planPins(coll.Alias, coll.Pinned)
+		if err != nil {
+			return nil, err
+		}
+		cs.Changes = append(cs.Changes, pins...)
+	}
+	return cs, nil
+}
+
+// planPins returns the changes that take the collection from the pins last
+// recorded in the PinStore to the given ones: unpinning slugs that were
+// dropped, and pinning those that are new or moved.
+func (c *Client) planPins(alias string, pinned []string) ([]Change, error) {
+	prev, err := c.pinStore().Pins(alias)
+	if err != nil {
+		return nil, err
+	}
+	want := map[string]bool{}
+	for _, slug := range pinned {
+		want[slug] = true
+	}
+	var changes []Change
+	for _, slug := range prev {
+		if !want[slug] {
+			changes = append(changes, Change{Kind: ChangeUnpin, Collection: alias, Slug: slug})
+		}
+	}
+	for j, slug := range pinned {
+		if j < len(prev) && prev[j] == slug {
+			continue
+		}
+		changes = append(changes, Change{Kind: ChangePin, Collection: alias, Slug: slug, Position: j + 1})
+	}
+	return changes, nil
+}
+
+// Of returns the changes of the given kind.
+func (cs *Changeset) Of(kind ChangeKind) []Change {
+	var changes []Change
+	for _, ch := range cs.Changes {
+		if ch.Kind == kind {
+			changes = append(changes, ch)
+		}
+	}
+	return changes
+}
+
+// Empty reports whether the Changeset has no changes.
+func (cs *Changeset) Empty() bool {
+	return len(cs.Changes) == 0
+}
+
+// Render writes the Changeset to w as a diff: one line per change, followed by
+// the removed and added lines of updated posts, and a summary.
+func (cs *Changeset) Render(w io.Writer) error {
+	for _, ch := range cs.Changes {
+		line := fmt.Sprintf("%s %s", changeSymbols[ch.Kind], ch.Target())
+		if ch.Kind == ChangePin {
+			line += fmt.Sprintf(" at %d", ch.Position)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		if ch.Kind != ChangeUpdate || ch.Slug == "" {
+			continue
+		}
+		for _, l := range diffLines(splitLines(ch.Old), splitLines(ch.New)) {
+			if l.Op == ' ' {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "    %c %s\n", l.Op, l.Text); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete, %d to pin, %d to unpin.\n",
+		len(cs.Of(ChangeCreate)), len(cs.Of(ChangeUpdate)), len(cs.Of(ChangeDelete)), len(cs.Of(ChangePin)), len(cs.Of(ChangeUnpin)))
+	return err
+}
+
+// ApplyChangeset makes the changes in the given Changeset, as returned by
+// Plan, in order, recording the new pins of each collection in the PinStore.
+func (c *Client) ApplyChangeset(cs *Changeset) error {
+	ids := map[string]string{}
+	for _, ch := range cs.Changes {
+		var err error
+		switch {
+		case ch.Kind == ChangeCreate && ch.coll != nil:
+			_, _, err = c.EnsureCollection(ch.coll)
+		case ch.Kind == ChangeUpdate && ch.coll != nil:
+			_, err = c.UpdateCollection(ch.Collection, ch.coll)
+		case ch.Kind == ChangeCreate:
+			var p *Post
+			if p, err = c.CreatePost(ch.post); err == nil {
+				ids[ch.Collection+"/"+ch.Slug] = p.ID
+			}
+		case ch.Kind == ChangeUpdate:
+			up := *ch.post
+			up.ID = ch.postID
+			_, err = c.UpdatePost(&up)
+		case ch.Kind == ChangeDelete:
+			err = c.DeletePost(&PostParams{ID: ch.postID})
+		case ch.Kind == ChangePin:
+			id, ok := ids[ch.Collection+"/"+ch.Slug]
+			if !ok {
+				var p *Post
+				if p, err = c.GetCollectionPost(ch.Collection, ch.Slug); err != nil {
+					break
+				}
+				id = p.ID
+			}
+			err = c.PinPost(ch.Collection, &PinnedPostParams{ID: id, Position: ch.Position})
+		case ch.Kind == ChangeUnpin:
+			var p *Post
+			// A post that's gone is no longer pinned anyway
+			if p, err = c.findCollectionPost(ch.Collection, ch.Slug); err == nil && p != nil {
+				err = c.UnpinPost(ch.Collection, &PinnedPostParams{ID: p.ID})
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("Can't %s %s: %v", ch.Kind, ch.Target(), err)
+		}
+		// Record each pin as it's made, so the PinStore matches the
+		// collection even if a later change fails
+		if ch.Kind == ChangePin {
+			err = c.recordPin(ch.Collection, ch.Slug, ch.Position)
+		} else if ch.Kind == ChangeUnpin {
+			err = c.recordUnpin(ch.Collection, ch.Slug)
+		}
+		if err != nil {
+			return fmt.Errorf("Can't record pins of %s: %v", ch.Collection, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "writeas-plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "keep.md"), []byte("Kept."), 0644)
+
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog", Title: "Old Blog"}
+	api.addPost("blog", Post{Slug: "keep", Content: "Kept."})
+	api.addPost("blog", Post{Slug: "stale", Content: "Stale."})
+
+	m := &Manifest{Collections: []ManifestCollection{{
+		Alias: "blog", Title: "Blog", Prune: true,
+		Posts: []ManifestPost{{Slug: "keep", File: filepath.Join(dir, "keep.md")}},
+	}}}
+	c := api.client()
+	cs, err := c.Plan(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(api.requests) != 3 {
+		t.Errorf("Expected Plan to only read, got: %v", api.requests)
+	}
+	if len(cs.Changes) != 2 || len(cs.Of(ChangeUpdate)) != 1 || cs.Of(ChangeDelete)[0].Slug != "stale" {
+		t.Fatalf("Unexpected changeset: %+v", cs.Changes)
+	}
+
+	if err := c.ApplyChangeset(cs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if api.collections["blog"].Title != "Blog" || len(api.posts) != 1 {
+		t.Errorf("Unexpected state: %+v, %+v", api.collections["blog"], api.posts)
+	}
+	if cs, _ := c.Plan(m); !cs.Empty() {
+		t.Errorf("Expected no changes after applying, got: %+v", cs.Changes)
+	}
+}
+
+func TestPlanPrunePages(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog", Title: "Blog"}
+	for i := 0; i <= PostsPerPage; i++ {
+		api.addPost("blog", Post{Slug: fmt.Sprintf("post-%d", i), Content: "Stale."})
+	}
+
+	m := &Manifest{Collections: []ManifestCollection{{Alias: "blog", Title: "Blog", Prune: true}}}
+	cs, err := api.client().Plan(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := len(cs.Of(ChangeDelete)); n != PostsPerPage+1 {
+		t.Errorf("Expected every page to be pruned, got %d deletes", n)
+	}
+}
+
+func TestPlanRedirectStubs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "writeas-plan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "post.md"), []byte("Moved."), 0644)
+
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog", Title: "Blog"}
+	moved := api.addPost("blog", Post{Slug: "old-slug", Content: "Moved."})
+
+	m := &Manifest{Collections: []ManifestCollection{{
+		Alias: "blog", Title: "Blog", Prune: true, RedirectStubs: true,
+		Posts: []ManifestPost{{Slug: "new-slug", Title: "Moved", File: filepath.Join(dir, "post.md"), OldSlugs: []string{"old-slug"}}},
+	}}}
+	c := api.client()
+	cs, err := c.Plan(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(cs.Changes) != 2 || cs.Changes[0].Target() != "post blog/new-slug (from old-slug)" || cs.Changes[1].Slug != "old-slug" {
+		t.Fatalf("Unexpected changeset: %+v", cs.Changes)
+	}
+
+	if err = c.ApplyChangeset(cs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if api.posts[moved.ID].Slug != "new-slug" {
+		t.Errorf("Expected post to move, got: %+v", api.posts[moved.ID])
+	}
+	stub := api.collectionPost("blog", "old-slug")
+	if stub == nil || stub.Content != "This post has moved to [Moved]("+api.URL+"/blog/new-slug)." {
+		t.Errorf("Unexpected stub: %+v", stub)
+	}
+	if cs, _ = c.Plan(m); !cs.Empty() {
+		t.Errorf("Expected no changes after applying, got: %+v", cs.Changes)
+	}
+}
@@ -0,0 +1,91 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"log"
+)
+
+// Plugin hooks into the lifecycle of the posts a Client publishes, for
+// integrations like notifiers, webmention senders, or audit logs. Any hook
+// may be nil. Plugins are added with WithPlugin, and their hooks run in the
+// order they were added.
+type Plugin struct {
+	// Name identifies the plugin in errors.
+	Name string
+
+	// BeforeCreate is called before a post is created, with its final
+	// PostParams, which it may change. Returning an error stops the post
+	// from being created.
+	BeforeCreate func(*PostParams) error
+	// AfterCreate is called after a post is created.
+	AfterCreate func(*Post)
+	// BeforeUpdate is called before a post is updated, like BeforeCreate.
+	BeforeUpdate func(*PostParams) error
+	// AfterDelete is called with the ID of each deleted post.
+	AfterDelete func(id string)
+}
+
+// WithPlugin adds a Plugin to the Client. Its After hooks are subscribed to
+// the Client's events, ahead of any subscribers added after it. A panic in
+// one of its hooks is returned as a *PanicError from a Before hook, and
+// logged from an After hook, since the post was already changed.
+func WithPlugin(p Plugin) Option {
+	return func(c *Client) {
+		c.plugins = append(c.plugins, p)
+		c.Subscribe(p.handleEvent)
+	}
+}
+
+// runBeforeHooks runs the given Before hook of each plugin on a copy of sp,
+// returning the copy.
+func (c *Client) runBeforeHooks(event string, sp *PostParams, hook func(Plugin) func(*PostParams) error) (*PostParams, error) {
+	if len(c.plugins) == 0 {
+		return sp, nil
+	}
+	hp := *sp
+	for _, p := range c.plugins {
+		f := hook(p)
+		if f == nil {
+			continue
+		}
+		var err error
+		if perr := SafeCall(p.hookName(event), func() { err = f(&hp) }); perr != nil {
+			return nil, perr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", p.hookName(event), err)
+		}
+	}
+	return &hp, nil
+}
+
+// handleEvent calls the plugin's After hook for the event, if it has one,
+// logging any panic.
+func (p Plugin) handleEvent(e Event) {
+	var event string
+	var run func()
+	switch e := e.(type) {
+	case PostPublished:
+		if p.AfterCreate != nil {
+			event, run = "AfterCreate", func() { p.AfterCreate(e.Post) }
+		}
+	case PostDeleted:
+		if p.AfterDelete != nil {
+			event, run = "AfterDelete", func() { p.AfterDelete(e.ID) }
+		}
+	}
+	if run == nil {
+		return
+	}
+	if perr := SafeCall(p.hookName(event), run); perr != nil {
+		log.Printf("writeas: %v", perr)
+	}
+}
+
+func (p Plugin) hookName(event string) string {
+	if p.Name == "" {
+		return event
+	}
+	return p.Name + " " + event
+}
@@ -0,0 +1,70 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPlugins(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	var log []string
+	audit := Plugin{
+		Name: "audit",
+		AfterCreate: func(p *Post) {
+			log = append(log, "created "+p.ID)
+		},
+		AfterDelete: func(id string) {
+			log = append(log, "deleted "+id)
+		},
+	}
+	signature := Plugin{
+		Name: "signature",
+		BeforeCreate: func(sp *PostParams) error {
+			if sp.Content == "" {
+				return errors.New("no content")
+			}
+			sp.Content += "\n\n— Matt"
+			return nil
+		},
+		BeforeUpdate: func(sp *PostParams) error {
+			sp.Title = strings.ToUpper(sp.Title)
+			return nil
+		},
+		AfterCreate: func(*Post) {
+			panic("oops")
+		},
+	}
+	c := api.client(WithPlugin(audit), WithPlugin(signature))
+	c.Subscribe(func(e Event) {
+		if e, ok := e.(PostDeleted); ok {
+			log = append(log, "event "+e.ID)
+		}
+	})
+
+	sp := &PostParams{Content: "Hello"}
+	p, err := c.CreatePost(sp)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if api.posts[p.ID].Content != "Hello\n\n— Matt" || sp.Content != "Hello" {
+		t.Errorf("Unexpected content: %q, params %q", api.posts[p.ID].Content, sp.Content)
+	}
+	if _, err = c.CreatePost(&PostParams{Title: "Empty"}); err == nil || err.Error() != "signature BeforeCreate: no content" {
+		t.Errorf("Unexpected error from BeforeCreate: %v", err)
+	}
+	if _, err = c.UpdatePost(&PostParams{ID: p.ID, Title: "loud", Content: "Hi"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if api.posts[p.ID].Title != "LOUD" {
+		t.Errorf("Unexpected title: %q", api.posts[p.ID].Title)
+	}
+	if err = c.DeletePost(&PostParams{ID: p.ID}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(log, ", ") != "created "+p.ID+", deleted "+p.ID+", event "+p.ID {
+		t.Errorf("Unexpected audit log: %v", log)
+	}
+}
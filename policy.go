@@ -0,0 +1,289 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointClass groups API endpoints that should be treated alike by a
+// Policy.
+type EndpointClass string
+
+// Endpoint classes.
+const (
+	// ReadEndpoints are GET and HEAD requests, which are safe to retry and
+	// cache.
+	ReadEndpoints EndpointClass = "read"
+	// WriteEndpoints are requests that change something, which retrying
+	// could repeat.
+	WriteEndpoints EndpointClass = "write"
+	// AuthEndpoints log users in and out, and are often rate limited by the
+	// API.
+	AuthEndpoints EndpointClass = "auth"
+)
+
+// Policy sets how the Client retries, caches, and limits requests to a class
+// of endpoints. The zero Policy, used for classes without one, makes every
+// request once, without caching or limits.
+type Policy struct {
+	// Retries is how many times a request is retried after a network error
+	// or a 429 or 5xx response.
+	Retries int
+	// RetryDelay is how long to wait before the first retry, doubling
+	// before each one after that. A 429 response's Retry-After takes
+	// precedence.
+	RetryDelay time.Duration
+
+	// CacheTTL is how long successful responses are cached, if positive,
+	// unless the API's Cache-Control or Expires headers say otherwise. Only
+	// ReadEndpoints are cached, and the cache is cleared by any successful
+	// write.
+	CacheTTL time.Duration
+	// StaleWhileRevalidate is how long after a cached response expires it's
+	// still returned, while it's refreshed in the background. Subscribers get
+	// a ResponseRefreshed event if the refresh brings fresher data.
+	StaleWhileRevalidate time.Duration
+
+	// RateLimit is the most requests made per second, if positive, with
+	// bursts of up to Burst requests. Requests over the limit wait.
+	RateLimit float64
+	Burst     int
+
+	// HedgeAfter, if positive, sends a second copy of a GET or HEAD request
+	// that hasn't been answered after this long, using whichever response
+	// comes first and canceling the other. Set it to around the endpoint's
+	// 95th percentile latency to cut the slowest requests short.
+	HedgeAfter time.Duration
+}
+
+// WithPolicy sets the Policy for requests to the given class of endpoints.
+//
+//	c := writeas.NewClient(
+//		writeas.WithPolicy(writeas.ReadEndpoints, writeas.Policy{Retries: 3, RetryDelay: time.Second, CacheTTL: time.Minute}),
+//		writeas.WithPolicy(writeas.AuthEndpoints, writeas.Policy{RateLimit: 0.2, Burst: 1}),
+//	)
+func WithPolicy(class EndpointClass, p Policy) Option {
+	return func(c *Client) {
+		if c.policies == nil {
+			c.policies = map[EndpointClass]*policyState{}
+		}
+		ps := &policyState{Policy: p}
+		if p.RateLimit > 0 {
+			ps.limiter = newRateLimiter(p.RateLimit, p.Burst)
+		}
+		c.policies[class] = ps
+	}
+}
+
+// policyState is a Policy along with its rate limiter.
+type policyState struct {
+	Policy
+	limiter *rateLimiter
+}
+
+// endpointClass returns the class of endpoint the request is for.
+func (c *Client) endpointClass(r *http.Request) EndpointClass {
+	if strings.HasPrefix(c.apiPath(r.URL.Path), "/auth/") {
+		return AuthEndpoints
+	}
+	if r.Method == "GET" || r.Method == "HEAD" {
+		return ReadEndpoints
+	}
+	return WriteEndpoints
+}
+
+// policy returns the Client's policy for the given class of endpoints.
+func (c *Client) policy(class EndpointClass) *policyState {
+	ps, ok := c.policies[class]
+	if !ok {
+		ps = &policyState{}
+	}
+	if c.lowBandwidth && class == ReadEndpoints {
+		return lowBandwidthPolicy(ps)
+	}
+	return ps
+}
+
+// send makes the request, waiting for the policy's rate limit and retrying
+// as it allows, until the request's context is done.
+func (c *Client) send(r *http.Request, ps *policyState) (*http.Response, error) {
+	endpoint := endpointName(r.Method, c.apiPath(r.URL.Path))
+	for attempt := 0; ; attempt++ {
+		if ps.limiter != nil {
+			ps.limiter.wait()
+		}
+
+		start := c.Now()
+		var resp *http.Response
+		var err error
+		if ps.HedgeAfter > 0 && (r.Method == "GET" || r.Method == "HEAD") {
+			resp, err = c.doHedged(r, ps.HedgeAfter)
+		} else {
+			resp, err = c.client.Do(r)
+		}
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+		}
+		c.latency.record(endpoint, c.Now().Sub(start), status)
+		delay := ps.RetryDelay << uint(attempt)
+		if status == http.StatusTooManyRequests {
+			wait := retryAfter(resp.Header.Get("Retry-After"))
+			c.emit(RateLimited{Endpoint: endpoint, RetryAfter: wait})
+			if wait > 0 {
+				delay = wait
+			}
+		}
+
+		retry := err != nil || status == http.StatusTooManyRequests || status >= 500
+		if !retry || attempt >= ps.Retries || !rewindBody(r) {
+			if err != nil {
+				return nil, fmt.Errorf("Request: %v", err)
+			}
+			return resp, nil
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			t.Stop()
+			return nil, fmt.Errorf("Request: %v", r.Context().Err())
+		}
+	}
+}
+
+// doHedged makes the request, and a second copy of it if there's no response
+// after the given delay, returning the first response. Both copies run with
+// contexts derived from the request's, so its deadline applies to them.
+func (c *Client) doHedged(r *http.Request, after time.Duration) (*http.Response, error) {
+	type result struct {
+		resp *http.Response
+		err  error
+		i    int
+	}
+	results := make(chan result, 2)
+	cancels := []context.CancelFunc{}
+	launch := func() {
+		ctx, cancel := context.WithCancel(r.Context())
+		i := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := c.client.Do(r.Clone(ctx))
+			results <- result{resp, err, i}
+		}()
+	}
+
+	launch()
+	timer := time.NewTimer(after)
+	defer timer.Stop()
+	pending := 1
+	for {
+		select {
+		case <-timer.C:
+			if len(cancels) == 1 {
+				launch()
+				pending++
+			}
+		case res := <-results:
+			pending--
+			if res.err != nil && pending > 0 {
+				continue
+			}
+			for i, cancel := range cancels {
+				if i != res.i || res.err != nil {
+					cancel()
+				}
+			}
+			// Close the loser's response, if it comes
+			go func(n int) {
+				for ; n > 0; n-- {
+					if l := <-results; l.resp != nil {
+						l.resp.Body.Close()
+					}
+				}
+			}(pending)
+			if res.err != nil {
+				return nil, res.err
+			}
+			res.resp.Body = &cancelOnClose{ReadCloser: res.resp.Body, cancel: cancels[res.i]}
+			return res.resp, nil
+		}
+	}
+}
+
+// cancelOnClose cancels a hedged request's context once its response body is
+// closed, and not before, so the body can still be read.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// rewindBody resets the request's body to be sent again, returning false if
+// it can't be.
+func rewindBody(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+	if r.GetBody == nil {
+		return false
+	}
+	body, err := r.GetBody()
+	if err != nil {
+		return false
+	}
+	r.Body = body
+	return true
+}
+
+// rateLimiter is a token bucket, refilled at rate tokens a second up to
+// burst.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// wait takes a token, waiting for one if there aren't any. Waiting callers
+// reserve their tokens, so they're served in order.
+func (l *rateLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	l.tokens--
+	tokens := l.tokens
+	l.mu.Unlock()
+
+	if tokens < 0 {
+		time.Sleep(time.Duration(-tokens / l.rate * float64(time.Second)))
+	}
+}
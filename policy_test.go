@@ -0,0 +1,172 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// policyServer responds to every request with the next status in statuses,
+// then with 200, recording the requests' bodies.
+type policyServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+func newPolicyServer(statuses ...int) *policyServer {
+	ps := &policyServer{statuses: statuses}
+	ps.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ps.mu.Lock()
+		defer ps.mu.Unlock()
+		body, _ := ioutil.ReadAll(r.Body)
+		ps.bodies = append(ps.bodies, string(body))
+		status := http.StatusOK
+		if len(ps.statuses) > 0 {
+			status, ps.statuses = ps.statuses[0], ps.statuses[1:]
+		}
+		if r.Method == "POST" && status == http.StatusOK {
+			status = http.StatusCreated
+		}
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"code":%d,"data":{"id":"p%d","body":"Hi"}}`, status, len(ps.bodies))
+	}))
+	return ps
+}
+
+func TestPolicyRetries(t *testing.T) {
+	srv := newPolicyServer(http.StatusBadGateway, http.StatusServiceUnavailable)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithPolicy(ReadEndpoints, Policy{Retries: 2, RetryDelay: time.Millisecond}))
+
+	p, err := c.GetPost("abc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.ID != "p3" || len(srv.bodies) != 3 {
+		t.Errorf("Unexpected post %+v after %d requests", p, len(srv.bodies))
+	}
+
+	// Writes aren't retried without a policy
+	srv.statuses = []int{http.StatusBadGateway}
+	if _, err = c.CreatePost(&PostParams{Content: "Hi"}); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if len(srv.bodies) != 4 {
+		t.Errorf("Unexpected number of requests: %d", len(srv.bodies))
+	}
+}
+
+func TestPolicyRetryResendsBody(t *testing.T) {
+	srv := newPolicyServer(http.StatusInternalServerError)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithPolicy(WriteEndpoints, Policy{Retries: 1}))
+
+	if _, err := c.CreatePost(&PostParams{Content: "Hi"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(srv.bodies) != 2 || srv.bodies[0] != srv.bodies[1] || srv.bodies[1] == "" {
+		t.Errorf("Unexpected request bodies: %q", srv.bodies)
+	}
+}
+
+func TestPolicyRetryCanceled(t *testing.T) {
+	srv := newPolicyServer(http.StatusServiceUnavailable)
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithPolicy(ReadEndpoints, Policy{Retries: 1, RetryDelay: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	r, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/posts/abc", nil)
+	start := time.Now()
+	if _, err := c.send(r, c.policy(ReadEndpoints)); err == nil {
+		t.Errorf("Expected an error")
+	}
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Expected the retry delay to end with the context, took %s", took)
+	}
+}
+
+func TestPolicyCache(t *testing.T) {
+	srv := newPolicyServer()
+	defer srv.Close()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClient(WithBaseURL(srv.URL), WithClock(ClockFunc(func() time.Time { return now })),
+		WithPolicy(ReadEndpoints, Policy{CacheTTL: time.Minute}))
+
+	get := func() string {
+		p, err := c.GetPost("abc")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return p.ID
+	}
+	if a, b := get(), get(); a != "p1" || b != "p1" || len(srv.bodies) != 1 {
+		t.Errorf("Expected a cached response, got %s then %s", a, b)
+	}
+	now = now.Add(time.Minute)
+	if id := get(); id != "p2" {
+		t.Errorf("Expected an expired response to be fetched again, got %s", id)
+	}
+	c.CreatePost(&PostParams{Content: "Hi"})
+	if id := get(); id != "p4" {
+		t.Errorf("Expected a write to clear the cache, got %s", id)
+	}
+}
+
+func TestPolicyRateLimit(t *testing.T) {
+	srv := newPolicyServer()
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithPolicy(ReadEndpoints, Policy{RateLimit: 50, Burst: 1}))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		c.GetPost("abc")
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("Expected requests to be limited, took %s", elapsed)
+	}
+}
+
+func TestPolicyHedging(t *testing.T) {
+	var mu sync.Mutex
+	n := 0
+	canceled := make(chan bool, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n++
+		first := n == 1
+		mu.Unlock()
+		if first {
+			select {
+			case <-r.Context().Done():
+				canceled <- true
+				return
+			case <-time.After(2 * time.Second):
+				canceled <- false
+			}
+		}
+		fmt.Fprintf(w, `{"code":200,"data":{"id":"abc","body":"first: %t"}}`, first)
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL), WithPolicy(ReadEndpoints, Policy{HedgeAfter: 20 * time.Millisecond}))
+
+	start := time.Now()
+	p, err := c.GetPost("abc")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Content != "first: false" || time.Since(start) > time.Second {
+		t.Errorf("Expected the hedged request to win, got %q after %s", p.Content, time.Since(start))
+	}
+	if !<-canceled {
+		t.Errorf("Expected the slow request to be canceled")
+	}
+}
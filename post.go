@@ -4,6 +4,7 @@ package writeas
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -13,6 +14,7 @@ type (
 	Post struct {
 		ID        string    `json:"id"`
 		Slug      string    `json:"slug"`
+		URL       string    `json:"url,omitempty"`
 		Token     string    `json:"token"`
 		Font      string    `json:"appearance"`
 		Language  *string   `json:"language"`
@@ -32,7 +34,7 @@ type (
 
 	// OwnedPostParams are, together, fields only the original post author knows.
 	OwnedPostParams struct {
-		ID    string `json:"-"`
+		ID    string `json:"id"`
 		Token string `json:"token,omitempty"`
 	}
 
@@ -43,6 +45,7 @@ type (
 		Token string `json:"token,omitempty"`
 
 		// Parameters for creating or updating
+		Slug     string  `json:"slug,omitempty"`
 		Title    string  `json:"title,omitempty"`
 		Content  string  `json:"body,omitempty"`
 		Font     string  `json:"font,omitempty"`
@@ -51,6 +54,7 @@ type (
 
 		// Parameters only for creating
 		Crosspost []map[string]string `json:"crosspost,omitempty"`
+		Created   *time.Time          `json:"created,omitempty"`
 
 		// Parameters for collection posts
 		Collection string `json:"-"`
@@ -80,10 +84,54 @@ type (
 	}
 )
 
+// Clone returns a deep copy of the Post, so changes to the copy, including its
+// Tags, Images, and Collection, don't affect the original.
+func (p *Post) Clone() *Post {
+	if p == nil {
+		return nil
+	}
+	cp := *p
+	if p.Language != nil {
+		lang := *p.Language
+		cp.Language = &lang
+	}
+	if p.RTL != nil {
+		rtl := *p.RTL
+		cp.RTL = &rtl
+	}
+	cp.Tags = cloneStrings(p.Tags)
+	cp.Images = cloneStrings(p.Images)
+	cp.Collection = p.Collection.Clone()
+	return &cp
+}
+
+// clonePosts returns a deep copy of the given posts.
+func clonePosts(posts []Post) []Post {
+	if posts == nil {
+		return nil
+	}
+	cp := make([]Post, len(posts))
+	for i := range posts {
+		cp[i] = *posts[i].Clone()
+	}
+	return cp
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
+
 // GetPost retrieves a published post, returning the Post and any error (in
 // user-friendly form) that occurs. See
 // https://developer.write.as/docs/api/#retrieve-a-post.
 func (c *Client) GetPost(id string) (*Post, error) {
+	id, err := ParsePostID(id)
+	if err != nil {
+		return nil, err
+	}
 	p := &Post{}
 	env, err := c.get(fmt.Sprintf("/posts/%s", id), p)
 	if err != nil {
@@ -97,6 +145,7 @@ func (c *Client) GetPost(id string) (*Post, error) {
 	status := env.Code
 
 	if status == http.StatusOK {
+		c.fillPostURL(p, nil)
 		return p, nil
 	} else if status == http.StatusNotFound {
 		return nil, fmt.Errorf("Post not found.")
@@ -109,6 +158,24 @@ func (c *Client) GetPost(id string) (*Post, error) {
 // CreatePost publishes a new post, returning a user-friendly error if one comes
 // up. See https://developer.write.as/docs/api/#publish-a-post.
 func (c *Client) CreatePost(sp *PostParams) (*Post, error) {
+	sp, err := c.prepareParams(sp, true)
+	if err != nil {
+		return nil, err
+	}
+	sp, err = c.runBeforeHooks("BeforeCreate", sp, func(p Plugin) func(*PostParams) error { return p.BeforeCreate })
+	if err != nil {
+		return nil, err
+	}
+	if sp.Collection != "" {
+		alias, err := ParseCollectionAlias(sp.Collection)
+		if err != nil {
+			return nil, err
+		}
+		np := *sp
+		np.Collection = alias
+		sp = &np
+	}
+
 	p := &Post{}
 	endPre := ""
 	if sp.Collection != "" {
@@ -126,6 +193,12 @@ func (c *Client) CreatePost(sp *PostParams) (*Post, error) {
 
 	status := env.Code
 	if status == http.StatusCreated {
+		var coll *Collection
+		if sp.Collection != "" {
+			coll = &Collection{Alias: sp.Collection}
+		}
+		c.fillPostURL(p, coll)
+		c.emit(PostPublished{Post: p})
 		return p, nil
 	} else if status == http.StatusBadRequest {
 		return nil, fmt.Errorf("Bad request: %s", env.ErrorMessage)
@@ -137,6 +210,21 @@ func (c *Client) CreatePost(sp *PostParams) (*Post, error) {
 // UpdatePost updates a published post with the given PostParams. See
 // https://developer.write.as/docs/api/#update-a-post.
 func (c *Client) UpdatePost(sp *PostParams) (*Post, error) {
+	id, err := ParsePostID(sp.ID)
+	if err != nil {
+		return nil, err
+	}
+	up := *sp
+	up.ID = id
+	sp, err = c.prepareParams(&up, false)
+	if err != nil {
+		return nil, err
+	}
+	sp, err = c.runBeforeHooks("BeforeUpdate", sp, func(p Plugin) func(*PostParams) error { return p.BeforeUpdate })
+	if err != nil {
+		return nil, err
+	}
+
 	p := &Post{}
 	env, err := c.put(fmt.Sprintf("/posts/%s", sp.ID), sp, p)
 	if err != nil {
@@ -157,13 +245,30 @@ func (c *Client) UpdatePost(sp *PostParams) (*Post, error) {
 		}
 		return nil, fmt.Errorf("Problem getting post: %d. %v\n", status, err)
 	}
+	c.fillPostURL(p, nil)
+	c.emit(PostUpdated{Post: p})
 	return p, nil
 }
 
 // DeletePost permanently deletes a published post. See
 // https://developer.write.as/docs/api/#delete-a-post.
 func (c *Client) DeletePost(sp *PostParams) error {
-	env, err := c.delete(fmt.Sprintf("/posts/%s", sp.ID), map[string]string{
+	return c.deletePost(sp, false)
+}
+
+// DeletePostIdempotent permanently deletes a published post like DeletePost,
+// but also succeeds if the post was already deleted, so cleanup scripts can
+// safely run more than once.
+func (c *Client) DeletePostIdempotent(sp *PostParams) error {
+	return c.deletePost(sp, true)
+}
+
+func (c *Client) deletePost(sp *PostParams, idempotent bool) error {
+	id, err := ParsePostID(sp.ID)
+	if err != nil {
+		return err
+	}
+	env, err := c.delete(fmt.Sprintf("/posts/%s", id), map[string]string{
 		"token": sp.Token,
 	})
 	if err != nil {
@@ -172,6 +277,9 @@ func (c *Client) DeletePost(sp *PostParams) error {
 
 	status := env.Code
 	if status == http.StatusNoContent {
+		c.emit(PostDeleted{ID: id})
+		return nil
+	} else if idempotent && (status == http.StatusNotFound || status == http.StatusGone) {
 		return nil
 	} else if c.isNotLoggedIn(status) {
 		return fmt.Errorf("Not authenticated.")
@@ -183,7 +291,37 @@ func (c *Client) DeletePost(sp *PostParams) error {
 
 // ClaimPosts associates anonymous posts with a user / account.
 // https://developer.write.as/docs/api/#claim-posts.
+//
+// Posts are claimed in chunks of MaxClaimPosts, or the size given with
+// WithClaimChunkSize, and the results of every chunk are returned together. If
+// a chunk fails, the results of the chunks already claimed are returned along
+// with the error.
 func (c *Client) ClaimPosts(sp *[]OwnedPostParams) (*[]ClaimPostResult, error) {
+	size := c.claimChunkSize
+	if size <= 0 {
+		size = MaxClaimPosts
+	}
+
+	res := []ClaimPostResult{}
+	posts := *sp
+	for len(posts) > 0 {
+		n := size
+		if n > len(posts) {
+			n = len(posts)
+		}
+		chunk := posts[:n]
+		posts = posts[n:]
+
+		p, err := c.claimPosts(&chunk)
+		if err != nil {
+			return &res, err
+		}
+		res = append(res, *p...)
+	}
+	return &res, nil
+}
+
+func (c *Client) claimPosts(sp *[]OwnedPostParams) (*[]ClaimPostResult, error) {
 	p := &[]ClaimPostResult{}
 	env, err := c.put("/posts/claim", sp, p)
 	if err != nil {
@@ -197,6 +335,11 @@ func (c *Client) ClaimPosts(sp *[]OwnedPostParams) (*[]ClaimPostResult, error) {
 
 	status := env.Code
 	if status == http.StatusOK {
+		for _, r := range *p {
+			if r.Post != nil {
+				c.fillPostURL(r.Post, nil)
+			}
+		}
 		return p, nil
 	} else if c.isNotLoggedIn(status) {
 		return nil, fmt.Errorf("Not authenticated.")
@@ -229,14 +372,59 @@ func (c *Client) GetUserPosts() (*[]Post, error) {
 		}
 		return nil, fmt.Errorf("Problem getting posts: %d. %v\n", status, err)
 	}
+	c.fillPostURLs(*p, nil)
 	return p, nil
 }
 
+// GetAuthorPosts retrieves the publicly listed posts of the user with the given
+// username, across all of their collections, for author pages. It returns
+// ErrNotSupported if the instance doesn't list posts by author.
+func (c *Client) GetAuthorPosts(owner string) ([]Post, error) {
+	if owner == "" {
+		return nil, fmt.Errorf("GetAuthorPosts needs a username.")
+	}
+	p := &[]Post{}
+	env, err := c.get(fmt.Sprintf("/users/%s/posts", url.PathEscape(owner)), p)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if p, ok = env.Data.(*[]Post); !ok {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+	status := env.Code
+
+	if status == http.StatusNotFound {
+		return nil, ErrNotSupported
+	} else if status != http.StatusOK {
+		return nil, fmt.Errorf("Problem getting posts: %d. %v\n", status, err)
+	}
+	posts := []Post{}
+	for _, post := range *p {
+		if post.Listed && (post.OwnerName == "" || post.OwnerName == owner) {
+			posts = append(posts, post)
+		}
+	}
+	c.fillPostURLs(posts, nil)
+	return posts, nil
+}
+
 // PinPost pins a post in the given collection.
 // See https://developers.write.as/docs/api/#pin-a-post-to-a-collection
 func (c *Client) PinPost(alias string, pp *PinnedPostParams) error {
+	alias, err := ParseCollectionAlias(alias)
+	if err != nil {
+		return err
+	}
+	id, err := ParsePostID(pp.ID)
+	if err != nil {
+		return err
+	}
+	np := *pp
+	np.ID = id
 	res := &[]BatchPostResult{}
-	env, err := c.post(fmt.Sprintf("/collections/%s/pin", alias), []*PinnedPostParams{pp}, res)
+	env, err := c.post(fmt.Sprintf("/collections/%s/pin", alias), []*PinnedPostParams{&np}, res)
 	if err != nil {
 		return err
 	}
@@ -270,8 +458,18 @@ func (c *Client) PinPost(alias string, pp *PinnedPostParams) error {
 // UnpinPost unpins a post from the given collection.
 // See https://developers.write.as/docs/api/#unpin-a-post-from-a-collection
 func (c *Client) UnpinPost(alias string, pp *PinnedPostParams) error {
+	alias, err := ParseCollectionAlias(alias)
+	if err != nil {
+		return err
+	}
+	id, err := ParsePostID(pp.ID)
+	if err != nil {
+		return err
+	}
+	np := *pp
+	np.ID = id
 	res := &[]BatchPostResult{}
-	env, err := c.post(fmt.Sprintf("/collections/%s/unpin", alias), []*PinnedPostParams{pp}, res)
+	env, err := c.post(fmt.Sprintf("/collections/%s/unpin", alias), []*PinnedPostParams{&np}, res)
 	if err != nil {
 		return err
 	}
@@ -4,7 +4,10 @@ package writeas
 import (
 	"testing"
 
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 )
 
@@ -98,6 +101,47 @@ func TestUnpinPost(t *testing.T) {
 	}
 }
 
+func TestClaimPostsChunked(t *testing.T) {
+	var sizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var claims []OwnedPostParams
+		json.NewDecoder(r.Body).Decode(&claims)
+		sizes = append(sizes, len(claims))
+		res := []ClaimPostResult{}
+		for _, cl := range claims {
+			res = append(res, ClaimPostResult{ID: cl.Token, Code: http.StatusOK})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"code": http.StatusOK, "data": res})
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithClaimChunkSize(2))
+	c.baseURL = srv.URL
+	res, err := c.ClaimPosts(&[]OwnedPostParams{{Token: "a"}, {Token: "b"}, {Token: "c"}, {Token: "d"}, {Token: "e"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(*res) != 5 || (*res)[4].ID != "e" || len(sizes) != 3 || sizes[2] != 1 {
+		t.Errorf("Unexpected results: %+v in chunks %v", *res, sizes)
+	}
+}
+
+func TestDeletePostIdempotent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	c.baseURL = srv.URL
+	if err := c.DeletePostIdempotent(&PostParams{ID: "gone"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := c.DeletePost(&PostParams{ID: "gone"}); err == nil {
+		t.Errorf("Expected DeletePost to fail on missing post")
+	}
+}
+
 func ExampleClient_CreatePost() {
 	c := NewClient()
 
@@ -115,3 +159,89 @@ func ExampleClient_CreatePost() {
 	fmt.Printf("%s", p.Content)
 	// Output: This is a post.
 }
+
+func TestPostClone(t *testing.T) {
+	lang := "en"
+	rtl := false
+	hide := false
+	posts := []Post{{ID: "a", Slug: "a"}}
+	p := &Post{
+		ID:         "abc",
+		Language:   &lang,
+		RTL:        &rtl,
+		Tags:       []string{"go"},
+		Images:     []string{"https://i.snap.as/a.png"},
+		Collection: &Collection{Alias: "blog", HideViews: &hide, Posts: &posts},
+	}
+
+	cp := p.Clone()
+	*cp.Language = "fr"
+	*cp.RTL = true
+	cp.Tags[0] = "rust"
+	cp.Images[0] = ""
+	cp.Collection.Alias = "other"
+	*cp.Collection.HideViews = true
+	(*cp.Collection.Posts)[0].Slug = "b"
+
+	if lang != "en" || rtl || p.Tags[0] != "go" || p.Images[0] == "" {
+		t.Errorf("Original post was modified: %+v", p)
+	}
+	if p.Collection.Alias != "blog" || hide || posts[0].Slug != "a" {
+		t.Errorf("Original collection was modified: %+v", p.Collection)
+	}
+	if (*Post)(nil).Clone() != nil {
+		t.Errorf("Expected nil clone of nil post")
+	}
+}
+
+func TestGetAuthorPosts(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	api.addPost("blog", Post{ID: "listed", OwnerName: "matt", Listed: true})
+	api.addPost("", Post{ID: "unlisted", OwnerName: "matt"})
+	api.addPost("", Post{ID: "other", OwnerName: "jane", Listed: true})
+	c := api.client()
+
+	posts, err := c.GetAuthorPosts("matt")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(posts) != 1 || posts[0].ID != "listed" {
+		t.Errorf("Unexpected posts: %+v", posts)
+	}
+	if api.requests[0] != "GET /users/matt/posts" {
+		t.Errorf("Unexpected request: %s", api.requests[0])
+	}
+	if _, err = c.GetAuthorPosts(""); err == nil {
+		t.Errorf("Expected an error for an empty username")
+	}
+}
+
+func TestPostURLPopulated(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.collections["blog"] = &Collection{Alias: "blog"}
+	api.addPost("blog", Post{ID: "inblog", Slug: "hello"})
+	api.addPost("", Post{ID: "anon"})
+	api.addPost("", Post{ID: "given", URL: "https://example.com/given"})
+	c := api.client()
+
+	p, err := c.GetPost("anon")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.URL != api.URL+"/anon" {
+		t.Errorf("Unexpected anonymous post URL: %s", p.URL)
+	}
+	if p, err = c.GetPost("given"); err != nil || p.URL != "https://example.com/given" {
+		t.Errorf("Unexpected URL replacing the API's: %s, %v", p.URL, err)
+	}
+	posts, err := c.GetCollectionPosts("blog")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(*posts) != 1 || (*posts)[0].URL != api.URL+"/blog/hello" {
+		t.Errorf("Unexpected collection posts: %+v", *posts)
+	}
+}
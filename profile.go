@@ -0,0 +1,129 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Profile is a named set of publishing settings, like a work blog or a
+// personal one, that can be selected when publishing a post.
+type Profile struct {
+	Name       string              `json:"name"`
+	Collection string              `json:"collection,omitempty"`
+	Font       string              `json:"font,omitempty"`
+	Language   *string             `json:"lang,omitempty"`
+	IsRTL      *bool               `json:"rtl,omitempty"`
+	Crosspost  []map[string]string `json:"crosspost,omitempty"`
+
+	// Footer is appended to the content of every post published with this
+	// profile, e.g. contact details.
+	Footer string `json:"footer,omitempty"`
+	// License is the SPDX identifier of the license posts are published
+	// under, e.g. "CC-BY-4.0". Its notice is added to the footer.
+	License string `json:"license,omitempty"`
+}
+
+// WithProfiles adds the given publishing Profiles to the Client, for use with
+// CreatePostWithProfile.
+func WithProfiles(profiles ...Profile) Option {
+	return func(c *Client) {
+		if c.profiles == nil {
+			c.profiles = map[string]*Profile{}
+		}
+		for i := range profiles {
+			c.profiles[profiles[i].Name] = &profiles[i]
+		}
+	}
+}
+
+// LoadProfiles reads a JSON array of Profiles.
+func LoadProfiles(r io.Reader) ([]Profile, error) {
+	var profiles []Profile
+	if err := json.NewDecoder(r).Decode(&profiles); err != nil {
+		return nil, fmt.Errorf("Invalid profiles: %v", err)
+	}
+	for _, p := range profiles {
+		if p.Name == "" {
+			return nil, fmt.Errorf("Invalid profiles: every profile needs a name.")
+		}
+		if _, ok := LicenseByID(p.License); p.License != "" && !ok {
+			return nil, fmt.Errorf("Invalid profiles: unknown license %q.", p.License)
+		}
+	}
+	return profiles, nil
+}
+
+// LoadProfilesFile reads a JSON file of Profiles, as written by
+// SaveProfilesFile.
+func LoadProfilesFile(path string) ([]Profile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadProfiles(f)
+}
+
+// SaveProfilesFile writes the given Profiles to a JSON file.
+func SaveProfilesFile(path string, profiles []Profile) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(profiles); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Profile returns the Client's Profile with the given name, or nil.
+func (c *Client) Profile(name string) *Profile {
+	return c.profiles[name]
+}
+
+// Apply returns a copy of the given PostParams with the Profile's settings
+// filled in wherever the params don't set their own, and its Footer and
+// license notice appended with AppendFooter.
+func (p *Profile) Apply(sp *PostParams) *PostParams {
+	pp := *sp
+	if pp.Collection == "" {
+		pp.Collection = p.Collection
+	}
+	if pp.Font == "" {
+		pp.Font = p.Font
+	}
+	if pp.Language == nil {
+		pp.Language = p.Language
+	}
+	if pp.IsRTL == nil {
+		pp.IsRTL = p.IsRTL
+	}
+	if pp.Crosspost == nil {
+		pp.Crosspost = p.Crosspost
+	}
+	footer := p.Footer
+	if l, ok := LicenseByID(p.License); ok {
+		footer = joinFooter(footer, l.Notice())
+	}
+	if footer != "" {
+		pp.Content = AppendFooter(pp.Content, footer)
+	}
+	return &pp
+}
+
+// CreatePostWithProfile publishes a new post using the Client's Profile with
+// the given name. Settings in sp take precedence over the Profile's, which take
+// precedence over the Client's defaults.
+func (c *Client) CreatePostWithProfile(name string, sp *PostParams) (*Post, error) {
+	p := c.Profile(name)
+	if p == nil {
+		return nil, fmt.Errorf("Profile %q not found.", name)
+	}
+	return c.CreatePost(p.Apply(sp))
+}
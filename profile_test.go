@@ -0,0 +1,33 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	err := SaveProfilesFile(path, []Profile{
+		{Name: "work", Collection: "eng-blog", Font: "sans", Footer: "*Posted by the Eng team.*"},
+		{Name: "personal", Collection: "matt"},
+	})
+	if err != nil {
+		t.Fatalf("Unable to save profiles: %v", err)
+	}
+
+	profiles, err := LoadProfilesFile(path)
+	if err != nil {
+		t.Fatalf("Unable to load profiles: %v", err)
+	}
+	c := NewClient(WithProfiles(profiles...))
+
+	p := c.Profile("work")
+	if p == nil {
+		t.Fatalf("Profile not found")
+	}
+	sp := p.Apply(&PostParams{Content: "Shipped.\n", Font: "mono"})
+	if sp.Collection != "eng-blog" || sp.Font != "mono" || sp.Content != "Shipped.\n\n<!--writeas:footer-->\n*Posted by the Eng team.*" {
+		t.Errorf("Unexpected params: %+v", sp)
+	}
+}
@@ -0,0 +1,107 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ReadabilityScore describes how difficult a piece of writing is to read.
+type ReadabilityScore struct {
+	// Method is the formula used for Score: "flesch-kincaid" for English
+	// text, or "lix" for other languages, since syllable counts don't carry
+	// over between them.
+	Method string
+	// Score is the Flesch-Kincaid grade level or the LIX score. Higher is
+	// harder in both.
+	Score float64
+	// ReadingEase is the Flesch reading ease (0-100, higher is easier), only
+	// set when Method is "flesch-kincaid".
+	ReadingEase float64
+
+	Words     int
+	Sentences int
+	Syllables int
+	LongWords int
+}
+
+var (
+	sentenceEndReg = regexp.MustCompile(`[.!?]+(\s|$)|\n\s*\n`)
+	markupReg      = regexp.MustCompile(`[#*_>|~` + "`" + `\[\]]`)
+	vowelGroupReg  = regexp.MustCompile(`[aeiouy]+`)
+)
+
+// Readability scores the content of the given post, based on its language.
+func Readability(p *Post) ReadabilityScore {
+	lang := ""
+	if p.Language != nil {
+		lang = *p.Language
+	}
+	return ReadabilityOf(p.Content, lang)
+}
+
+// ReadabilityOf scores the given Markdown content, written in the language
+// with the given code. English, or an empty code, gets a Flesch-Kincaid
+// score; other languages get a LIX score.
+func ReadabilityOf(content, lang string) ReadabilityScore {
+	text := plainText(content)
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\''
+	})
+
+	s := ReadabilityScore{Words: len(words)}
+	if s.Words == 0 {
+		return s
+	}
+	s.Sentences = len(sentenceEndReg.FindAllStringIndex(strings.TrimSpace(text)+" ", -1))
+	if s.Sentences == 0 {
+		s.Sentences = 1
+	}
+	for _, w := range words {
+		s.Syllables += syllables(w)
+		if len([]rune(w)) > 6 {
+			s.LongWords++
+		}
+	}
+
+	wps := float64(s.Words) / float64(s.Sentences)
+	if lang == "" || strings.HasPrefix(strings.ToLower(lang), "en") {
+		spw := float64(s.Syllables) / float64(s.Words)
+		s.Method = "flesch-kincaid"
+		s.Score = 0.39*wps + 11.8*spw - 15.59
+		s.ReadingEase = 206.835 - 1.015*wps - 84.6*spw
+	} else {
+		s.Method = "lix"
+		s.Score = wps + 100*float64(s.LongWords)/float64(s.Words)
+	}
+	return s
+}
+
+// plainText strips code, link targets, and Markdown markup from content.
+func plainText(content string) string {
+	lines, _ := markdownLines(content)
+	var prose []string
+	for _, l := range lines {
+		if !l.code {
+			prose = append(prose, l.text)
+		}
+	}
+	text := strings.Join(prose, "\n")
+	text = codeSpanReg.ReplaceAllString(text, "")
+	text = inlineLink.ReplaceAllString(text, "$1")
+	return markupReg.ReplaceAllString(text, "")
+}
+
+// syllables estimates the number of syllables in an English word.
+func syllables(word string) int {
+	w := strings.ToLower(word)
+	n := len(vowelGroupReg.FindAllString(w, -1))
+	if strings.HasSuffix(w, "e") && !strings.HasSuffix(w, "le") && n > 1 {
+		n--
+	}
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
@@ -0,0 +1,23 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestReadability(t *testing.T) {
+	easy := ReadabilityOf("The cat sat on the mat. It was a big cat.", "")
+	hard := ReadabilityOf("Notwithstanding considerable institutional opposition, the committee unanimously recommended comprehensive organizational restructuring.", "en-US")
+	if easy.Method != "flesch-kincaid" || easy.Words != 11 || easy.Sentences != 2 {
+		t.Errorf("Unexpected score: %+v", easy)
+	}
+	if easy.Score >= hard.Score || easy.ReadingEase <= hard.ReadingEase {
+		t.Errorf("Expected easy text to score easier: %+v vs %+v", easy, hard)
+	}
+
+	lang := "de"
+	s := Readability(&Post{Content: "Das ist ein kurzer Satz.", Language: &lang})
+	if s.Method != "lix" || s.Score != 5 {
+		t.Errorf("Unexpected LIX score: %+v", s)
+	}
+}
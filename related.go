@@ -0,0 +1,153 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type (
+	// RelatedIndex scores the similarity between a set of posts, based on
+	// shared tags and the TF-IDF weights of the words in their content.
+	RelatedIndex struct {
+		posts   []Post
+		vectors []map[string]float64
+		tags    []map[string]bool
+		byID    map[string]int
+	}
+
+	// RelatedPost is a post with its similarity score to another post.
+	RelatedPost struct {
+		Post  Post
+		Score float64
+	}
+)
+
+// Weights of each signal in the similarity score.
+const (
+	relatedTextWeight = 0.7
+	relatedTagWeight  = 0.3
+)
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "that": true, "this": true, "with": true,
+	"you": true, "are": true, "was": true, "but": true, "not": true, "have": true,
+	"has": true, "from": true, "they": true, "his": true, "her": true, "its": true,
+	"our": true, "your": true, "can": true, "all": true, "will": true, "just": true,
+	"what": true, "when": true, "who": true, "how": true, "there": true, "their": true,
+}
+
+// NewRelatedIndex builds a RelatedIndex over a copy of the given posts, so
+// later changes to them don't affect it.
+func NewRelatedIndex(posts []Post) *RelatedIndex {
+	posts = clonePosts(posts)
+	idx := &RelatedIndex{
+		posts:   posts,
+		vectors: make([]map[string]float64, len(posts)),
+		tags:    make([]map[string]bool, len(posts)),
+		byID:    make(map[string]int, len(posts)),
+	}
+
+	df := map[string]int{}
+	tfs := make([]map[string]int, len(posts))
+	for i, p := range posts {
+		idx.byID[p.ID] = i
+		idx.tags[i] = map[string]bool{}
+		for _, t := range p.Tags {
+			idx.tags[i][strings.ToLower(t)] = true
+		}
+
+		tfs[i] = map[string]int{}
+		for _, w := range tokenize(p.Title + " " + p.Content) {
+			if tfs[i][w] == 0 {
+				df[w]++
+			}
+			tfs[i][w]++
+		}
+	}
+
+	n := float64(len(posts))
+	for i, tf := range tfs {
+		vec := make(map[string]float64, len(tf))
+		var norm float64
+		for w, count := range tf {
+			weight := float64(count) * math.Log(1+n/float64(df[w]))
+			vec[w] = weight
+			norm += weight * weight
+		}
+		norm = math.Sqrt(norm)
+		for w := range vec {
+			vec[w] /= norm
+		}
+		idx.vectors[i] = vec
+	}
+	return idx
+}
+
+// Related returns up to n posts most similar to the post with the given ID,
+// most similar first. Posts with no similarity are omitted, and there are none
+// if n isn't positive.
+func (idx *RelatedIndex) Related(id string, n int) []RelatedPost {
+	i, ok := idx.byID[id]
+	if !ok || n <= 0 {
+		return nil
+	}
+
+	var related []RelatedPost
+	for j := range idx.posts {
+		if j == i {
+			continue
+		}
+		score := relatedTextWeight*cosine(idx.vectors[i], idx.vectors[j]) +
+			relatedTagWeight*jaccard(idx.tags[i], idx.tags[j])
+		if score > 0 {
+			related = append(related, RelatedPost{Post: *idx.posts[j].Clone(), Score: score})
+		}
+	}
+	sort.SliceStable(related, func(a, b int) bool {
+		return related[a].Score > related[b].Score
+	})
+	if len(related) > n {
+		related = related[:n]
+	}
+	return related
+}
+
+func tokenize(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	toks := words[:0]
+	for _, w := range words {
+		if len([]rune(w)) > 2 && !stopWords[w] {
+			toks = append(toks, w)
+		}
+	}
+	return toks
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var dot float64
+	for w, v := range a {
+		dot += v * b[w]
+	}
+	return dot
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if b[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
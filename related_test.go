@@ -0,0 +1,45 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestRelatedIndex(t *testing.T) {
+	idx := NewRelatedIndex([]Post{
+		{ID: "go", Content: "Writing a Go client library for the Write.as API.", Tags: []string{"golang"}},
+		{ID: "go2", Content: "Testing the Go client library against WriteFreely.", Tags: []string{"golang"}},
+		{ID: "bread", Content: "Sourdough bread needs patience and a warm kitchen."},
+	})
+
+	related := idx.Related("go", 5)
+	if len(related) != 1 || related[0].Post.ID != "go2" {
+		t.Errorf("Unexpected related posts: %+v", related)
+	}
+	if res := idx.Related("missing", 5); res != nil {
+		t.Errorf("Expected no related posts, got: %+v", res)
+	}
+	for _, n := range []int{0, -1} {
+		if res := idx.Related("go", n); res != nil {
+			t.Errorf("Expected no related posts for n = %d, got: %+v", n, res)
+		}
+	}
+}
+
+func TestRelatedIndexCopies(t *testing.T) {
+	posts := []Post{
+		{ID: "go", Content: "Writing a Go client library.", Tags: []string{"golang"}},
+		{ID: "go2", Content: "Testing the Go client library.", Tags: []string{"golang"}},
+	}
+	idx := NewRelatedIndex(posts)
+	posts[1].ID = "changed"
+
+	related := idx.Related("go", 5)
+	if len(related) != 1 || related[0].Post.ID != "go2" {
+		t.Fatalf("Unexpected related posts: %+v", related)
+	}
+	related[0].Post.Tags[0] = "changed"
+	if again := idx.Related("go", 5); again[0].Post.Tags[0] != "golang" {
+		t.Errorf("Index was modified through its results: %+v", again)
+	}
+}
@@ -0,0 +1,265 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// CollisionStrategy decides what Restore does with a collection or post that
+// already exists.
+type CollisionStrategy string
+
+// Collision strategies.
+const (
+	// CollisionSkip leaves the existing collection or post alone.
+	CollisionSkip CollisionStrategy = "skip"
+	// CollisionOverwrite updates the existing collection or post.
+	CollisionOverwrite CollisionStrategy = "overwrite"
+	// CollisionRename restores under a new alias or slug, like "blog-2".
+	CollisionRename CollisionStrategy = "rename"
+)
+
+// maxRenames is the most suffixes CollisionRename tries.
+const maxRenames = 100
+
+type (
+	// RestoreOptions configures Restore.
+	RestoreOptions struct {
+		// Collision is the strategy for existing collections and posts.
+		// Defaults to CollisionSkip.
+		Collision CollisionStrategy
+	}
+
+	// RestoreResult counts what Restore did.
+	RestoreResult struct {
+		Created int
+		Updated int
+		Skipped int
+		// Renamed maps the original collection aliases and post paths
+		// ("alias/slug") to the ones they were restored as.
+		Renamed map[string]string
+	}
+)
+
+// Restore republishes a backup archive written by Backup into the
+// authenticated user's account, which may be on another instance. Posts keep
+// their slugs and creation dates, and collections their pinned posts, unless
+// they were skipped. Posts that weren't in a collection are always published
+// as new posts.
+func (c *Client) Restore(r io.Reader, opts RestoreOptions) (*RestoreResult, error) {
+	if opts.Collision == "" {
+		opts.Collision = CollisionSkip
+	}
+	colls, posts, pins, err := readBackup(r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RestoreResult{Renamed: map[string]string{}}
+	aliases := map[string]string{}
+	skipped := map[string]bool{}
+	for _, coll := range colls {
+		before := res.Skipped
+		alias, err := c.restoreCollection(&coll, opts.Collision, res)
+		if err != nil {
+			return res, fmt.Errorf("Collection %s: %v", coll.Alias, err)
+		}
+		aliases[coll.Alias] = alias
+		skipped[coll.Alias] = res.Skipped > before
+	}
+
+	for i := range posts {
+		p := &posts[i]
+		sp := &PostParams{
+			Slug:     p.Slug,
+			Title:    p.Title,
+			Content:  p.Content,
+			Font:     p.Font,
+			IsRTL:    p.RTL,
+			Language: p.Language,
+		}
+		if !p.Created.IsZero() {
+			created := p.Created
+			sp.Created = &created
+		}
+		if p.Collection == nil {
+			sp.Slug = ""
+			if _, err := c.CreatePost(sp); err != nil {
+				return res, fmt.Errorf("Post %s: %v", p.ID, err)
+			}
+			res.Created++
+			continue
+		}
+		sp.Collection = aliases[p.Collection.Alias]
+		if sp.Collection == "" {
+			sp.Collection = p.Collection.Alias
+		}
+		if err := c.restorePost(sp, p.Collection.Alias+"/"+p.Slug, opts.Collision, res); err != nil {
+			return res, fmt.Errorf("Post %s/%s: %v", p.Collection.Alias, p.Slug, err)
+		}
+	}
+
+	for _, coll := range colls {
+		if skipped[coll.Alias] || len(pins[coll.Alias]) == 0 {
+			continue
+		}
+		alias := aliases[coll.Alias]
+		var slugs []string
+		for _, slug := range pins[coll.Alias] {
+			if renamed, ok := res.Renamed[coll.Alias+"/"+slug]; ok {
+				slug = strings.TrimPrefix(renamed, alias+"/")
+			}
+			slugs = append(slugs, slug)
+		}
+		if err := c.SetPinned(alias, slugs); err != nil {
+			return res, fmt.Errorf("Collection %s: %v", coll.Alias, err)
+		}
+	}
+	return res, nil
+}
+
+// restoreCollection restores the collection, returning the alias it now has.
+func (c *Client) restoreCollection(coll *Collection, strategy CollisionStrategy, res *RestoreResult) (string, error) {
+	sp := &CollectionParams{
+		Alias:       coll.Alias,
+		Title:       coll.Title,
+		Description: coll.Description,
+		StyleSheet:  coll.StyleSheet,
+	}
+	existing, err := c.findCollection(sp.Alias)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil {
+		switch strategy {
+		case CollisionSkip:
+			res.Skipped++
+			return sp.Alias, nil
+		case CollisionOverwrite:
+			if _, err = c.UpdateCollection(sp.Alias, sp); err != nil {
+				return "", err
+			}
+			res.Updated++
+			return sp.Alias, nil
+		}
+		if sp.Alias, err = c.freeName(coll.Alias, func(alias string) (bool, error) {
+			coll, err := c.findCollection(alias)
+			return coll == nil, err
+		}); err != nil {
+			return "", err
+		}
+		res.Renamed[coll.Alias] = sp.Alias
+	}
+	if _, _, err = c.EnsureCollection(sp); err != nil {
+		return "", err
+	}
+	res.Created++
+	return sp.Alias, nil
+}
+
+// restorePost restores a collection post, originally at path.
+func (c *Client) restorePost(sp *PostParams, path string, strategy CollisionStrategy, res *RestoreResult) error {
+	var existing *Post
+	var err error
+	if sp.Slug != "" {
+		if existing, err = c.findCollectionPost(sp.Collection, sp.Slug); err != nil {
+			return err
+		}
+	}
+	if existing != nil {
+		switch strategy {
+		case CollisionSkip:
+			res.Skipped++
+			return nil
+		case CollisionOverwrite:
+			up := *sp
+			up.ID = existing.ID
+			if _, err = c.UpdatePost(&up); err != nil {
+				return err
+			}
+			res.Updated++
+			return nil
+		}
+		if sp.Slug, err = c.freeName(sp.Slug, func(slug string) (bool, error) {
+			p, err := c.findCollectionPost(sp.Collection, slug)
+			return p == nil, err
+		}); err != nil {
+			return err
+		}
+		res.Renamed[path] = sp.Collection + "/" + sp.Slug
+	}
+	if _, err = c.CreatePost(sp); err != nil {
+		return err
+	}
+	res.Created++
+	return nil
+}
+
+// freeName returns the first of name-2, name-3, and so on that's free.
+func (c *Client) freeName(name string, free func(string) (bool, error)) (string, error) {
+	for i := 2; i <= maxRenames; i++ {
+		n := fmt.Sprintf("%s-%d", name, i)
+		ok, err := free(n)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return n, nil
+		}
+	}
+	return "", fmt.Errorf("No free name for %s.", name)
+}
+
+// readBackup reads the collections, posts, and pins in a backup archive, with
+// posts ordered by creation date. Backups from before pins were included have
+// none.
+func readBackup(r io.Reader) ([]Collection, []Post, map[string][]string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Invalid backup: %v", err)
+	}
+	defer gz.Close()
+
+	var colls []Collection
+	var posts []Post
+	pins := map[string][]string{}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, nil, fmt.Errorf("Invalid backup: %v", err)
+		}
+		if !strings.HasSuffix(h.Name, ".json") {
+			continue
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		switch h.Name {
+		case backupCollectionsFile:
+			err = json.Unmarshal(data, &colls)
+		case backupPinsFile:
+			err = json.Unmarshal(data, &pins)
+		default:
+			p := Post{}
+			if err = json.Unmarshal(data, &p); err == nil {
+				posts = append(posts, p)
+			}
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("Invalid backup: %s: %v", h.Name, err)
+		}
+	}
+	sort.SliceStable(posts, func(i, j int) bool { return posts[i].Created.Before(posts[j].Created) })
+	return colls, posts, pins, nil
+}
@@ -0,0 +1,87 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRestore(t *testing.T) {
+	src := newFakeAPI(t)
+	defer src.Close()
+	src.collections["blog"] = &Collection{Alias: "blog", Title: "Blog"}
+	src.addPost("blog", Post{Slug: "hello", Title: "Hello", Content: "Hello, world."})
+	src.addPost("", Post{Content: "Anonymous."})
+
+	var buf bytes.Buffer
+	if err := src.client().Backup(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	backup := buf.Bytes()
+
+	dst := newFakeAPI(t)
+	defer dst.Close()
+	dst.collections["blog"] = &Collection{Alias: "blog", Title: "Someone's Blog"}
+	dst.addPost("blog", Post{Slug: "hello", Content: "Taken."})
+
+	c := dst.client()
+	res, err := c.Restore(bytes.NewReader(backup), RestoreOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.Created != 1 || res.Skipped != 2 || dst.collectionPost("blog", "hello").Content != "Taken." {
+		t.Errorf("Unexpected skip result: %+v", res)
+	}
+
+	res, err = c.Restore(bytes.NewReader(backup), RestoreOptions{Collision: CollisionRename})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.Renamed["blog"] != "blog-2" || dst.collectionPost("blog-2", "hello") == nil {
+		t.Errorf("Unexpected rename result: %+v", res)
+	}
+
+	res, err = c.Restore(bytes.NewReader(backup), RestoreOptions{Collision: CollisionOverwrite})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.Updated != 2 || dst.collectionPost("blog", "hello").Content != "Hello, world." || dst.collections["blog"].Title != "Blog" {
+		t.Errorf("Unexpected overwrite result: %+v", res)
+	}
+}
+
+func TestRestorePins(t *testing.T) {
+	src := newFakeAPI(t)
+	defer src.Close()
+	src.collections["blog"] = &Collection{Alias: "blog", Title: "Blog"}
+	src.addPost("blog", Post{Slug: "hello", Title: "Hello", Content: "Hello, world."})
+	src.addPost("blog", Post{Slug: "about", Title: "About", Content: "About me."})
+	sc := src.client()
+	if err := sc.SetPinned("blog", []string{"about", "hello"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := sc.Backup(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if pins := readTarGz(t, bytes.NewBuffer(buf.Bytes()))["pins.json"]; !strings.Contains(pins, `"about",`) {
+		t.Errorf("Unexpected pins.json: %s", pins)
+	}
+
+	dst := newFakeAPI(t)
+	defer dst.Close()
+	c := dst.client()
+	if _, err := c.Restore(&buf, RestoreOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	about, hello := dst.collectionPost("blog", "about"), dst.collectionPost("blog", "hello")
+	if about == nil || hello == nil || fmt.Sprint(dst.pinned["blog"]) != fmt.Sprint([]string{about.ID, hello.ID}) {
+		t.Errorf("Unexpected pins: %v", dst.pinned)
+	}
+	if slugs, _ := c.Pinned("blog"); fmt.Sprint(slugs) != "[about hello]" {
+		t.Errorf("Unexpected recorded pins: %v", slugs)
+	}
+}
@@ -0,0 +1,68 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned by Run after the component was closed.
+var ErrClosed = errors.New("Closed.")
+
+// RunState lets the Run loop of a long-running component be stopped with
+// Close, which waits for the work in progress to finish. Its zero value is
+// ready to use, so components embed one:
+//
+//	func (x *Thing) Run(ctx context.Context) error {
+//		ctx, err := x.run.Begin(ctx)
+//		if err != nil {
+//			return err
+//		}
+//		defer x.run.End()
+//		...
+//	}
+type RunState struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+	closed bool
+}
+
+// Begin starts a Run loop, returning the context it should run until. It
+// returns ErrClosed after Close, and an error if the loop is already running.
+func (s *RunState) Begin(ctx context.Context) (context.Context, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrClosed
+	}
+	if s.done != nil {
+		return nil, errors.New("Already running.")
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	return ctx, nil
+}
+
+// End marks the Run loop as finished.
+func (s *RunState) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancel()
+	close(s.done)
+	s.cancel, s.done = nil, nil
+}
+
+// Close stops the Run loop, if it's running, and waits for it to finish.
+func (s *RunState) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	return nil
+}
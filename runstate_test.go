@@ -0,0 +1,40 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestViewTrackerClose(t *testing.T) {
+	api := newFakeAPI(t)
+	defer api.Close()
+	api.addPost("", Post{ID: "a", Views: 1})
+
+	store := NewMemoryViewStore()
+	tracker := NewViewTracker(api.client(), store, time.Millisecond)
+	errs := make(chan error)
+	go func() {
+		errs <- tracker.Run(context.Background())
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	if err := tracker.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case err := <-errs:
+		if err != context.Canceled {
+			t.Errorf("Unexpected Run error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Run to have returned after Close")
+	}
+	if samples, _ := store.Samples("a", time.Time{}); len(samples) == 0 {
+		t.Errorf("Expected samples to be stored")
+	}
+	if err := tracker.Run(context.Background()); err != ErrClosed {
+		t.Errorf("Expected closed tracker not to run, got %v", err)
+	}
+}
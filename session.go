@@ -0,0 +1,81 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrNotSupported is returned for calls the instance doesn't support.
+var ErrNotSupported = errors.New("Not supported by this instance.")
+
+// Session is an access token issued to an app for the user.
+type Session struct {
+	ID       string    `json:"id"`
+	App      string    `json:"app,omitempty"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"last_used,omitempty"`
+	Current  bool      `json:"current,omitempty"`
+}
+
+// ListSessions retrieves the authenticated user's active sessions, returning
+// ErrNotSupported if the instance doesn't manage sessions.
+func (c *Client) ListSessions() ([]Session, error) {
+	sessions := &[]Session{}
+	env, err := c.get("/me/sessions", sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	status := env.Code
+	if status == http.StatusNotFound {
+		return nil, ErrNotSupported
+	}
+
+	var ok bool
+	if sessions, ok = env.Data.(*[]Session); !ok {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+	if status != http.StatusOK {
+		if c.isNotLoggedIn(status) {
+			return nil, fmt.Errorf("Not authenticated.")
+		}
+		return nil, fmt.Errorf("Problem getting sessions: %d. %v\n", status, err)
+	}
+	return *sessions, nil
+}
+
+// RevokeSession revokes the session with the given ID, making its access
+// token invalid.
+func (c *Client) RevokeSession(id string) error {
+	return c.revokeSessions(fmt.Sprintf("/me/sessions/%s", id))
+}
+
+// RevokeAllSessions revokes every session of the authenticated user, including
+// the Client's own.
+func (c *Client) RevokeAllSessions() error {
+	if err := c.revokeSessions("/me/sessions"); err != nil {
+		return err
+	}
+	c.SetToken("")
+	return nil
+}
+
+func (c *Client) revokeSessions(path string) error {
+	env, err := c.delete(path, nil)
+	if err != nil {
+		return err
+	}
+
+	status := env.Code
+	if status == http.StatusNoContent {
+		return nil
+	} else if c.isNotLoggedIn(status) {
+		return fmt.Errorf("Not authenticated.")
+	} else if status == http.StatusNotFound || status == http.StatusMethodNotAllowed {
+		return ErrNotSupported
+	}
+	return fmt.Errorf("Problem revoking session: %d. %v\n", status, err)
+}
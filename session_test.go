@@ -0,0 +1,38 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSessions(t *testing.T) {
+	sessions := []Session{{ID: "s1", App: "writeas-cli"}, {ID: "s2", Current: true}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/me/sessions":
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": http.StatusOK, "data": sessions})
+		case r.Method == "DELETE" && r.URL.Path == "/me/sessions/s1":
+			sessions = sessions[1:]
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":404}`))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	s, err := c.ListSessions()
+	if err != nil || len(s) != 2 || s[0].App != "writeas-cli" {
+		t.Fatalf("Unexpected sessions: %+v, %v", s, err)
+	}
+	if err = c.RevokeSession("s1"); err != nil || len(sessions) != 1 {
+		t.Errorf("Unexpected revoke result: %v", err)
+	}
+	if err = c.RevokeAllSessions(); err != ErrNotSupported {
+		t.Errorf("Expected unsupported error, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type (
+	// Sitemap is a sitemap.xml document for a collection, as described at
+	// https://www.sitemaps.org/protocol.html.
+	Sitemap struct {
+		XMLName xml.Name     `xml:"urlset"`
+		Xmlns   string       `xml:"xmlns,attr"`
+		URLs    []SitemapURL `xml:"url"`
+	}
+
+	// SitemapURL is a single entry in a Sitemap.
+	SitemapURL struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod,omitempty"`
+	}
+)
+
+const sitemapXmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// NewSitemap builds a Sitemap for the given collection posts, served from
+// baseURL, e.g. "https://blog.example.com/". Posts without a slug are
+// skipped.
+func NewSitemap(baseURL string, posts []Post) *Sitemap {
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	sm := &Sitemap{Xmlns: sitemapXmlns}
+
+	var latest time.Time
+	var urls []SitemapURL
+	for _, p := range posts {
+		if p.Slug == "" {
+			continue
+		}
+		mod := p.Updated
+		if mod.IsZero() {
+			mod = p.Created
+		}
+		if mod.After(latest) {
+			latest = mod
+		}
+		urls = append(urls, SitemapURL{
+			Loc:     baseURL + p.Slug,
+			LastMod: sitemapTime(mod),
+		})
+	}
+
+	sm.URLs = append([]SitemapURL{{Loc: baseURL, LastMod: sitemapTime(latest)}}, urls...)
+	return sm
+}
+
+// Write writes the Sitemap as XML to w.
+func (sm *Sitemap) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(sm)
+}
+
+// GetCollectionSitemap builds a Sitemap for the given collection. If baseURL is
+// empty, the collection's public URL is used; set it when the blog is fronted
+// by a different domain.
+func (c *Client) GetCollectionSitemap(alias, baseURL string) (*Sitemap, error) {
+	if baseURL == "" {
+		coll, err := c.GetCollection(alias)
+		if err != nil {
+			return nil, err
+		}
+		if coll.URL == "" {
+			return nil, fmt.Errorf("Collection has no public URL.")
+		}
+		baseURL = coll.URL
+	}
+
+	posts, err := c.GetCollectionPosts(alias)
+	if err != nil {
+		return nil, err
+	}
+	return NewSitemap(baseURL, *posts), nil
+}
+
+func sitemapTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,31 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSitemap(t *testing.T) {
+	updated := time.Date(2019, 1, 13, 21, 9, 0, 0, time.UTC)
+	sm := NewSitemap("https://blog.example.com", []Post{
+		{Slug: "hello-world", Created: updated.Add(-time.Hour), Updated: updated},
+		{ID: "anonymous"},
+	})
+	if len(sm.URLs) != 2 {
+		t.Fatalf("Unexpected sitemap URLs: %+v", sm.URLs)
+	}
+	if sm.URLs[1].Loc != "https://blog.example.com/hello-world" || sm.URLs[1].LastMod != "2019-01-13T21:09:00Z" {
+		t.Errorf("Unexpected post URL: %+v", sm.URLs[1])
+	}
+
+	buf := &bytes.Buffer{}
+	if err := sm.Write(buf); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "<loc>https://blog.example.com/</loc>") {
+		t.Errorf("Unexpected sitemap: %s", buf.String())
+	}
+}
@@ -0,0 +1,67 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+)
+
+const snapAsAPIURL = "https://snap.as/api"
+
+// SnapAsUploader is an ImageUploader that hosts images on Snap.as, using the
+// Client's access token.
+type SnapAsUploader struct {
+	Client *Client
+}
+
+// UploadImage implements the ImageUploader interface.
+func (u *SnapAsUploader) UploadImage(filename, contentType string, r io.Reader) (string, error) {
+	if u.Client.token == "" {
+		return "", fmt.Errorf("Not authenticated.")
+	}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filepath.Base(filename)))
+	h.Set("Content-Type", contentType)
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(part, r); err != nil {
+		return "", err
+	}
+	if err = w.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest("POST", snapAsAPIURL+"/photos/upload", body)
+	if err != nil {
+		return "", fmt.Errorf("Create request: %v", err)
+	}
+	u.Client.prepareRequest(req)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	photo := &struct {
+		URL string `json:"url"`
+	}{}
+	env, err := u.Client.doRequest(req, photo)
+	if err != nil {
+		return "", err
+	}
+
+	status := env.Code
+	if status != http.StatusCreated && status != http.StatusOK {
+		if u.Client.isNotLoggedIn(status) {
+			return "", fmt.Errorf("Not authenticated.")
+		}
+		return "", fmt.Errorf("Problem uploading image: %d. %v", status, env.ErrorMessage)
+	}
+	return photo.URL, nil
+}
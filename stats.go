@@ -0,0 +1,111 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// PostStats summarizes a set of posts.
+	PostStats struct {
+		TotalPosts   int
+		TotalViews   int64
+		TotalWords   int
+		AverageWords float64
+
+		// Tags holds tag frequencies, most used first.
+		Tags []TagCount
+		// Months holds the number of posts created each month, oldest first.
+		Months []MonthCount
+	}
+
+	// TagCount is the number of posts using a tag.
+	TagCount struct {
+		Tag   string
+		Count int
+	}
+
+	// MonthCount is the number of posts created in a month.
+	MonthCount struct {
+		Year  int
+		Month time.Month
+		Count int
+	}
+)
+
+// WordCount returns the number of words in the given content.
+func WordCount(content string) int {
+	return len(strings.Fields(content))
+}
+
+// NewPostStats computes statistics for the given posts.
+func NewPostStats(posts []Post) *PostStats {
+	s := &PostStats{TotalPosts: len(posts)}
+
+	tags := map[string]int{}
+	for _, p := range posts {
+		s.TotalViews += p.Views
+		s.TotalWords += WordCount(p.Content)
+		for _, t := range p.Tags {
+			tags[strings.ToLower(t)]++
+		}
+	}
+	if len(posts) > 0 {
+		s.AverageWords = float64(s.TotalWords) / float64(len(posts))
+	}
+
+	for t, n := range tags {
+		s.Tags = append(s.Tags, TagCount{Tag: t, Count: n})
+	}
+	sort.Slice(s.Tags, func(i, j int) bool {
+		if s.Tags[i].Count != s.Tags[j].Count {
+			return s.Tags[i].Count > s.Tags[j].Count
+		}
+		return s.Tags[i].Tag < s.Tags[j].Tag
+	})
+
+	months := GroupByMonth(posts)
+	for i := len(months) - 1; i >= 0; i-- {
+		s.Months = append(s.Months, MonthCount{
+			Year:  months[i].Year,
+			Month: months[i].Month,
+			Count: len(months[i].Posts),
+		})
+	}
+	return s
+}
+
+// Markdown renders the statistics as a Markdown report.
+func (s *PostStats) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Overview\n\n")
+	fmt.Fprintf(&b, "- Posts: %d\n", s.TotalPosts)
+	fmt.Fprintf(&b, "- Views: %d\n", s.TotalViews)
+	fmt.Fprintf(&b, "- Words: %d (%.0f per post)\n", s.TotalWords, s.AverageWords)
+
+	if len(s.Tags) > 0 {
+		fmt.Fprintf(&b, "\n## Tags\n\n")
+		for _, t := range s.Tags {
+			fmt.Fprintf(&b, "- #%s: %d\n", t.Tag, t.Count)
+		}
+	}
+	if len(s.Months) > 0 {
+		fmt.Fprintf(&b, "\n## Posts per month\n\n")
+		for _, m := range s.Months {
+			fmt.Fprintf(&b, "- %s %d: %d\n", m.Month, m.Year, m.Count)
+		}
+	}
+	return b.String()
+}
+
+// GetUserPostStats computes statistics for the authenticated user's posts.
+func (c *Client) GetUserPostStats() (*PostStats, error) {
+	posts, err := c.GetUserPosts()
+	if err != nil {
+		return nil, err
+	}
+	return NewPostStats(*posts), nil
+}
@@ -0,0 +1,25 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPostStats(t *testing.T) {
+	jan := time.Date(2019, time.January, 13, 0, 0, 0, 0, time.UTC)
+	s := NewPostStats([]Post{
+		{Content: "One two three.", Views: 10, Tags: []string{"Go", "api"}, Created: jan},
+		{Content: "One.", Views: 5, Tags: []string{"go"}, Created: jan.AddDate(0, 1, 0)},
+	})
+
+	if s.TotalPosts != 2 || s.TotalViews != 15 || s.TotalWords != 4 || s.AverageWords != 2 {
+		t.Errorf("Unexpected totals: %+v", s)
+	}
+	if len(s.Tags) != 2 || s.Tags[0] != (TagCount{"go", 2}) {
+		t.Errorf("Unexpected tags: %+v", s.Tags)
+	}
+	if len(s.Months) != 2 || s.Months[0].Month != time.January || s.Months[1].Month != time.February {
+		t.Errorf("Unexpected months: %+v", s.Months)
+	}
+}
@@ -0,0 +1,143 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// StyleReport summarizes the writing style of one or more posts, for
+	// editor UIs.
+	StyleReport struct {
+		Words     int
+		Sentences int
+
+		// OverusedWords are the most frequent words that aren't common
+		// function words, most used first.
+		OverusedWords []WordFrequency
+		// PassiveVoice holds sentences that may be written in the passive
+		// voice.
+		PassiveVoice []StyleSentence
+		// SentenceLengths is the distribution of sentence lengths, in words.
+		SentenceLengths       []SentenceLengthBucket
+		AverageSentenceLength float64
+		LongestSentence       StyleSentence
+	}
+
+	// WordFrequency is the number of times a word is used.
+	WordFrequency struct {
+		Word  string
+		Count int
+	}
+
+	// StyleSentence is a sentence from a post.
+	StyleSentence struct {
+		PostID string
+		Text   string
+		Words  int
+	}
+
+	// SentenceLengthBucket counts sentences with between Min and Max words,
+	// inclusive. Max is 0 for the last, open-ended bucket.
+	SentenceLengthBucket struct {
+		Min, Max int
+		Count    int
+	}
+)
+
+const (
+	maxOverusedWords = 10
+	// Words must be used at least this many times to be considered overused.
+	minOverusedCount = 3
+)
+
+var (
+	passiveReg = regexp.MustCompile(`(?i)\b(am|is|are|was|were|be|been|being)\s+(\w+ly\s+)?(\w+ed|born|built|done|given|known|made|seen|shown|taken|thought|told|written)\b`)
+
+	sentenceBuckets = []SentenceLengthBucket{{1, 10, 0}, {11, 20, 0}, {21, 30, 0}, {31, 40, 0}, {41, 0, 0}}
+)
+
+// NewStyleReport analyzes the writing style of the given posts.
+func NewStyleReport(posts ...Post) *StyleReport {
+	r := &StyleReport{
+		SentenceLengths: make([]SentenceLengthBucket, len(sentenceBuckets)),
+	}
+	copy(r.SentenceLengths, sentenceBuckets)
+
+	counts := map[string]int{}
+	for _, p := range posts {
+		for _, s := range splitSentences(plainText(p.Content)) {
+			words := tokenize(s)
+			n := len(strings.Fields(s))
+			if n == 0 {
+				continue
+			}
+			r.Sentences++
+			r.Words += n
+			for _, w := range words {
+				counts[w]++
+			}
+
+			ss := StyleSentence{PostID: p.ID, Text: s, Words: n}
+			if passiveReg.MatchString(s) {
+				r.PassiveVoice = append(r.PassiveVoice, ss)
+			}
+			if n > r.LongestSentence.Words {
+				r.LongestSentence = ss
+			}
+			for i := range r.SentenceLengths {
+				b := &r.SentenceLengths[i]
+				if n >= b.Min && (b.Max == 0 || n <= b.Max) {
+					b.Count++
+					break
+				}
+			}
+		}
+	}
+	if r.Sentences > 0 {
+		r.AverageSentenceLength = float64(r.Words) / float64(r.Sentences)
+	}
+
+	for w, n := range counts {
+		if n >= minOverusedCount {
+			r.OverusedWords = append(r.OverusedWords, WordFrequency{w, n})
+		}
+	}
+	sort.Slice(r.OverusedWords, func(i, j int) bool {
+		if r.OverusedWords[i].Count != r.OverusedWords[j].Count {
+			return r.OverusedWords[i].Count > r.OverusedWords[j].Count
+		}
+		return r.OverusedWords[i].Word < r.OverusedWords[j].Word
+	})
+	if len(r.OverusedWords) > maxOverusedWords {
+		r.OverusedWords = r.OverusedWords[:maxOverusedWords]
+	}
+	return r
+}
+
+// GetCollectionStyleReport analyzes the writing style of the given
+// collection's posts.
+func (c *Client) GetCollectionStyleReport(alias string) (*StyleReport, error) {
+	posts, err := c.GetCollectionPosts(alias)
+	if err != nil {
+		return nil, err
+	}
+	return NewStyleReport(*posts...), nil
+}
+
+func splitSentences(text string) []string {
+	var sentences []string
+	last := 0
+	for _, loc := range sentenceEndReg.FindAllStringIndex(text, -1) {
+		if s := strings.Join(strings.Fields(text[last:loc[1]]), " "); s != "" {
+			sentences = append(sentences, s)
+		}
+		last = loc[1]
+	}
+	if s := strings.Join(strings.Fields(text[last:]), " "); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
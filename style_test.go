@@ -0,0 +1,26 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestNewStyleReport(t *testing.T) {
+	r := NewStyleReport(
+		Post{ID: "a", Content: "The report was written quickly. Honestly, the report is fine."},
+		Post{ID: "b", Content: "We read the report. Mistakes were made by everyone involved in this long and winding process that nobody really enjoyed at all."},
+	)
+
+	if r.Sentences != 4 || r.Words != 32 {
+		t.Errorf("Unexpected totals: %d sentences, %d words", r.Sentences, r.Words)
+	}
+	if len(r.OverusedWords) != 1 || r.OverusedWords[0] != (WordFrequency{"report", 3}) {
+		t.Errorf("Unexpected overused words: %+v", r.OverusedWords)
+	}
+	if len(r.PassiveVoice) != 2 || r.PassiveVoice[1].PostID != "b" {
+		t.Errorf("Unexpected passive voice: %+v", r.PassiveVoice)
+	}
+	if r.SentenceLengths[0].Count != 3 || r.SentenceLengths[1].Count != 1 || r.LongestSentence.PostID != "b" {
+		t.Errorf("Unexpected sentence lengths: %+v", r.SentenceLengths)
+	}
+}
@@ -0,0 +1,163 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type (
+	// SnapshotStore keeps the content of each post as of its last sync, used
+	// as the base of three-way merges.
+	SnapshotStore interface {
+		// Snapshot returns the post's content at the last sync, and false
+		// if it hasn't been synced.
+		Snapshot(postID string) (string, bool, error)
+		SaveSnapshot(postID, content string) error
+	}
+
+	// MemorySnapshotStore is a SnapshotStore that keeps snapshots in memory.
+	MemorySnapshotStore struct {
+		mu        sync.Mutex
+		snapshots map[string]string
+	}
+
+	// DirSnapshotStore is a SnapshotStore that keeps each snapshot in a file
+	// named after the post ID in Dir.
+	DirSnapshotStore struct {
+		Dir string
+	}
+)
+
+// SyncResult describes what SyncFile did.
+type SyncResult string
+
+// Results of SyncFile.
+const (
+	SyncUnchanged SyncResult = "unchanged"
+	SyncPushed    SyncResult = "pushed"
+	SyncPulled    SyncResult = "pulled"
+	SyncMerged    SyncResult = "merged"
+	SyncConflict  SyncResult = "conflict"
+)
+
+// ErrSyncConflict is returned by SyncFile when the local file and the remote
+// post changed the same lines differently.
+var ErrSyncConflict = errors.New("Sync conflict: resolve the conflict markers in the file and sync again.")
+
+// SyncFile syncs the local file at path with the post with the given ID, using
+// the snapshot of the post as of the last sync as a base:
+//
+//   - if only the file changed, the post is updated with it
+//   - if only the post changed, the file is overwritten with it
+//   - if both changed, they're merged with Merge3 and both are updated
+//
+// When a merge conflicts, the file is written with conflict markers, the post
+// is left alone, and ErrSyncConflict is returned. Without a snapshot, the file
+// and post are treated as having no common base.
+func (c *Client) SyncFile(path, postID string, snapshots SnapshotStore) (SyncResult, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	local := string(data)
+	p, err := c.GetPost(postID)
+	if err != nil {
+		return "", err
+	}
+	remote := p.Content
+	base, _, err := snapshots.Snapshot(postID)
+	if err != nil {
+		return "", err
+	}
+
+	// The snapshot is the content as the API stored it, with the Client's
+	// filters and footer applied, so compare the file as it would be sent,
+	// and keep the footer out of what's written to it.
+	sp, err := c.prepareParams(&PostParams{ID: postID, Title: p.Title, Content: local}, false)
+	if err != nil {
+		return "", err
+	}
+	sent := sp.Content
+
+	var res SyncResult
+	var merged string
+	switch {
+	case ContentEqual(sent, remote):
+		res, merged = SyncUnchanged, local
+	case ContentEqual(sent, base):
+		res, merged = SyncPulled, StripFooter(remote)
+	case ContentEqual(remote, base):
+		res, merged = SyncPushed, local
+	default:
+		var conflict bool
+		merged, conflict = Merge3(StripFooter(base), local, StripFooter(remote))
+		if conflict {
+			if err = ioutil.WriteFile(path, []byte(merged), 0644); err != nil {
+				return "", err
+			}
+			return SyncConflict, ErrSyncConflict
+		}
+		res = SyncMerged
+	}
+
+	if res == SyncPulled || res == SyncMerged {
+		if err = ioutil.WriteFile(path, []byte(merged), 0644); err != nil {
+			return "", err
+		}
+	}
+	stored := remote
+	if res == SyncPushed || res == SyncMerged {
+		up, err := c.UpdatePost(&PostParams{ID: postID, Token: p.Token, Title: p.Title, Content: merged})
+		if err != nil {
+			return "", err
+		}
+		stored = up.Content
+	}
+	if err = snapshots.SaveSnapshot(postID, stored); err != nil {
+		return res, err
+	}
+	c.emit(SyncCompleted{PostID: postID, Result: res})
+	return res, nil
+}
+
+// Snapshot implements the SnapshotStore interface.
+func (s *MemorySnapshotStore) Snapshot(postID string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.snapshots[postID]
+	return content, ok, nil
+}
+
+// SaveSnapshot implements the SnapshotStore interface.
+func (s *MemorySnapshotStore) SaveSnapshot(postID, content string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.snapshots == nil {
+		s.snapshots = map[string]string{}
+	}
+	s.snapshots[postID] = content
+	return nil
+}
+
+// Snapshot implements the SnapshotStore interface.
+func (s *DirSnapshotStore) Snapshot(postID string) (string, bool, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.Dir, postID))
+	if os.IsNotExist(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	return string(data), true, nil
+}
+
+// SaveSnapshot implements the SnapshotStore interface.
+func (s *DirSnapshotStore) SaveSnapshot(postID, content string) error {
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(s.Dir, postID), []byte(content), 0600)
+}
@@ -0,0 +1,85 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "writeas-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "post.md")
+
+	api := newFakeAPI(t)
+	defer api.Close()
+	c := api.client()
+	p := api.addPost("", Post{Content: "one\ntwo\nthree\n"})
+	snapshots := &DirSnapshotStore{Dir: filepath.Join(dir, ".snapshots")}
+	snapshots.SaveSnapshot(p.ID, "one\ntwo\nthree\n")
+
+	// Both sides change different lines
+	ioutil.WriteFile(path, []byte("1\ntwo\nthree\n"), 0644)
+	api.posts[p.ID].Content = "one\ntwo\n3\n"
+	res, err := c.SyncFile(path, p.ID, snapshots)
+	if err != nil || res != SyncMerged {
+		t.Fatalf("Unexpected result: %s, %v", res, err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "1\ntwo\n3\n" || api.posts[p.ID].Content != "1\ntwo\n3\n" {
+		t.Errorf("Unexpected merge: %q, %q", data, api.posts[p.ID].Content)
+	}
+
+	// Both sides change the same line
+	ioutil.WriteFile(path, []byte("uno\ntwo\n3\n"), 0644)
+	api.posts[p.ID].Content = "ONE\ntwo\n3\n"
+	if res, err = c.SyncFile(path, p.ID, snapshots); err != ErrSyncConflict || res != SyncConflict {
+		t.Fatalf("Unexpected result: %s, %v", res, err)
+	}
+	if api.posts[p.ID].Content != "ONE\ntwo\n3\n" {
+		t.Errorf("Expected remote post to be left alone")
+	}
+	if base, _, _ := snapshots.Snapshot(p.ID); base != "1\ntwo\n3\n" {
+		t.Errorf("Expected snapshot to be kept, got %q", base)
+	}
+}
+
+func TestSyncFileFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "post.md")
+	api := newFakeAPI(t)
+	defer api.Close()
+	c := api.client(WithFooter("Thanks for reading."))
+	p := api.addPost("", Post{Content: "one\n"})
+	snapshots := &MemorySnapshotStore{}
+	snapshots.SaveSnapshot(p.ID, "one\n")
+
+	ioutil.WriteFile(path, []byte("two\n"), 0644)
+	if res, err := c.SyncFile(path, p.ID, snapshots); err != nil || res != SyncPushed {
+		t.Fatalf("Unexpected result: %s, %v", res, err)
+	}
+	if StripFooter(api.posts[p.ID].Content) == api.posts[p.ID].Content {
+		t.Fatalf("Expected the footer to be added: %q", api.posts[p.ID].Content)
+	}
+	if base, _, _ := snapshots.Snapshot(p.ID); base != api.posts[p.ID].Content {
+		t.Errorf("Unexpected snapshot %q, expected the stored content", base)
+	}
+
+	if res, err := c.SyncFile(path, p.ID, snapshots); err != nil || res != SyncUnchanged {
+		t.Fatalf("Unexpected result on second sync: %s, %v", res, err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "two\n" {
+		t.Errorf("Unexpected file: %q", data)
+	}
+
+	api.posts[p.ID].Content = AppendFooter("three\n", "Thanks for reading.")
+	if res, err := c.SyncFile(path, p.ID, snapshots); err != nil || res != SyncPulled {
+		t.Fatalf("Unexpected result after a remote edit: %s, %v", res, err)
+	}
+	if data, _ := ioutil.ReadFile(path); string(data) != "three" {
+		t.Errorf("Unexpected pulled file: %q", data)
+	}
+}
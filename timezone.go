@@ -0,0 +1,99 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timestampLayouts are the formats Write.as and WriteFreely instances have
+// used for post timestamps. Layouts without a zone are read as UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02",
+}
+
+// ParseTimestamp parses a post timestamp as returned by any Write.as or
+// WriteFreely instance, returning the time in UTC.
+func ParseTimestamp(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, l := range timestampLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Unrecognized timestamp: %q", s)
+}
+
+// UnmarshalJSON decodes a Post, accepting any timestamp format understood by
+// ParseTimestamp for its Created and Updated times.
+func (p *Post) UnmarshalJSON(data []byte) error {
+	type post Post
+	aux := struct {
+		*post
+		Created *string `json:"created"`
+		Updated *string `json:"updated"`
+	}{post: (*post)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if aux.Created != nil && *aux.Created != "" {
+		if p.Created, err = ParseTimestamp(*aux.Created); err != nil {
+			return err
+		}
+	}
+	if aux.Updated != nil && *aux.Updated != "" {
+		if p.Updated, err = ParseTimestamp(*aux.Updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatTime formats t in the given time zone, e.g. one named by the reader's
+// "America/New_York" setting. An empty zone formats t in UTC, and an empty
+// layout uses "January 2, 2006 3:04 PM MST".
+func FormatTime(t time.Time, zone, layout string) (string, error) {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return "", fmt.Errorf("Unknown time zone %q.", zone)
+	}
+	if layout == "" {
+		layout = "January 2, 2006 3:04 PM MST"
+	}
+	return t.In(loc).Format(layout), nil
+}
+
+// CreatedIn returns the time the post was created in the given time zone.
+func (p *Post) CreatedIn(zone string) (time.Time, error) {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Unknown time zone %q.", zone)
+	}
+	return p.Created.In(loc), nil
+}
+
+// SetCreatedInZone sets the post's creation time, for backdating it, from a
+// local date and time like "2019-04-01 09:30" in the named time zone like
+// "Europe/Berlin". Seconds and the time of day are optional.
+func (sp *PostParams) SetCreatedInZone(datetime, zone string) error {
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return fmt.Errorf("Unknown time zone %q.", zone)
+	}
+	for _, l := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(l, strings.TrimSpace(datetime), loc); err == nil {
+			t = t.UTC()
+			sp.Created = &t
+			return nil
+		}
+	}
+	return fmt.Errorf("Unrecognized date and time: %q", datetime)
+}
@@ -0,0 +1,46 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPostTimestamps(t *testing.T) {
+	expected := time.Date(2019, 4, 1, 7, 30, 0, 0, time.UTC)
+	for _, ts := range []string{"2019-04-01T07:30:00Z", "2019-04-01T09:30:00+02:00", "2019-04-01T07:30:00", "2019-04-01 07:30:00"} {
+		p := &Post{}
+		if err := json.Unmarshal([]byte(`{"id":"abc","created":"`+ts+`"}`), p); err != nil {
+			t.Errorf("Unexpected error for %q: %v", ts, err)
+			continue
+		}
+		if !p.Created.Equal(expected) || p.ID != "abc" {
+			t.Errorf("Unexpected post for %q: %+v", ts, p)
+		}
+	}
+
+	p := &Post{}
+	if err := json.Unmarshal([]byte(`{"created":"yesterday"}`), p); err == nil {
+		t.Errorf("Expected error for bad timestamp")
+	}
+}
+
+func TestSetCreatedInZone(t *testing.T) {
+	sp := &PostParams{}
+	if err := sp.SetCreatedInZone("2019-04-01 09:30", "Europe/Berlin"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !sp.Created.Equal(time.Date(2019, 4, 1, 7, 30, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected created time: %v", sp.Created)
+	}
+
+	s, err := FormatTime(*sp.Created, "Europe/Berlin", "2006-01-02 15:04 MST")
+	if err != nil || s != "2019-04-01 09:30 CEST" {
+		t.Errorf("Unexpected formatted time: %q, %v", s, err)
+	}
+
+	if err := sp.SetCreatedInZone("2019-04-01", "Mars/Olympus"); err == nil {
+		t.Errorf("Expected error for unknown zone")
+	}
+}
@@ -0,0 +1,67 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrInvalidToken is returned when the Client has no access token, or its
+// token was revoked or has expired.
+var ErrInvalidToken = errors.New("Access token is missing, invalid, or revoked.")
+
+// TokenInfo describes the Client's access token.
+type TokenInfo struct {
+	Valid bool
+	// User is the user the token belongs to, if it's valid.
+	User      *User
+	CheckedAt time.Time
+}
+
+// WhoAmI returns the user the Client's access token belongs to, or
+// ErrInvalidToken if it's no longer valid. It always asks the API, since a
+// cached answer could hide a token that's since been revoked.
+func (c *Client) WhoAmI() (*User, error) {
+	if c.token == "" && c.auth == nil {
+		return nil, ErrInvalidToken
+	}
+
+	u := &User{}
+	env, err := c.getFresh("/me", u)
+	if err != nil {
+		return nil, err
+	}
+
+	status := env.Code
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return nil, ErrInvalidToken
+	}
+
+	var ok bool
+	if u, ok = env.Data.(*User); !ok {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Problem getting user: %d. %v\n", status, err)
+	}
+	return u, nil
+}
+
+// IntrospectToken checks whether the Client's access token is still valid and
+// which user it belongs to, so long-running processes can detect revocation
+// before queueing work. An invalid token isn't an error; other failures, like
+// the instance being unreachable, are.
+func (c *Client) IntrospectToken() (*TokenInfo, error) {
+	info := &TokenInfo{CheckedAt: c.Now()}
+	u, err := c.WhoAmI()
+	if err == ErrInvalidToken {
+		return info, nil
+	} else if err != nil {
+		return nil, err
+	}
+	info.Valid = true
+	info.User = u
+	return info, nil
+}
@@ -0,0 +1,62 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIntrospectToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":401,"error_msg":"Invalid access token."}`))
+			return
+		}
+		w.Write([]byte(`{"code":200,"data":{"username":"writer"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL))
+	if _, err := c.WhoAmI(); err != ErrInvalidToken {
+		t.Errorf("Expected missing token to be invalid, got %v", err)
+	}
+
+	c.SetToken("good")
+	info, err := c.IntrospectToken()
+	if err != nil || !info.Valid || info.User.Username != "writer" {
+		t.Errorf("Unexpected token info: %+v, %v", info, err)
+	}
+
+	c.SetToken("revoked")
+	info, err = c.IntrospectToken()
+	if err != nil || info.Valid || info.User != nil {
+		t.Errorf("Unexpected token info: %+v, %v", info, err)
+	}
+}
+
+func TestWhoAmIUncached(t *testing.T) {
+	revoked := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if revoked {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":401,"error_msg":"Invalid access token."}`))
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=3600")
+		w.Write([]byte(`{"code":200,"data":{"username":"writer"}}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(WithBaseURL(srv.URL), WithPolicy(ReadEndpoints, Policy{CacheTTL: time.Hour}))
+	c.SetToken("good")
+	if _, err := c.WhoAmI(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	revoked = true
+	if _, err := c.WhoAmI(); err != ErrInvalidToken {
+		t.Errorf("Expected the revoked token to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// ViewTracker periodically samples the cumulative view counts of the
+	// authenticated user's posts into a ViewStore, since the API doesn't
+	// report views over time.
+	ViewTracker struct {
+		Client   *Client
+		Store    ViewStore
+		Interval time.Duration
+
+		// OnError is called with errors that occur while sampling in Run. It
+		// may be nil.
+		OnError func(error)
+
+		run RunState
+	}
+
+	// DailyViews is the number of views a post gained on a single day.
+	DailyViews struct {
+		Date  time.Time
+		Views int64
+	}
+)
+
+// DefaultTrackerInterval is the sampling interval used when a ViewTracker's
+// Interval isn't set.
+const DefaultTrackerInterval = time.Hour
+
+// NewViewTracker creates a ViewTracker that samples the given Client's posts
+// into store every interval.
+func NewViewTracker(c *Client, store ViewStore, interval time.Duration) *ViewTracker {
+	return &ViewTracker{
+		Client:   c,
+		Store:    store,
+		Interval: interval,
+	}
+}
+
+// Sample records the current view count of every post the user owns.
+func (t *ViewTracker) Sample() error {
+	posts, err := t.Client.GetUserPosts()
+	if err != nil {
+		return err
+	}
+
+	now := t.Client.Now()
+	samples := make([]ViewSample, len(*posts))
+	for i, p := range *posts {
+		samples[i] = ViewSample{PostID: p.ID, Time: now, Views: p.Views}
+	}
+	return t.Store.AddSamples(samples)
+}
+
+// Run samples views immediately, then every Interval until ctx is done or
+// Close is called.
+func (t *ViewTracker) Run(ctx context.Context) error {
+	ctx, err := t.run.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer t.run.End()
+
+	interval := t.Interval
+	if interval <= 0 {
+		interval = DefaultTrackerInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Sample(); err != nil {
+			ReportError(t.OnError, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops Run, waiting for a sample in progress to be stored first.
+func (t *ViewTracker) Close() error {
+	return t.run.Close()
+}
+
+// DailyViews returns the views the given post gained each day since the given
+// time, oldest first, suitable for charting.
+func (t *ViewTracker) DailyViews(postID string, since time.Time) ([]DailyViews, error) {
+	samples, err := t.Store.Samples(postID, since)
+	if err != nil {
+		return nil, err
+	}
+	return DailyDeltas(samples), nil
+}
+
+// DailyDeltas converts cumulative view samples, oldest first, into the number
+// of views gained each (UTC) day.
+func DailyDeltas(samples []ViewSample) []DailyViews {
+	var days []DailyViews
+	var prev int64
+	for i, vs := range samples {
+		if i == 0 {
+			prev = vs.Views
+		}
+		y, m, d := vs.Time.UTC().Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		if n := len(days); n == 0 || !days[n-1].Date.Equal(day) {
+			days = append(days, DailyViews{Date: day})
+		}
+		days[len(days)-1].Views += vs.Views - prev
+		prev = vs.Views
+	}
+	return days
+}
@@ -0,0 +1,81 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDailyDeltas(t *testing.T) {
+	day := time.Date(2019, 1, 13, 0, 0, 0, 0, time.UTC)
+	days := DailyDeltas([]ViewSample{
+		{Time: day.Add(1 * time.Hour), Views: 100},
+		{Time: day.Add(20 * time.Hour), Views: 110},
+		{Time: day.Add(30 * time.Hour), Views: 150},
+	})
+	if len(days) != 2 || days[0].Views != 10 || days[1].Views != 40 {
+		t.Errorf("Unexpected daily views: %+v", days)
+	}
+}
+
+func TestFileViewStore(t *testing.T) {
+	s := &FileViewStore{Path: filepath.Join(t.TempDir(), "views.jsonl")}
+	now := time.Now()
+	err := s.AddSamples([]ViewSample{
+		{PostID: "a", Time: now, Views: 2},
+		{PostID: "a", Time: now.Add(-time.Hour), Views: 1},
+		{PostID: "b", Time: now, Views: 5},
+	})
+	if err != nil {
+		t.Fatalf("Unable to add samples: %v", err)
+	}
+
+	samples, err := s.Samples("a", now.Add(-2*time.Hour))
+	if err != nil {
+		t.Fatalf("Unable to get samples: %v", err)
+	}
+	if len(samples) != 2 || samples[0].Views != 1 {
+		t.Errorf("Unexpected samples: %+v", samples)
+	}
+
+	ids, _ := s.PostIDs()
+	if len(ids) != 2 {
+		t.Errorf("Unexpected post IDs: %v", ids)
+	}
+
+	all, err := s.AllSamples(now.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("Unable to get samples: %v", err)
+	}
+	if len(all) != 2 || len(all["a"]) != 1 || all["a"][0].Views != 2 {
+		t.Errorf("Unexpected samples: %+v", all)
+	}
+	trending, err := Trending(s, now.Add(-2*time.Hour), 5)
+	if err != nil || len(trending) != 1 || trending[0].PostID != "a" || trending[0].Gained != 1 {
+		t.Errorf("Unexpected trending posts: %+v, %v", trending, err)
+	}
+}
+
+func TestTrending(t *testing.T) {
+	s := NewMemoryViewStore()
+	now := time.Now()
+	s.AddSamples([]ViewSample{
+		{PostID: "old-hit", Time: now.Add(-48 * time.Hour), Views: 1000},
+		{PostID: "old-hit", Time: now.Add(-23 * time.Hour), Views: 1001},
+		{PostID: "old-hit", Time: now, Views: 1003},
+		{PostID: "new", Time: now.Add(-23 * time.Hour), Views: 10},
+		{PostID: "new", Time: now, Views: 60},
+	})
+
+	trending, err := Trending(s, now.Add(-24*time.Hour), 5)
+	if err != nil {
+		t.Fatalf("Trending failed: %v", err)
+	}
+	if len(trending) != 2 || trending[0].PostID != "new" || trending[0].Gained != 50 || trending[1].Gained != 2 {
+		t.Errorf("Unexpected trending posts: %+v", trending)
+	}
+	if trending, err = Trending(s, now.Add(-24*time.Hour), -1); err != nil || trending != nil {
+		t.Errorf("Expected no trending posts for n = -1, got: %+v, %v", trending, err)
+	}
+}
@@ -0,0 +1,253 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// Translation is a post written in a particular language, as part of a
+	// group of posts that are translations of each other.
+	Translation struct {
+		PostID string `json:"post_id"`
+		Lang   string `json:"lang"`
+		URL    string `json:"url"`
+	}
+
+	// TranslationStore links posts that are translations of each other.
+	TranslationStore interface {
+		// Translations returns every post in the group containing postID,
+		// including that post, or nil if it isn't in a group.
+		Translations(postID string) ([]Translation, error)
+		// Link adds translation to the group containing original, creating
+		// the group if needed.
+		Link(original, translation Translation) error
+	}
+
+	// MemoryTranslationStore is a TranslationStore that keeps groups in
+	// memory.
+	MemoryTranslationStore struct {
+		mu     sync.Mutex
+		groups [][]Translation
+	}
+
+	// FileTranslationStore is a TranslationStore that keeps groups in a JSON
+	// file.
+	FileTranslationStore struct {
+		Path string
+
+		mu sync.Mutex
+	}
+
+	// Translator publishes translations of posts, keeping a language switcher
+	// on every post in a group up to date.
+	Translator struct {
+		Client *Client
+		Store  TranslationStore
+	}
+)
+
+const (
+	switcherStart = "<!--writeas:translations-->"
+	switcherEnd   = "<!--/writeas:translations-->"
+)
+
+// LanguageNames maps language codes to the name of each language in itself,
+// for use in language switchers.
+var LanguageNames = map[string]string{
+	"ar": "العربية", "de": "Deutsch", "en": "English", "es": "Español",
+	"fa": "فارسی", "fr": "Français", "he": "עברית", "hi": "हिन्दी",
+	"it": "Italiano", "ja": "日本語", "ko": "한국어", "nl": "Nederlands",
+	"pl": "Polski", "pt": "Português", "ru": "Русский", "sv": "Svenska",
+	"tr": "Türkçe", "uk": "Українська", "ur": "اردو", "vi": "Tiếng Việt",
+	"zh": "中文",
+}
+
+// Translate publishes sp as a translation of the post with the given ID into
+// lang, then updates the language switcher on every post in the group.
+func (t *Translator) Translate(originalID, lang string, sp *PostParams) (*Post, error) {
+	orig, err := t.Client.GetPost(originalID)
+	if err != nil {
+		return nil, err
+	}
+	origLang := ""
+	if orig.Language != nil {
+		origLang = *orig.Language
+	}
+
+	tp := *sp
+	tp.Language = &lang
+	if tp.Collection == "" && orig.Collection != nil {
+		tp.Collection = orig.Collection.Alias
+	}
+	p, err := t.Client.CreatePost(&tp)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.Store.Link(
+		Translation{PostID: orig.ID, Lang: origLang, URL: t.Client.postURL(orig)},
+		Translation{PostID: p.ID, Lang: lang, URL: t.Client.postURL(p)},
+	)
+	if err != nil {
+		return p, err
+	}
+	return p, t.UpdateSwitchers(p.ID)
+}
+
+// UpdateSwitchers rewrites the language switcher on every post in the group
+// containing the given post.
+func (t *Translator) UpdateSwitchers(postID string) error {
+	group, err := t.Store.Translations(postID)
+	if err != nil {
+		return err
+	}
+	for _, tr := range group {
+		p, err := t.Client.GetPost(tr.PostID)
+		if err != nil {
+			return err
+		}
+		content := SetLanguageSwitcher(p.Content, LanguageSwitcher(tr.Lang, group))
+		if content == p.Content {
+			continue
+		}
+		_, err = t.Client.UpdatePost(&PostParams{ID: p.ID, Title: p.Title, Content: content})
+		if err != nil {
+			return fmt.Errorf("Update %s: %v", p.ID, err)
+		}
+	}
+	return nil
+}
+
+// LanguageSwitcher renders a Markdown line linking to the translations in
+// group other than the one in the current language.
+func LanguageSwitcher(current string, group []Translation) string {
+	var links []string
+	for _, tr := range group {
+		if tr.Lang == current {
+			continue
+		}
+		name := LanguageNames[strings.ToLower(strings.SplitN(tr.Lang, "-", 2)[0])]
+		if name == "" {
+			name = tr.Lang
+		}
+		links = append(links, fmt.Sprintf("[%s](%s)", name, tr.URL))
+	}
+	if len(links) == 0 {
+		return ""
+	}
+	return "🌐 " + strings.Join(links, " · ")
+}
+
+// SetLanguageSwitcher replaces the language switcher in content, or adds it at
+// the end of the post (before any footer). An empty switcher removes it.
+func SetLanguageSwitcher(content, switcher string) string {
+	if i := strings.Index(content, switcherStart); i >= 0 {
+		if j := strings.Index(content[i:], switcherEnd); j >= 0 {
+			content = strings.TrimRight(content[:i], "\n") + "\n\n" + strings.TrimLeft(content[i+j+len(switcherEnd):], "\n")
+			content = strings.TrimRight(content, "\n")
+		}
+	}
+	if switcher == "" {
+		return content
+	}
+
+	block := switcherStart + "\n" + switcher + "\n" + switcherEnd
+	if i := strings.LastIndex(content, footerMarker); i >= 0 {
+		return strings.TrimRight(content[:i], "\n") + "\n\n" + block + "\n\n" + content[i:]
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + block
+}
+
+// Translations implements the TranslationStore interface.
+func (s *MemoryTranslationStore) Translations(postID string) ([]Translation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := findGroup(s.groups, postID); i >= 0 {
+		return append([]Translation(nil), s.groups[i]...), nil
+	}
+	return nil, nil
+}
+
+// Link implements the TranslationStore interface.
+func (s *MemoryTranslationStore) Link(original, translation Translation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups = linkTranslation(s.groups, original, translation)
+	return nil
+}
+
+// Translations implements the TranslationStore interface.
+func (s *FileTranslationStore) Translations(postID string) ([]Translation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groups, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if i := findGroup(groups, postID); i >= 0 {
+		return groups[i], nil
+	}
+	return nil, nil
+}
+
+// Link implements the TranslationStore interface.
+func (s *FileTranslationStore) Link(original, translation Translation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	groups, err := s.load()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(linkTranslation(groups, original, translation), "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.Path, data, 0600)
+}
+
+func (s *FileTranslationStore) load() ([][]Translation, error) {
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var groups [][]Translation
+	if err = json.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("Invalid translations file: %v", err)
+	}
+	return groups, nil
+}
+
+func findGroup(groups [][]Translation, postID string) int {
+	for i, g := range groups {
+		for _, tr := range g {
+			if tr.PostID == postID {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func linkTranslation(groups [][]Translation, original, translation Translation) [][]Translation {
+	i := findGroup(groups, original.PostID)
+	if i < 0 {
+		groups = append(groups, []Translation{original})
+		i = len(groups) - 1
+	}
+	if findGroup(groups[i:i+1], translation.PostID) < 0 {
+		groups[i] = append(groups[i], translation)
+	}
+	sort.SliceStable(groups[i], func(a, b int) bool {
+		return groups[i][a].Lang < groups[i][b].Lang
+	})
+	return groups
+}
@@ -0,0 +1,35 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLanguageSwitcher(t *testing.T) {
+	s := &FileTranslationStore{Path: filepath.Join(t.TempDir(), "translations.json")}
+	s.Link(Translation{"a", "en", "https://write.as/blog/hello"}, Translation{"b", "es", "https://write.as/blog/hola"})
+	s.Link(Translation{"b", "es", "https://write.as/blog/hola"}, Translation{"c", "fr-CA", "https://write.as/blog/bonjour"})
+
+	group, err := s.Translations("c")
+	if err != nil || len(group) != 3 {
+		t.Fatalf("Unexpected group: %+v, err: %v", group, err)
+	}
+
+	sw := LanguageSwitcher("en", group)
+	if sw != "🌐 [Español](https://write.as/blog/hola) · [Français](https://write.as/blog/bonjour)" {
+		t.Errorf("Unexpected switcher: %s", sw)
+	}
+
+	content := SetLanguageSwitcher(AppendFooter("Hello.", "Footer"), sw)
+	expected := "Hello.\n\n" + switcherStart + "\n" + sw + "\n" + switcherEnd + "\n\n" + footerMarker + "\nFooter"
+	if content != expected {
+		t.Errorf("Unexpected content: %q", content)
+	}
+	if res := SetLanguageSwitcher(content, sw); res != content {
+		t.Errorf("Switcher was duplicated: %q", res)
+	}
+	if res := SetLanguageSwitcher(content, ""); res != AppendFooter("Hello.", "Footer") {
+		t.Errorf("Switcher wasn't removed: %q", res)
+	}
+}
@@ -0,0 +1,87 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"sort"
+	"time"
+)
+
+// TrendingPost is a post's view growth over a recent window of time.
+type TrendingPost struct {
+	PostID string
+	// Gained is the number of views gained during the window.
+	Gained int64
+	// Growth is Gained relative to the views at the start of the window, or 0
+	// if the post had no views then.
+	Growth float64
+	// Views is the most recently sampled cumulative view count.
+	Views int64
+}
+
+// Trending ranks the posts in store by the views they gained since the given
+// time, returning up to n of them, fastest growing first. Posts that gained
+// no views are omitted, and there are none if n isn't positive.
+func Trending(store ViewStore, since time.Time, n int) ([]TrendingPost, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	all, err := trendingSamples(store, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var trending []TrendingPost
+	for id, samples := range all {
+		if len(samples) < 2 {
+			continue
+		}
+		first, last := samples[0].Views, samples[len(samples)-1].Views
+		tp := TrendingPost{PostID: id, Gained: last - first, Views: last}
+		if tp.Gained <= 0 {
+			continue
+		}
+		if first > 0 {
+			tp.Growth = float64(tp.Gained) / float64(first)
+		}
+		trending = append(trending, tp)
+	}
+
+	sort.Slice(trending, func(i, j int) bool {
+		if trending[i].Gained != trending[j].Gained {
+			return trending[i].Gained > trending[j].Gained
+		}
+		if trending[i].Growth != trending[j].Growth {
+			return trending[i].Growth > trending[j].Growth
+		}
+		return trending[i].PostID < trending[j].PostID
+	})
+	if len(trending) > n {
+		trending = trending[:n]
+	}
+	return trending, nil
+}
+
+// trendingSamples returns the samples in store taken at or after since, by
+// post ID, reading a BulkViewStore once.
+func trendingSamples(store ViewStore, since time.Time) (map[string][]ViewSample, error) {
+	if bs, ok := store.(BulkViewStore); ok {
+		return bs.AllSamples(since)
+	}
+	ids, err := store.PostIDs()
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string][]ViewSample, len(ids))
+	for _, id := range ids {
+		if all[id], err = store.Samples(id, since); err != nil {
+			return nil, err
+		}
+	}
+	return all, nil
+}
+
+// Trending ranks the tracked posts by the views they gained during the given
+// window, up to now, returning up to n of them.
+func (t *ViewTracker) Trending(window time.Duration, n int) ([]TrendingPost, error) {
+	return Trending(t.Store, t.Client.Now().Add(-window), n)
+}
@@ -0,0 +1,263 @@
+#author: Nguyễn Thái Sơn
+// Package tui provides terminal UI components for Write.as, built on
+// Bubble Tea. It's a separate package so programs that don't need a terminal
+// UI don't depend on it.
+//
+//	b := tui.NewBrowser(c, "blog")
+//	if _, err := tea.NewProgram(b).Run(); err != nil {
+//		log.Fatal(err)
+//	}
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/writeas/go-writeas"
+)
+
+type mode int
+
+const (
+	modeList mode = iota
+	modePreview
+	modeEdit
+)
+
+type (
+	// Browser is a Bubble Tea model that lists posts, previews them, and
+	// edits their content. It can be run as a program on its own or embedded
+	// in another model by forwarding messages to Update.
+	Browser struct {
+		client *writeas.Client
+		alias  string
+
+		posts  []writeas.Post
+		cursor int
+		mode   mode
+		editor textarea.Model
+		status string
+
+		// Quit is whether pressing q in the list quits the program. It's
+		// true for NewBrowser, and embedding models can turn it off to handle
+		// leaving the browser themselves.
+		Quit bool
+	}
+
+	postsMsg struct {
+		posts []writeas.Post
+		err   error
+	}
+
+	savedMsg struct {
+		post *writeas.Post
+		err  error
+	}
+)
+
+// NewBrowser returns a Browser of the posts in the collection with the given
+// alias, or of the authenticated user's posts if alias is empty.
+func NewBrowser(c *writeas.Client, alias string) *Browser {
+	editor := textarea.New()
+	editor.ShowLineNumbers = false
+	editor.CharLimit = 0
+	return &Browser{
+		client: c,
+		alias:  alias,
+		editor: editor,
+		status: "Loading posts...",
+		Quit:   true,
+	}
+}
+
+// Selected returns the post under the cursor, or nil if there are none.
+func (b *Browser) Selected() *writeas.Post {
+	if b.cursor >= len(b.posts) {
+		return nil
+	}
+	return &b.posts[b.cursor]
+}
+
+// Init implements tea.Model, loading the posts.
+func (b *Browser) Init() tea.Cmd {
+	return b.load
+}
+
+func (b *Browser) load() tea.Msg {
+	if b.alias == "" {
+		posts, err := b.client.GetUserPosts()
+		if err != nil {
+			return postsMsg{err: err}
+		}
+		return postsMsg{posts: *posts}
+	}
+	posts, err := b.client.GetCollectionPosts(b.alias)
+	if err != nil {
+		return postsMsg{err: err}
+	}
+	return postsMsg{posts: *posts}
+}
+
+// Update implements tea.Model.
+func (b *Browser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.editor.SetWidth(msg.Width)
+		b.editor.SetHeight(msg.Height - 2)
+	case postsMsg:
+		if msg.err != nil {
+			b.status = fmt.Sprintf("Couldn't load posts: %v", msg.err)
+			break
+		}
+		b.posts = msg.posts
+		if b.cursor >= len(b.posts) {
+			b.cursor = 0
+		}
+		if b.Selected() == nil {
+			b.mode = modeList
+		}
+		b.status = fmt.Sprintf("%d posts.", len(b.posts))
+	case savedMsg:
+		if msg.err != nil {
+			b.status = fmt.Sprintf("Couldn't save post: %v", msg.err)
+			break
+		}
+		for i := range b.posts {
+			if b.posts[i].ID == msg.post.ID {
+				b.posts[i] = *msg.post
+			}
+		}
+		b.mode = modePreview
+		b.status = "Saved."
+	case tea.KeyMsg:
+		return b.updateKey(msg)
+	}
+	return b, nil
+}
+
+func (b *Browser) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyCtrlC {
+		return b, tea.Quit
+	}
+
+	switch b.mode {
+	case modeList:
+		switch msg.String() {
+		case "up", "k":
+			if b.cursor > 0 {
+				b.cursor--
+			}
+		case "down", "j":
+			if b.cursor < len(b.posts)-1 {
+				b.cursor++
+			}
+		case "enter":
+			if b.Selected() != nil {
+				b.mode = modePreview
+			}
+		case "e":
+			return b, b.edit()
+		case "r":
+			b.status = "Loading posts..."
+			return b, b.load
+		case "q":
+			if b.Quit {
+				return b, tea.Quit
+			}
+		}
+	case modePreview:
+		switch msg.String() {
+		case "esc", "q":
+			b.mode = modeList
+		case "e":
+			return b, b.edit()
+		}
+	case modeEdit:
+		switch msg.Type {
+		case tea.KeyEsc:
+			b.editor.Blur()
+			b.mode = modePreview
+			b.status = "Edit canceled."
+			return b, nil
+		case tea.KeyCtrlS:
+			b.editor.Blur()
+			b.status = "Saving..."
+			return b, b.save(*b.Selected(), b.editor.Value())
+		}
+		var cmd tea.Cmd
+		b.editor, cmd = b.editor.Update(msg)
+		return b, cmd
+	}
+	return b, nil
+}
+
+func (b *Browser) edit() tea.Cmd {
+	p := b.Selected()
+	if p == nil {
+		return nil
+	}
+	b.mode = modeEdit
+	b.status = "Editing. Ctrl+S to save, Esc to cancel."
+	b.editor.SetValue(p.Content)
+	return b.editor.Focus()
+}
+
+func (b *Browser) save(p writeas.Post, content string) tea.Cmd {
+	return func() tea.Msg {
+		saved, err := b.client.UpdatePost(&writeas.PostParams{
+			ID:      p.ID,
+			Token:   p.Token,
+			Title:   p.Title,
+			Content: content,
+		})
+		return savedMsg{post: saved, err: err}
+	}
+}
+
+// View implements tea.Model.
+func (b *Browser) View() string {
+	var sb strings.Builder
+	switch b.mode {
+	case modeList:
+		for i, p := range b.posts {
+			cursor := "  "
+			if i == b.cursor {
+				cursor = "> "
+			}
+			fmt.Fprintf(&sb, "%s%s\n", cursor, postLabel(&p))
+		}
+	case modePreview:
+		p := b.Selected()
+		if p.Title != "" {
+			fmt.Fprintf(&sb, "# %s\n\n", p.Title)
+		}
+		sb.WriteString(p.Content)
+		fmt.Fprintf(&sb, "\n\n%s\n", p.URL)
+	case modeEdit:
+		sb.WriteString(b.editor.View())
+		sb.WriteString("\n")
+	}
+	sb.WriteString(b.status)
+	return sb.String()
+}
+
+// postLabel returns the title of a post for the list, or the start of its
+// content if it has none.
+func postLabel(p *writeas.Post) string {
+	label := p.Title
+	if label == "" {
+		label = strings.TrimSpace(p.Content)
+		if i := strings.IndexByte(label, '\n'); i >= 0 {
+			label = label[:i]
+		}
+		if r := []rune(label); len(r) > 60 {
+			label = string(r[:60]) + "…"
+		}
+	}
+	if label == "" {
+		label = p.ID
+	}
+	return label
+}
@@ -0,0 +1,64 @@
+#author: Nguyễn Thái Sơn
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/writeas/go-writeas"
+)
+
+func TestBrowser(t *testing.T) {
+	content := "Old content"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/collections/blog/posts":
+			fmt.Fprintf(w, `{"code":200,"data":{"alias":"blog","posts":[{"id":"a","slug":"first","title":"First"},{"id":"b","slug":"second","body":%q}]}}`, content)
+		case r.Method == "PUT" && r.URL.Path == "/api/posts/b":
+			sp := map[string]string{}
+			json.NewDecoder(r.Body).Decode(&sp)
+			content = sp["body"]
+			fmt.Fprintf(w, `{"code":200,"data":{"id":"b","slug":"second","body":%q}}`, content)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	c, err := writeas.NewClientForInstance(srv.URL, writeas.WithInsecureHTTP())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewBrowser(c, "blog")
+	b.Update(b.Init()())
+	if view := b.View(); !strings.Contains(view, "> First\n  Old content\n") {
+		t.Errorf("Unexpected list view:\n%s", view)
+	}
+
+	b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	b.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := b.View(); !strings.Contains(view, "Old content\n\n"+srv.URL+"/blog/second") {
+		t.Errorf("Unexpected preview:\n%s", view)
+	}
+
+	b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!")})
+	_, cmd := b.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	b.Update(cmd())
+	if content != "Old content!" || b.Selected().Content != content {
+		t.Errorf("Unexpected saved content: %q, %q", content, b.Selected().Content)
+	}
+	if !strings.HasSuffix(b.View(), "Saved.") {
+		t.Errorf("Unexpected view after saving:\n%s", b.View())
+	}
+
+	b.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if _, cmd = b.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Errorf("Expected q to quit")
+	}
+}
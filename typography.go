@@ -0,0 +1,89 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// markupSpanReg matches HTML tags and autolinks, link and image
+	// destinations, and link reference definitions, whose quotes and hyphens
+	// are part of attributes and URLs rather than prose.
+	markupSpanReg = regexp.MustCompile(`</?[A-Za-z!][^<>]*>|\]\([^()]*\)|(?m)^ {0,3}\[[^\]]+\]:.*$`)
+	dashReplacer  = strings.NewReplacer("---", "—", "--", "–", "...", "…")
+	dumbReplacer  = strings.NewReplacer(
+		"“", `"`, "”", `"`, "„", `"`,
+		"‘", "'", "’", "'", "‚", "'",
+		"—", "---", "–", "--", "…", "...",
+	)
+)
+
+// Smarten converts straight quotes, double and triple hyphens, and three dots
+// into their typographic equivalents (“”, ‘’, –, —, and …), outside of code
+// and markup.
+func Smarten(content string) string {
+	return eachText(content, func(s string) string {
+		return smartQuotes(dashReplacer.Replace(s))
+	})
+}
+
+// Dumb converts typographic quotes, dashes, and ellipses into plain ASCII,
+// outside of code and markup.
+func Dumb(content string) string {
+	return eachText(content, dumbReplacer.Replace)
+}
+
+// eachText calls f on each run of prose in content, like eachProse, leaving
+// out HTML tags, autolinks, and link destinations as well as code.
+func eachText(content string, f func(string) string) string {
+	return eachProse(content, func(block string) string {
+		var b strings.Builder
+		last := 0
+		for _, sp := range markupSpanReg.FindAllStringIndex(block, -1) {
+			b.WriteString(f(block[last:sp[0]]))
+			b.WriteString(block[sp[0]:sp[1]])
+			last = sp[1]
+		}
+		b.WriteString(f(block[last:]))
+		return b.String()
+	})
+}
+
+// Filters for controlling typography regardless of the server's renderer, for
+// use with SetFilters.
+var (
+	SmartTypography = FilterFunc(func(content string) (string, error) {
+		return Smarten(content), nil
+	})
+	PlainTypography = FilterFunc(func(content string) (string, error) {
+		return Dumb(content), nil
+	})
+)
+
+func smartQuotes(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i, r := range rs {
+		if r != '"' && r != '\'' {
+			b.WriteRune(r)
+			continue
+		}
+		opening := i == 0 || unicode.IsSpace(rs[i-1]) || strings.ContainsRune("([{<—–-", rs[i-1])
+		switch {
+		case r == '"' && opening:
+			b.WriteRune('“')
+		case r == '"':
+			b.WriteRune('”')
+		case opening && i+1 < len(rs) && unicode.IsDigit(rs[i+1]):
+			// Abbreviated years, like '90s
+			b.WriteRune('’')
+		case opening:
+			b.WriteRune('‘')
+		default:
+			b.WriteRune('’')
+		}
+	}
+	return b.String()
+}
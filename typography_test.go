@@ -0,0 +1,32 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"testing"
+)
+
+func TestSmarten(t *testing.T) {
+	content := "\"It's the '90s,\" she said -- 'really'... Wait---\n\n`don't \"touch\"`"
+	expected := "“It’s the ’90s,” she said – ‘really’… Wait—\n\n`don't \"touch\"`"
+	res := Smarten(content)
+	if res != expected {
+		t.Errorf("Unexpected smartened content: %s", res)
+	}
+	if dumb := Dumb(res); dumb != content {
+		t.Errorf("Unexpected plain content: %s", dumb)
+	}
+}
+
+func TestSmartenMarkup(t *testing.T) {
+	for content, expected := range map[string]string{
+		`<img src="a.png" alt='a'> "Hi"`:                        `<img src="a.png" alt='a'> “Hi”`,
+		`See [the "docs"](https://example.com/a--b "Title")...`: `See [the “docs”](https://example.com/a--b "Title")…`,
+		`![it's](img--1.png) -- <https://example.com/a--b>`:     `![it’s](img--1.png) – <https://example.com/a--b>`,
+		"[docs]: https://example.com/a--b \"Docs\"\nIt's":       "[docs]: https://example.com/a--b \"Docs\"\nIt’s",
+		`1 < 2 -- "yes"`: `1 < 2 – “yes”`,
+	} {
+		if res := Smarten(content); res != expected {
+			t.Errorf("Unexpected smartened content: %s", res)
+		}
+	}
+}
@@ -2,6 +2,8 @@
 package writeas
 
 import (
+	"fmt"
+	"net/http"
 	"time"
 )
 
@@ -20,6 +22,10 @@ type (
 		Email    string    `json:"email"`
 		Created  time.Time `json:"created"`
 
+		// EmailVerified is whether the user confirmed their email address,
+		// on instances that require it.
+		EmailVerified bool `json:"email_verified,omitempty"`
+
 		// Optional properties
 		Subscription *UserSubscription `json:"subscription"`
 	}
@@ -35,3 +41,65 @@ type (
 		Delinquent bool      `json:"is_delinquent"`
 	}
 )
+
+// GetMe retrieves the authenticated user.
+// See https://developers.write.as/docs/api/#retrieve-authenticated-user
+func (c *Client) GetMe() (*User, error) {
+	u := &User{}
+	env, err := c.get("/me", u)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if u, ok = env.Data.(*User); !ok {
+		return nil, fmt.Errorf("Wrong data returned from API.")
+	}
+
+	status := env.Code
+	if status != http.StatusOK {
+		if c.isNotLoggedIn(status) {
+			return nil, fmt.Errorf("Not authenticated.")
+		}
+		return nil, fmt.Errorf("Problem getting user: %d. %v\n", status, err)
+	}
+	return u, nil
+}
+
+// RequestEmailVerification sends a verification link to the authenticated
+// user's email address, on instances that require verified emails. It returns
+// ErrNotSupported if the instance doesn't.
+func (c *Client) RequestEmailVerification() error {
+	env, err := c.post("/me/email/verify", nil, &struct{}{})
+	if err != nil {
+		return err
+	}
+	return c.verificationError(env.Code, env.ErrorMessage)
+}
+
+// ConfirmEmail confirms the authenticated user's email address with the token
+// from their verification link.
+func (c *Client) ConfirmEmail(token string) error {
+	env, err := c.post("/me/email/confirm", map[string]string{
+		"token": token,
+	}, &struct{}{})
+	if err != nil {
+		return err
+	}
+	return c.verificationError(env.Code, env.ErrorMessage)
+}
+
+func (c *Client) verificationError(status int, msg string) error {
+	if status == http.StatusOK || status == http.StatusNoContent || status == http.StatusAccepted {
+		return nil
+	} else if c.isNotLoggedIn(status) {
+		return fmt.Errorf("Not authenticated.")
+	} else if status == http.StatusNotFound {
+		return ErrNotSupported
+	} else if status == http.StatusBadRequest || status == http.StatusGone {
+		return fmt.Errorf("Bad request: %s", msg)
+	} else if status == http.StatusTooManyRequests {
+		return &RateLimitError{Message: msg}
+	}
+	return fmt.Errorf("Problem verifying email: %d. %s", status, msg)
+}
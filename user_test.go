@@ -0,0 +1,46 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEmailVerification(t *testing.T) {
+	verified := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me":
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": http.StatusOK, "data": User{Username: "writer", EmailVerified: verified}})
+		case "/me/email/verify":
+			w.WriteHeader(http.StatusNoContent)
+		case "/me/email/confirm":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["token"] != "abc" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":400,"error_msg":"Invalid token."}`))
+				return
+			}
+			verified = true
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	if err := c.RequestEmailVerification(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := c.ConfirmEmail("wrong"); err == nil {
+		t.Errorf("Expected bad token to fail")
+	}
+	if err := c.ConfirmEmail("abc"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if u, err := c.GetMe(); err != nil || !u.EmailVerified {
+		t.Errorf("Expected verified user, got %+v, %v", u, err)
+	}
+}
@@ -0,0 +1,194 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// ViewSample is the cumulative view count of a post at a point in time.
+	ViewSample struct {
+		PostID string    `json:"post_id"`
+		Time   time.Time `json:"time"`
+		Views  int64     `json:"views"`
+	}
+
+	// ViewStore persists ViewSamples for a ViewTracker.
+	ViewStore interface {
+		// AddSamples stores the given samples.
+		AddSamples(samples []ViewSample) error
+		// Samples returns a post's samples taken at or after since, oldest
+		// first.
+		Samples(postID string, since time.Time) ([]ViewSample, error)
+		// PostIDs returns the IDs of all posts with stored samples.
+		PostIDs() ([]string, error)
+	}
+
+	// BulkViewStore is a ViewStore that can also return the samples of
+	// every post at once. Trending uses it when a store is one, to read the
+	// store once rather than once per post.
+	BulkViewStore interface {
+		ViewStore
+		// AllSamples returns the samples taken at or after since, by post
+		// ID, oldest first.
+		AllSamples(since time.Time) (map[string][]ViewSample, error)
+	}
+
+	// MemoryViewStore is a ViewStore that keeps samples in memory.
+	MemoryViewStore struct {
+		mu      sync.RWMutex
+		samples map[string][]ViewSample
+	}
+
+	// FileViewStore is a ViewStore that appends samples to a file as JSON
+	// lines, so they survive restarts.
+	FileViewStore struct {
+		Path string
+
+		mu sync.Mutex
+	}
+)
+
+// NewMemoryViewStore creates an empty MemoryViewStore.
+func NewMemoryViewStore() *MemoryViewStore {
+	return &MemoryViewStore{samples: map[string][]ViewSample{}}
+}
+
+// AddSamples implements the ViewStore interface.
+func (s *MemoryViewStore) AddSamples(samples []ViewSample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, vs := range samples {
+		s.samples[vs.PostID] = append(s.samples[vs.PostID], vs)
+	}
+	return nil
+}
+
+// Samples implements the ViewStore interface.
+func (s *MemoryViewStore) Samples(postID string, since time.Time) ([]ViewSample, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterSamples(s.samples[postID], since), nil
+}
+
+// PostIDs implements the ViewStore interface.
+func (s *MemoryViewStore) PostIDs() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]string, 0, len(s.samples))
+	for id := range s.samples {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// AllSamples implements the BulkViewStore interface.
+func (s *MemoryViewStore) AllSamples(since time.Time) (map[string][]ViewSample, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return filterAllSamples(s.samples, since), nil
+}
+
+// AddSamples implements the ViewStore interface.
+func (s *FileViewStore) AddSamples(samples []ViewSample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, vs := range samples {
+		if err = enc.Encode(vs); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// Samples implements the ViewStore interface.
+func (s *FileViewStore) Samples(postID string, since time.Time) ([]ViewSample, error) {
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return filterSamples(all[postID], since), nil
+}
+
+// PostIDs implements the ViewStore interface.
+func (s *FileViewStore) PostIDs() ([]string, error) {
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(all))
+	for id := range all {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// AllSamples implements the BulkViewStore interface.
+func (s *FileViewStore) AllSamples(since time.Time) (map[string][]ViewSample, error) {
+	all, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return filterAllSamples(all, since), nil
+}
+
+func (s *FileViewStore) load() (map[string][]ViewSample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all := map[string][]ViewSample{}
+	f, err := os.Open(s.Path)
+	if os.IsNotExist(err) {
+		return all, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var vs ViewSample
+		if err := json.Unmarshal(sc.Bytes(), &vs); err != nil {
+			return nil, err
+		}
+		all[vs.PostID] = append(all[vs.PostID], vs)
+	}
+	return all, sc.Err()
+}
+
+func filterSamples(samples []ViewSample, since time.Time) []ViewSample {
+	var res []ViewSample
+	for _, vs := range samples {
+		if !vs.Time.Before(since) {
+			res = append(res, vs)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Time.Before(res[j].Time)
+	})
+	return res
+}
+
+func filterAllSamples(all map[string][]ViewSample, since time.Time) map[string][]ViewSample {
+	res := make(map[string][]ViewSample, len(all))
+	for id, samples := range all {
+		if filtered := filterSamples(samples, since); len(filtered) > 0 {
+			res[id] = filtered
+		}
+	}
+	return res
+}
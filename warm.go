@@ -0,0 +1,68 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"sync"
+)
+
+// warmCacheWorkers is how many collections WarmCache fetches at once.
+const warmCacheWorkers = 4
+
+// WarmCache fetches the collections with the given aliases and the first page
+// of their posts, as GetCollection and GetCollectionPosts return them,
+// concurrently, so they're cached before they're needed, like when an app
+// starts. It emits a CacheWarmed event as each collection is done. The Client
+// must cache reads, with a ReadEndpoints Policy CacheTTL or WithResponseCache.
+// In low-bandwidth mode, nothing is prefetched.
+func (c *Client) WarmCache(aliases []string) *BatchResult[string] {
+	br := &BatchResult[string]{Items: make([]BatchItem[string], len(aliases))}
+	var skip error
+	if c.lowBandwidth {
+		skip = fmt.Errorf("Prefetching is disabled in low-bandwidth mode.")
+	} else if !c.cachesReads() {
+		skip = fmt.Errorf("Responses aren't cached.")
+	}
+	if skip != nil {
+		for i, alias := range aliases {
+			br.Items[i] = BatchItem[string]{Value: alias, Err: skip}
+		}
+		return br
+	}
+
+	jobs := make(chan int)
+	var mu sync.Mutex
+	done := 0
+	var wg sync.WaitGroup
+	for w := 0; w < warmCacheWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				alias := aliases[i]
+				err := c.warmCollection(alias)
+				mu.Lock()
+				br.Items[i] = BatchItem[string]{Value: alias, Err: err}
+				done++
+				c.emit(CacheWarmed{Alias: alias, Err: err, Done: done, Total: len(aliases)})
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range aliases {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return br
+}
+
+func (c *Client) warmCollection(alias string) error {
+	if _, err := c.GetCollection(alias); err != nil {
+		return fmt.Errorf("Collection %s: %v", alias, err)
+	}
+	if _, err := c.GetCollectionPosts(alias); err != nil {
+		return fmt.Errorf("Collection %s posts: %v", alias, err)
+	}
+	return nil
+}
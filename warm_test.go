@@ -0,0 +1,61 @@
+#author: Nguyễn Thái Sơn
+package writeas
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWarmCache(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		alias := strings.Split(r.URL.Path, "/")[2]
+		if alias == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":404,"error_msg":"Collection not found."}`)
+			return
+		}
+		fmt.Fprintf(w, `{"code":200,"data":{"alias":%q,"posts":[{"id":"p1","body":"Hi"}]}}`, alias)
+	}))
+	defer srv.Close()
+
+	if res := NewClient(WithBaseURL(srv.URL)).WarmCache([]string{"blog"}); res.Err() == nil {
+		t.Errorf("Expected an error warming a Client that doesn't cache")
+	}
+	if res := NewClient(WithBaseURL(srv.URL), WithLowBandwidth()).WarmCache([]string{"blog"}); res.Err() == nil || len(requests) != 0 {
+		t.Errorf("Expected nothing prefetched in low-bandwidth mode, got %v and requests %v", res.Err(), requests)
+	}
+
+	c := NewClient(WithBaseURL(srv.URL), WithPolicy(ReadEndpoints, Policy{CacheTTL: time.Minute}))
+	var events []CacheWarmed
+	c.Subscribe(func(e Event) {
+		if e, ok := e.(CacheWarmed); ok {
+			events = append(events, e)
+		}
+	})
+	res := c.WarmCache([]string{"blog", "missing", "notes"})
+	if got := fmt.Sprint(res.Succeeded()); got != "[blog notes]" {
+		t.Errorf("Unexpected warmed collections %s", got)
+	}
+	if len(res.Failed()) != 1 || res.Failed()[0].Value != "missing" {
+		t.Errorf("Expected missing to fail, got %+v", res.Failed())
+	}
+	if len(events) != 3 || events[2].Done != 3 || events[2].Total != 3 {
+		t.Errorf("Unexpected progress events %+v", events)
+	}
+
+	c.GetCollection("blog")
+	c.GetCollectionPosts("blog")
+	if requests["/collections/blog"] != 1 || requests["/collections/blog/posts"] != 1 {
+		t.Errorf("Expected warmed responses to be cached, got requests %v", requests)
+	}
+}
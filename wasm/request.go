@@ -0,0 +1,108 @@
+#author: Nguyễn Thái Sơn
+// Package wasm exposes the client to JavaScript when built with GOOS=js and
+// GOARCH=wasm, so browser extensions can publish to Write.as with this
+// package. Register adds a global writeas object whose functions return
+// Promises:
+//
+//	const post = await writeas.publish({
+//		body: window.getSelection().toString(),
+//		source: location.href,
+//		access_token: accessToken, // optional
+//	});
+//	await writeas.delete({id: post.id, token: post.token});
+//
+// The writeas-wasm command builds it:
+//
+//	GOOS=js GOARCH=wasm go build -o writeas.wasm ./cmd/writeas-wasm
+//
+// Under js/wasm, the client's requests go through the browser's Fetch API.
+package wasm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/writeas/go-writeas"
+)
+
+// request is the object passed to the JavaScript functions.
+type request struct {
+	// AccessToken authenticates the user, if given.
+	AccessToken string `json:"access_token"`
+
+	// Fields for publish
+	Title      string  `json:"title"`
+	Body       string  `json:"body"`
+	Font       string  `json:"font"`
+	Language   *string `json:"lang"`
+	RTL        *bool   `json:"rtl"`
+	Collection string  `json:"collection"`
+	// Source is the URL of the page the body was selected from, credited
+	// at the end of the post.
+	Source string `json:"source"`
+
+	// Fields for delete
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+func parseRequest(data string) (*request, error) {
+	req := &request{}
+	if err := json.Unmarshal([]byte(data), req); err != nil {
+		return nil, fmt.Errorf("Invalid arguments: %v", err)
+	}
+	return req, nil
+}
+
+// publishParams returns the PostParams for publishing the request.
+func (req *request) publishParams() (*writeas.PostParams, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, fmt.Errorf("Nothing to publish: body is empty.")
+	}
+	if req.Source != "" {
+		body += fmt.Sprintf("\n\n*Selected from <%s>.*", req.Source)
+	}
+	return &writeas.PostParams{
+		Title:      req.Title,
+		Content:    body,
+		Font:       req.Font,
+		Language:   req.Language,
+		IsRTL:      req.RTL,
+		Collection: req.Collection,
+	}, nil
+}
+
+// handle runs the named function with the request, as JSON, returning its
+// result as JSON.
+func handle(c *writeas.Client, fn, data string) (string, error) {
+	req, err := parseRequest(data)
+	if err != nil {
+		return "", err
+	}
+	if req.AccessToken != "" {
+		c.SetToken(req.AccessToken)
+	}
+
+	var res interface{}
+	switch fn {
+	case "publish":
+		sp, err := req.publishParams()
+		if err != nil {
+			return "", err
+		}
+		if res, err = c.CreatePost(sp); err != nil {
+			return "", err
+		}
+	case "delete":
+		if err = c.DeletePost(&writeas.PostParams{ID: req.ID, Token: req.Token}); err != nil {
+			return "", err
+		}
+		res = map[string]string{"id": req.ID}
+	default:
+		return "", fmt.Errorf("Unknown function %q.", fn)
+	}
+	b, err := json.Marshal(res)
+	return string(b), err
+}
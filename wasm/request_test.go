@@ -0,0 +1,58 @@
+#author: Nguyễn Thái Sơn
+package wasm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestHandle(t *testing.T) {
+	var published map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/posts":
+			if r.Header.Get("Authorization") != "Token t0k" {
+				t.Errorf("Unexpected Authorization: %q", r.Header.Get("Authorization"))
+			}
+			json.NewDecoder(r.Body).Decode(&published)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"code":201,"data":{"id":"p1","token":"edit"}}`)
+		case r.Method == "DELETE" && r.URL.Path == "/api/posts/p1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+	c, err := writeas.NewClientForInstance(srv.URL, writeas.WithInsecureHTTP())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := handle(c, "publish", `{"body":" Quoted text ","source":"https://example.com/a","access_token":"t0k"}`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(res, `"id":"p1"`) {
+		t.Errorf("Unexpected result: %s", res)
+	}
+	if published["body"] != "Quoted text\n\n*Selected from <https://example.com/a>.*" {
+		t.Errorf("Unexpected published body: %q", published["body"])
+	}
+
+	if res, err = handle(c, "delete", `{"id":"p1","token":"edit"}`); err != nil || res != `{"id":"p1"}` {
+		t.Errorf("Unexpected delete result: %s, %v", res, err)
+	}
+	if _, err = handle(c, "publish", `{"body":"  "}`); err == nil {
+		t.Errorf("Expected an error publishing an empty body")
+	}
+	if _, err = handle(c, "unpublish", `{}`); err == nil {
+		t.Errorf("Expected an error for an unknown function")
+	}
+}
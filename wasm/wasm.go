@@ -0,0 +1,48 @@
+#author: Nguyễn Thái Sơn
+//go:build js && wasm
+// +build js,wasm
+
+package wasm
+
+import (
+	"syscall/js"
+
+	"github.com/writeas/go-writeas"
+)
+
+// Register sets the global writeas object, whose functions each make their
+// requests with a new Client from newClient.
+func Register(newClient func() *writeas.Client) {
+	obj := js.Global().Get("Object").New()
+	for _, fn := range []string{"publish", "delete"} {
+		obj.Set(fn, function(newClient, fn))
+	}
+	js.Global().Set("writeas", obj)
+}
+
+// function returns the JavaScript function that runs fn, returning a Promise
+// of its result.
+func function(newClient func() *writeas.Client, fn string) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		data := "{}"
+		if len(args) > 0 {
+			data = js.Global().Get("JSON").Call("stringify", args[0]).String()
+		}
+		executor := js.FuncOf(func(this js.Value, cb []js.Value) interface{} {
+			resolve, reject := cb[0], cb[1]
+			// Requests block on the browser's event loop, so they can't run
+			// in the callback.
+			go func() {
+				res, err := handle(newClient(), fn, data)
+				if err != nil {
+					reject.Invoke(js.Global().Get("Error").New(err.Error()))
+					return
+				}
+				resolve.Invoke(js.Global().Get("JSON").Call("parse", res))
+			}()
+			return nil
+		})
+		defer executor.Release()
+		return js.Global().Get("Promise").New(executor)
+	})
+}
@@ -0,0 +1,102 @@
+#author: Nguyễn Thái Sơn
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/writeas/go-writeas"
+)
+
+// GitHubPush returns a Transform that publishes a summary of each GitHub push
+// event to the given collection, or anonymously if collection is empty. Other
+// GitHub events are skipped.
+func GitHubPush(collection string) Transform {
+	return func(r *http.Request, body []byte) (*writeas.PostParams, error) {
+		if ev := r.Header.Get("X-GitHub-Event"); ev != "push" {
+			return nil, ErrSkip
+		}
+
+		var push struct {
+			Ref        string `json:"ref"`
+			Compare    string `json:"compare"`
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+			Commits []struct {
+				ID      string `json:"id"`
+				Message string `json:"message"`
+				URL     string `json:"url"`
+			} `json:"commits"`
+		}
+		if err := json.Unmarshal(body, &push); err != nil {
+			return nil, err
+		}
+		if len(push.Commits) == 0 {
+			return nil, ErrSkip
+		}
+
+		var b strings.Builder
+		for _, c := range push.Commits {
+			msg := strings.SplitN(c.Message, "\n", 2)[0]
+			id := c.ID
+			if len(id) > 7 {
+				id = id[:7]
+			}
+			fmt.Fprintf(&b, "- [`%s`](%s) %s\n", id, c.URL, msg)
+		}
+		if push.Compare != "" {
+			fmt.Fprintf(&b, "\n[Compare changes](%s)\n", push.Compare)
+		}
+
+		return &writeas.PostParams{
+			Title:      fmt.Sprintf("Pushed to %s (%s)", push.Repository.FullName, strings.TrimPrefix(push.Ref, "refs/heads/")),
+			Content:    b.String(),
+			Collection: collection,
+		}, nil
+	}
+}
+
+// FormFields returns a Transform that publishes form submissions, using the
+// given form fields as the post title and body.
+func FormFields(titleField, bodyField, collection string) Transform {
+	return func(r *http.Request, body []byte) (*writeas.PostParams, error) {
+		vals, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, err
+		}
+		content := vals.Get(bodyField)
+		if strings.TrimSpace(content) == "" {
+			return nil, fmt.Errorf("Field %q is empty.", bodyField)
+		}
+		return &writeas.PostParams{
+			Title:      vals.Get(titleField),
+			Content:    content,
+			Collection: collection,
+		}, nil
+	}
+}
+
+// JSONFields returns a Transform that publishes JSON payloads, using the given
+// top-level string fields as the post title and body.
+func JSONFields(titleField, bodyField, collection string) Transform {
+	return func(r *http.Request, body []byte) (*writeas.PostParams, error) {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		content, _ := payload[bodyField].(string)
+		if strings.TrimSpace(content) == "" {
+			return nil, fmt.Errorf("Field %q is empty.", bodyField)
+		}
+		title, _ := payload[titleField].(string)
+		return &writeas.PostParams{
+			Title:      title,
+			Content:    content,
+			Collection: collection,
+		}, nil
+	}
+}
@@ -0,0 +1,136 @@
+#author: Nguyễn Thái Sơn
+// Package webhook provides an http.Handler that turns incoming webhooks into
+// published Write.as posts.
+//
+//	c := writeas.NewClient()
+//	c.SetToken("00000000-0000-0000-0000-000000000000")
+//	http.Handle("/hooks/github", &webhook.Handler{
+//		Client:    c,
+//		Transform: webhook.GitHubPush("blog"),
+//		Secret:    os.Getenv("GITHUB_WEBHOOK_SECRET"),
+//	})
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/writeas/go-writeas"
+)
+
+// Transform maps an incoming webhook request and its body into the post to
+// publish. It may return ErrSkip to acknowledge the webhook without
+// publishing anything. Returning neither a post nor an error is a bad
+// request.
+type Transform func(r *http.Request, body []byte) (*writeas.PostParams, error)
+
+// ErrSkip is returned by a Transform when a webhook shouldn't be published.
+var ErrSkip = errors.New("Webhook skipped.")
+
+// DefaultMaxBodySize is the largest webhook body accepted when
+// Handler.MaxBodySize isn't set.
+const DefaultMaxBodySize = 1 << 20
+
+// Handler receives webhooks, maps them through Transform, and publishes the
+// result with Client.
+type Handler struct {
+	Client    *writeas.Client
+	Transform Transform
+
+	// Secret, if set, is used to verify the HMAC-SHA256 signature of each
+	// request body, sent in the X-Hub-Signature-256 header as GitHub does.
+	Secret string
+	// MaxBodySize limits the size of accepted request bodies.
+	MaxBodySize int64
+
+	// OnPublish is called with each published post. It may be nil.
+	OnPublish func(*writeas.Post)
+	// OnError is called with any error that stops a webhook from being
+	// published. It may be nil.
+	OnError func(error)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.respond(w, http.StatusMethodNotAllowed, "", fmt.Errorf("Method %s not allowed.", r.Method))
+		return
+	}
+
+	max := h.MaxBodySize
+	if max <= 0 {
+		max = DefaultMaxBodySize
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, max))
+	if err != nil {
+		h.respond(w, http.StatusRequestEntityTooLarge, "", fmt.Errorf("Read body: %v", err))
+		return
+	}
+
+	if h.Secret != "" && !validSignature(h.Secret, r.Header.Get("X-Hub-Signature-256"), body) {
+		h.respond(w, http.StatusUnauthorized, "", fmt.Errorf("Invalid webhook signature."))
+		return
+	}
+
+	var sp *writeas.PostParams
+	if perr := writeas.SafeCall("Transform", func() { sp, err = h.Transform(r, body) }); perr != nil {
+		h.respond(w, http.StatusInternalServerError, "", perr)
+		return
+	}
+	if err == ErrSkip {
+		h.respond(w, http.StatusAccepted, "", nil)
+		return
+	} else if err != nil {
+		h.respond(w, http.StatusBadRequest, "", fmt.Errorf("Transform: %v", err))
+		return
+	} else if sp == nil {
+		h.respond(w, http.StatusBadRequest, "", fmt.Errorf("Transform: no post to publish."))
+		return
+	}
+
+	p, err := h.Client.CreatePost(sp)
+	if err != nil {
+		h.respond(w, http.StatusBadGateway, "", fmt.Errorf("Publish: %v", err))
+		return
+	}
+	if h.OnPublish != nil {
+		if err = writeas.SafeCall("OnPublish", func() { h.OnPublish(p) }); err != nil {
+			writeas.ReportError(h.OnError, err)
+		}
+	}
+	h.respond(w, http.StatusCreated, p.ID, nil)
+}
+
+func (h *Handler) respond(w http.ResponseWriter, code int, id string, err error) {
+	res := struct {
+		ID           string `json:"id,omitempty"`
+		ErrorMessage string `json:"error_msg,omitempty"`
+	}{ID: id}
+	if err != nil {
+		res.ErrorMessage = err.Error()
+		writeas.ReportError(h.OnError, err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
+
+func validSignature(secret, header string, body []byte) bool {
+	if !strings.HasPrefix(header, "sha256=") {
+		return false
+	}
+	sig, err := hex.DecodeString(header[len("sha256="):])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
@@ -0,0 +1,93 @@
+#author: Nguyễn Thái Sơn
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/writeas/go-writeas"
+)
+
+func TestGitHubPush(t *testing.T) {
+	body := `{"ref":"refs/heads/main","repository":{"full_name":"writeas/go-writeas"},"commits":[{"id":"0123456789abcdef","message":"Fix typo\n\nDetails","url":"https://github.com/c/1"}]}`
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	r.Header.Set("X-GitHub-Event", "push")
+
+	sp, err := GitHubPush("blog")(r, []byte(body))
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	if sp.Title != "Pushed to writeas/go-writeas (main)" || sp.Collection != "blog" {
+		t.Errorf("Unexpected params: %+v", sp)
+	}
+	if !strings.Contains(sp.Content, "[`0123456`](https://github.com/c/1) Fix typo") {
+		t.Errorf("Unexpected content: %s", sp.Content)
+	}
+
+	r.Header.Set("X-GitHub-Event", "star")
+	if _, err = GitHubPush("blog")(r, []byte(body)); err != ErrSkip {
+		t.Errorf("Expected ErrSkip, got: %v", err)
+	}
+}
+
+func TestHandlerSignature(t *testing.T) {
+	h := &Handler{
+		Secret:    "shh",
+		Transform: JSONFields("title", "body", ""),
+	}
+	body := `{"body":"Hi"}`
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	r.Header.Set("X-Hub-Signature-256", "sha256=00")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected unauthorized, got: %d", w.Code)
+	}
+
+	mac := hmac.New(sha256.New, []byte("shh"))
+	mac.Write([]byte(body))
+	if !validSignature("shh", "sha256="+hex.EncodeToString(mac.Sum(nil)), []byte(body)) {
+		t.Errorf("Expected valid signature")
+	}
+}
+
+func TestHandlerTransformPanic(t *testing.T) {
+	var reported error
+	h := &Handler{
+		Transform: func(r *http.Request, body []byte) (*writeas.PostParams, error) {
+			panic("boom")
+		},
+		OnError: func(err error) { reported = err },
+	}
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader("{}"))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected internal server error, got: %d", w.Code)
+	}
+	if _, ok := reported.(*writeas.PanicError); !ok {
+		t.Errorf("Expected a *writeas.PanicError, got: %v", reported)
+	}
+}
+
+func TestHandlerTransformNil(t *testing.T) {
+	h := &Handler{
+		Transform: func(r *http.Request, body []byte) (*writeas.PostParams, error) {
+			return nil, nil
+		},
+	}
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader("{}"))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected bad request, got: %d", w.Code)
+	}
+}
@@ -10,27 +10,75 @@ import (
 	"github.com/writeas/impart"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 )
 
+// Base URLs of the Write.as API, used by NewClient, NewDevClient, and
+// NewTorClient.
 const (
-	apiURL    = "https://write.as/api"
-	devAPIURL = "https://development.write.as/api"
-	torAPIURL = "http://writeas7pm7rcdqg.onion/api"
+	APIURL    = "https://write.as/api"
+	DevAPIURL = "https://development.write.as/api"
+	TorAPIURL = "http://writeas7pm7rcdqg.onion/api"
 )
 
 // Client is used to interact with the Write.as API. It can be used to make
 // authenticated or unauthenticated calls.
 type Client struct {
 	baseURL string
+	// Public URL of the instance, if not baseURL without "/api"
+	instance string
 
 	// Access token for the user making requests.
 	token string
 	// Client making requests to the API
 	client *http.Client
+	// Authenticator used instead of the token header, if set
+	auth Authenticator
+	// Whether plain HTTP instance URLs are allowed
+	insecureHTTP bool
+	// Source of the current time and new IDs, if not the defaults
+	clock Clock
+	idGen IDGenerator
+	// Latency of API calls, by endpoint
+	latency latencyTracker
 
 	// UserAgent overrides the default User-Agent header
 	UserAgent string
+
+	// Filters run on post content before publishing
+	filters []Filter
+	// Plugins hooked into the post lifecycle
+	plugins []Plugin
+	// Subscribers to the Client's events
+	events eventBus
+	// Retry, cache, and rate limit policies, by class of endpoint
+	policies map[EndpointClass]*policyState
+	cache    ResponseCache
+	memCache MemoryResponseCache
+	// Keys of the cached responses being revalidated in the background
+	revalidating sync.Map
+	// Cached addresses of the hosts dialed, with WithDialOptions
+	dns *dnsCache
+	// Posts pinned in each collection, as recorded by the Client
+	pins    PinStore
+	memPins MemoryPinStore
+	// Whether to save bandwidth, with WithLowBandwidth
+	lowBandwidth bool
+	// Linters run on post content by Lint
+	linters []Linter
+	// Defaults for newly created posts
+	defaults *PostParams
+	// Named publishing profiles
+	profiles map[string]*Profile
+	// Footer appended to every published post
+	footer string
+	// Licenses for posts in each collection
+	licenses map[string]License
+	// Number of posts claimed in each request
+	claimChunkSize int
 }
 
 // defaultHTTPTimeout is the default http.Client timeout.
@@ -41,39 +89,50 @@ const defaultHTTPTimeout = 10 * time.Second
 //
 //     c := writeas.NewClient()
 //     c.SetToken("00000000-0000-0000-0000-000000000000")
-func NewClient() *Client {
-	return &Client{
+func NewClient(opts ...Option) *Client {
+	c := &Client{
 		client:  &http.Client{Timeout: defaultHTTPTimeout},
-		baseURL: apiURL,
+		baseURL: APIURL,
 	}
+	return c.applyOptions(opts)
 }
 
 // NewTorClient creates a new API client for communicating with the Write.as
 // Tor hidden service, using the given port to connect to the local SOCKS
 // proxy.
-func NewTorClient(port int) *Client {
+func NewTorClient(port int, opts ...Option) *Client {
 	dialSocksProxy := socks.DialSocksProxy(socks.SOCKS5, fmt.Sprintf("127.0.0.1:%d", port))
 	transport := &http.Transport{Dial: dialSocksProxy}
-	return &Client{
+	c := &Client{
 		client:  &http.Client{Transport: transport},
-		baseURL: torAPIURL,
+		baseURL: TorAPIURL,
 	}
+	return c.applyOptions(opts)
 }
 
 // NewDevClient creates a new API client for development and testing. It'll
 // communicate with our development servers, and SHOULD NOT be used in
 // production.
-func NewDevClient() *Client {
-	return &Client{
+func NewDevClient(opts ...Option) *Client {
+	c := &Client{
 		client:  &http.Client{Timeout: defaultHTTPTimeout},
-		baseURL: devAPIURL,
+		baseURL: DevAPIURL,
 	}
+	return c.applyOptions(opts)
 }
 
 // SetToken sets the user token for all future Client requests. Setting this to
 // an empty string will change back to unauthenticated requests.
 func (c *Client) SetToken(token string) {
 	c.token = token
+	if ta, ok := c.auth.(*TokenAuth); ok {
+		ta.Token = token
+	}
+}
+
+// BaseURL returns the URL of the API the Client talks to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
 }
 
 // Token returns the user token currently set to the Client.
@@ -90,6 +149,16 @@ func (c *Client) get(path string, r interface{}) (*impart.Envelope, error) {
 	return c.request(method, path, nil, r)
 }
 
+// getFresh is like get, but always asks the API rather than the response
+// cache, for reads that must be current.
+func (c *Client) getFresh(path string, r interface{}) (*impart.Envelope, error) {
+	req, err := c.buildRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.sendRequest(req, r, false)
+}
+
 func (c *Client) post(path string, data, r interface{}) (*impart.Envelope, error) {
 	b := new(bytes.Buffer)
 	json.NewEncoder(b).Encode(data)
@@ -138,20 +207,63 @@ func (c *Client) buildRequest(method, path string, data io.Reader) (*http.Reques
 }
 
 func (c *Client) doRequest(r *http.Request, result interface{}) (*impart.Envelope, error) {
-	resp, err := c.client.Do(r)
+	return c.sendRequest(r, result, true)
+}
+
+// sendRequest sends r, decoding the response into result. Reads are answered
+// from the response cache if useCache is set and the Client caches them.
+func (c *Client) sendRequest(r *http.Request, result interface{}, useCache bool) (*impart.Envelope, error) {
+	class := c.endpointClass(r)
+	ps := c.policy(class)
+	key := ""
+	var cached *CachedResponse
+	if class == ReadEndpoints && useCache && c.cachesReads() {
+		// Cache errors are treated as misses, rather than failing requests
+		key = cacheKey(r)
+		cached, _ = c.responseCache().Response(key)
+		if cached != nil {
+			now := c.Now()
+			if now.Before(cached.Expires) {
+				return decodeEnvelope(http.StatusOK, bytes.NewReader(cached.Body), result)
+			}
+			if now.Before(cached.Expires.Add(ps.StaleWhileRevalidate)) {
+				c.revalidate(r, key, cached, ps)
+				return decodeEnvelope(http.StatusOK, bytes.NewReader(cached.Body), result)
+			}
+			cached.setValidators(r)
+		}
+	}
+
+	resp, err := c.send(r, ps)
 	if err != nil {
-		return nil, fmt.Errorf("Request: %v", err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if key != "" {
+		status, body, err := c.cacheResponse(key, cached, resp, ps)
+		if err != nil {
+			return nil, err
+		}
+		return decodeEnvelope(status, bytes.NewReader(body), result)
+	}
+	if class != ReadEndpoints && resp.StatusCode < 300 {
+		c.responseCache().Clear()
+	}
+	return decodeEnvelope(resp.StatusCode, resp.Body, result)
+}
+
+// decodeEnvelope decodes an API response with the given status and body,
+// decoding its data into result.
+func decodeEnvelope(status int, body io.Reader, result interface{}) (*impart.Envelope, error) {
 	env := &impart.Envelope{
-		Code: resp.StatusCode,
+		Code: status,
 	}
 	if result != nil {
 		env.Data = result
 
-		err = json.NewDecoder(resp.Body).Decode(&env)
-		if err != nil {
+		err := json.NewDecoder(body).Decode(&env)
+		if err != nil && err != io.EOF {
 			return nil, err
 		}
 	}
@@ -159,14 +271,78 @@ func (c *Client) doRequest(r *http.Request, result interface{}) (*impart.Envelop
 	return env, nil
 }
 
-func (c *Client) prepareRequest(r *http.Request) {
-	ua := c.UserAgent
-	if ua == "" {
-		ua = "go-writeas v1"
+// apiPath returns the given request path relative to the Client's base URL.
+func (c *Client) apiPath(path string) string {
+	if u, err := url.Parse(c.baseURL); err == nil {
+		return strings.TrimPrefix(path, strings.TrimRight(u.Path, "/"))
 	}
-	r.Header.Add("User-Agent", ua)
+	return path
+}
+
+func (c *Client) prepareRequest(r *http.Request) {
+	r.Header.Add("User-Agent", c.userAgent())
 	r.Header.Add("Content-Type", "application/json")
-	if c.token != "" {
+	if c.auth != nil {
+		c.auth.ApplyAuth(r)
+	} else if c.token != "" {
 		r.Header.Add("Authorization", "Token "+c.token)
 	}
 }
+
+func (c *Client) userAgent() string {
+	if c.UserAgent == "" {
+		return "go-writeas v1"
+	}
+	return c.UserAgent
+}
+
+// postURL returns the public URL of the given post, built from its
+// collection's URL and slug, or from the instance's URL and the post ID.
+func (c *Client) postURL(p *Post) string {
+	if p.Collection != nil && p.Slug != "" {
+		if p.Collection.URL != "" {
+			return strings.TrimRight(p.Collection.URL, "/") + "/" + p.Slug
+		}
+		return c.instanceURL() + "/" + p.Collection.Alias + "/" + p.Slug
+	}
+	return c.instanceURL() + "/" + p.ID
+}
+
+// fillPostURL sets the URL of a post the API returned without one. coll is
+// the collection the post was requested from, if any, for responses that
+// don't include it.
+func (c *Client) fillPostURL(p *Post, coll *Collection) {
+	if p.URL != "" {
+		return
+	}
+	if p.Collection == nil && coll != nil {
+		inColl := *p
+		inColl.Collection = coll
+		p.URL = c.postURL(&inColl)
+		return
+	}
+	p.URL = c.postURL(p)
+}
+
+func (c *Client) fillPostURLs(posts []Post, coll *Collection) {
+	for i := range posts {
+		c.fillPostURL(&posts[i], coll)
+	}
+}
+
+// collectionURL returns the public URL of the given collection.
+func (c *Client) collectionURL(coll *Collection) string {
+	if coll.URL != "" {
+		return coll.URL
+	}
+	return c.instanceURL() + "/" + coll.Alias + "/"
+}
+
+// instanceURL returns the base URL of the instance the Client talks to,
+// including any path prefix it's served under.
+func (c *Client) instanceURL() string {
+	if c.instance != "" {
+		return c.instance
+	}
+	return strings.TrimSuffix(c.baseURL, "/api")
+}